@@ -3,6 +3,7 @@ module github.com/drathveloper/go-cloud-gateway
 go 1.26.0
 
 require (
+	github.com/andybalholm/brotli v1.2.0
 	github.com/go-playground/validator/v10 v10.30.3
 	github.com/stretchr/testify v1.11.1
 	golang.org/x/net v0.56.0
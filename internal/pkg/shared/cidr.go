@@ -0,0 +1,126 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// ErrInvalidCIDR is returned when a network of a CIDR set is not valid.
+var ErrInvalidCIDR = errors.New("invalid CIDR")
+
+// CIDRSet is a set of IPv4 and IPv6 networks.
+//
+// The networks are stored in a binary prefix tree per address family, so a lookup walks at most
+// one node per address bit, whatever the number of networks in the set.
+type CIDRSet struct {
+	v4   *cidrNode
+	v6   *cidrNode
+	size int
+}
+
+type cidrNode struct {
+	children [2]*cidrNode
+	terminal bool
+}
+
+// ParseCIDRSet creates a CIDRSet from the given networks. A network is either a CIDR, like
+// 10.0.0.0/8 or 2001:db8::/32, or a single IP address, which is the same as a /32 or a /128.
+//
+// IPv4-mapped IPv6 networks, like ::ffff:10.0.0.0/104, are stored as IPv4 networks.
+func ParseCIDRSet(networks []string) (*CIDRSet, error) {
+	set := &CIDRSet{v4: &cidrNode{}, v6: &cidrNode{}}
+	for _, network := range networks {
+		prefix, err := parseNetwork(strings.TrimSpace(network))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCIDR, network)
+		}
+		set.add(prefix)
+		set.size++
+	}
+	return set, nil
+}
+
+func parseNetwork(network string) (netip.Prefix, error) {
+	if !strings.Contains(network, "/") {
+		addr, err := netip.ParseAddr(network)
+		if err != nil {
+			return netip.Prefix{}, err //nolint:wrapcheck
+		}
+		addr = addr.WithZone("")
+		return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+	}
+	prefix, err := netip.ParsePrefix(network)
+	if err != nil {
+		return netip.Prefix{}, err //nolint:wrapcheck
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+func (s *CIDRSet) add(prefix netip.Prefix) {
+	node := s.root(prefix.Addr())
+	octets := prefix.Addr().AsSlice()
+	for i := range prefix.Bits() {
+		if node.terminal {
+			return
+		}
+		bit := octets[i/8] >> (7 - i%8) & 1
+		if node.children[bit] == nil {
+			node.children[bit] = &cidrNode{}
+		}
+		node = node.children[bit]
+	}
+	// The network covers the ones nested in it, which are no longer needed.
+	node.terminal = true
+	node.children = [2]*cidrNode{}
+}
+
+func (s *CIDRSet) root(addr netip.Addr) *cidrNode {
+	if addr.Is4() {
+		return s.v4
+	}
+	return s.v6
+}
+
+// Contains checks if the given address belongs to any network of the set. IPv4-mapped IPv6
+// addresses are checked as IPv4 addresses.
+func (s *CIDRSet) Contains(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap()
+	node := s.root(addr)
+	octets := addr.AsSlice()
+	for i := range addr.BitLen() {
+		if node.terminal {
+			return true
+		}
+		node = node.children[octets[i/8]>>(7-i%8)&1]
+		if node == nil {
+			return false
+		}
+	}
+	return node.terminal
+}
+
+// Len returns the number of networks the set was created from.
+func (s *CIDRSet) Len() int {
+	return s.size
+}
+
+// ParseRemoteAddr parses a remote address, either a host:port pair, like http.Request.RemoteAddr,
+// or a bare IP address, like gateway.Request.RemoteAddr. The zone of the address is dropped.
+func ParseRemoteAddr(remoteAddr string) (netip.Addr, bool) {
+	if addrPort, err := netip.ParseAddrPort(remoteAddr); err == nil {
+		return addrPort.Addr().WithZone(""), true
+	}
+	addr, err := netip.ParseAddr(remoteAddr)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.WithZone(""), true
+}
@@ -0,0 +1,25 @@
+package shared_test
+
+import (
+	"net/netip"
+	"strconv"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+func BenchmarkCIDRSet_Contains(b *testing.B) {
+	networks := make([]string, 0, 10000)
+	for i := range 10000 {
+		networks = append(networks, "10."+strconv.Itoa(i/256)+"."+strconv.Itoa(i%256)+".0/24")
+	}
+	set, err := shared.ParseCIDRSet(networks)
+	if err != nil {
+		b.Fatalf("unexpected error %v", err)
+	}
+	addr := netip.MustParseAddr("10.39.15.200")
+	b.ReportAllocs()
+	for b.Loop() {
+		set.Contains(addr)
+	}
+}
@@ -0,0 +1,126 @@
+package shared_test
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+func TestParseCIDRSet(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		networks    []string
+		expectedLen int
+	}{
+		{
+			name:        "parse should succeed when networks are valid",
+			networks:    []string{"10.0.0.0/8", "192.168.1.7", "2001:db8::/32", "::1", "::ffff:172.16.0.0/108"},
+			expectedLen: 5,
+			expectedErr: nil,
+		},
+		{
+			name:        "parse should succeed when networks are empty",
+			networks:    nil,
+			expectedLen: 0,
+			expectedErr: nil,
+		},
+		{
+			name:        "parse should fail when a network is not valid",
+			networks:    []string{"10.0.0.0/8", "10.0.0.0/33"},
+			expectedErr: errors.New("invalid CIDR: 10.0.0.0/33"),
+		},
+		{
+			name:        "parse should fail when a network is not an address",
+			networks:    []string{"localhost"},
+			expectedErr: errors.New("invalid CIDR: localhost"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := shared.ParseCIDRSet(tt.networks)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && set.Len() != tt.expectedLen {
+				t.Errorf("expected len %d actual %d", tt.expectedLen, set.Len())
+			}
+		})
+	}
+}
+
+func TestCIDRSet_Contains(t *testing.T) {
+	set, err := shared.ParseCIDRSet([]string{
+		"10.0.0.0/8", "10.1.0.0/16", "192.168.1.7", "2001:db8::/32", "::1", "::ffff:172.16.0.0/108",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tests := []struct {
+		name     string
+		addr     string
+		expected bool
+	}{
+		{name: "ipv4 address within a network", addr: "10.200.3.4", expected: true},
+		{name: "ipv4 address within a nested network", addr: "10.1.2.3", expected: true},
+		{name: "ipv4 address outside the networks", addr: "11.0.0.1", expected: false},
+		{name: "single ipv4 address", addr: "192.168.1.7", expected: true},
+		{name: "neighbour of a single ipv4 address", addr: "192.168.1.8", expected: false},
+		{name: "ipv4 address within an ipv4-mapped network", addr: "172.16.9.1", expected: true},
+		{name: "ipv4-mapped address within an ipv4 network", addr: "::ffff:10.0.0.1", expected: true},
+		{name: "ipv6 address within a network", addr: "2001:db8:1::7", expected: true},
+		{name: "ipv6 address outside the networks", addr: "2001:db9::1", expected: false},
+		{name: "single ipv6 address", addr: "::1", expected: true},
+		{name: "ipv6 address sharing the bits of an ipv4 network", addr: "a00::1", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := set.Contains(netip.MustParseAddr(tt.addr)); actual != tt.expected {
+				t.Errorf("expected %v actual %v", tt.expected, actual)
+			}
+		})
+	}
+	if set.Contains(netip.Addr{}) {
+		t.Error("expected invalid address not to be contained")
+	}
+}
+
+func TestCIDRSet_ContainsAll(t *testing.T) {
+	set, err := shared.ParseCIDRSet([]string{"0.0.0.0/0", "::/0"})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, addr := range []string{"1.2.3.4", "255.255.255.255", "2001:db8::1"} {
+		if !set.Contains(netip.MustParseAddr(addr)) {
+			t.Errorf("expected %s to be contained", addr)
+		}
+	}
+}
+
+func TestParseRemoteAddr(t *testing.T) {
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   string
+		expectedOk bool
+	}{
+		{name: "host and port", remoteAddr: "203.0.113.7:4321", expected: "203.0.113.7", expectedOk: true},
+		{name: "ipv6 host and port", remoteAddr: "[2001:db8::1]:5555", expected: "2001:db8::1", expectedOk: true},
+		{name: "bare ip address", remoteAddr: "203.0.113.7", expected: "203.0.113.7", expectedOk: true},
+		{name: "ip address with zone", remoteAddr: "fe80::1%eth0", expected: "fe80::1", expectedOk: true},
+		{name: "not an address", remoteAddr: "unknown", expected: "invalid IP", expectedOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := shared.ParseRemoteAddr(tt.remoteAddr)
+
+			if ok != tt.expectedOk || actual.String() != tt.expected {
+				t.Errorf("expected %s %v actual %s %v", tt.expected, tt.expectedOk, actual, ok)
+			}
+		})
+	}
+}
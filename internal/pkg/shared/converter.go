@@ -19,6 +19,9 @@ var ErrRequiredIntValue = errors.New("value is required to be a valid int")
 // ErrRequiredSliceValue is returned when a value is required to be a slice but is not.
 var ErrRequiredSliceValue = errors.New("value is required to be a valid slice")
 
+// ErrRequiredMapValue is returned when a value is required to be a map but is not.
+var ErrRequiredMapValue = errors.New("value is required to be a valid map")
+
 // ErrRequiredDurationValue is returned when a value is required to be a duration but is not.
 var ErrRequiredDurationValue = errors.New("value is required to be a valid duration")
 
@@ -76,6 +79,27 @@ func ConvertToStringSlice(val any) ([]string, error) {
 	return valStrSlice, nil
 }
 
+// ConvertToMap converts the given value to a map of strings to any.
+//
+// The value can be a map[string]any, as decoded from JSON and YAML, or a map[string]string.
+func ConvertToMap(val any) (map[string]any, error) {
+	if val == nil {
+		return nil, ErrRequiredValue
+	}
+	switch value := val.(type) {
+	case map[string]any:
+		return value, nil
+	case map[string]string:
+		result := make(map[string]any, len(value))
+		for key, item := range value {
+			result[key] = item
+		}
+		return result, nil
+	default:
+		return nil, ErrRequiredMapValue
+	}
+}
+
 // ConvertSlice converts the given value to a slice of the given type.
 func ConvertSlice[T any](sliceAny []any) ([]T, error) {
 	result := make([]T, 0, len(sliceAny))
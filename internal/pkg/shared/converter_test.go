@@ -310,3 +310,49 @@ func TestConvertToDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToMap(t *testing.T) {
+	tests := []struct {
+		input       any
+		expectedErr error
+		expected    map[string]any
+		name        string
+	}{
+		{
+			name:        "convert map to map should succeed",
+			input:       map[string]any{"key": 1},
+			expected:    map[string]any{"key": 1},
+			expectedErr: nil,
+		},
+		{
+			name:        "convert string map to map should succeed",
+			input:       map[string]string{"key": "value"},
+			expected:    map[string]any{"key": "value"},
+			expectedErr: nil,
+		},
+		{
+			name:        "convert nil to map should return error",
+			input:       nil,
+			expected:    nil,
+			expectedErr: errors.New("value is required"),
+		},
+		{
+			name:        "convert other type to map should return error",
+			input:       "someStr",
+			expected:    nil,
+			expectedErr: errors.New("value is required to be a valid map"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := shared.ConvertToMap(tt.input)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected %s actual %s", tt.expectedErr, err)
+			}
+			if !reflect.DeepEqual(tt.expected, result) {
+				t.Errorf("expected %v actual %v", tt.expected, result)
+			}
+		})
+	}
+}
@@ -30,9 +30,14 @@ func SetXForwardedHeaders(request *http.Request) {
 		request.Header.Del(xForwardedForHeader)
 	}
 	request.Header.Set(xForwardedHostHeader, request.Host)
+	request.Header.Set(xForwardedProtoHeader, RequestScheme(request))
+}
+
+// RequestScheme returns the scheme the client used for the request to the gateway, https when the
+// connection is TLS and http otherwise.
+func RequestScheme(request *http.Request) string {
 	if request.TLS == nil {
-		request.Header.Set(xForwardedProtoHeader, "http")
-	} else {
-		request.Header.Set(xForwardedProtoHeader, "https")
+		return "http"
 	}
+	return "https"
 }
@@ -0,0 +1,24 @@
+package shared
+
+import "strings"
+
+// LookupKey returns the value under the given key of the values. The nested maps are looked up with dotted keys,
+// like 'sla.tier' for the key 'tier' of the map under 'sla'. A key containing dots is found as is first.
+func LookupKey(values map[string]any, key string) (any, bool) {
+	if value, ok := values[key]; ok {
+		return value, true
+	}
+	for i := strings.IndexByte(key, '.'); i >= 0; {
+		if nested, ok := values[key[:i]].(map[string]any); ok {
+			if value, found := LookupKey(nested, key[i+1:]); found {
+				return value, true
+			}
+		}
+		next := strings.IndexByte(key[i+1:], '.')
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return nil, false
+}
@@ -0,0 +1,46 @@
+package shared_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+func TestLookupKey(t *testing.T) {
+	values := map[string]any{
+		"team":     "payments",
+		"sla":      map[string]any{"tier": 1, "limits": map[string]any{"rps": 100}},
+		"a.b":      "dotted",
+		"a":        map[string]any{"c": "nested"},
+		"no-map":   "value",
+		"empty":    nil,
+		"sla.tier": nil,
+	}
+	tests := []struct {
+		expected   any
+		name       string
+		key        string
+		expectedOk bool
+	}{
+		{name: "lookup should find top level key", key: "team", expected: "payments", expectedOk: true},
+		{name: "lookup should find nested key", key: "sla.limits.rps", expected: 100, expectedOk: true},
+		{name: "lookup should find nested map", key: "sla.limits", expected: map[string]any{"rps": 100}, expectedOk: true},
+		{name: "lookup should find dotted key as is first", key: "a.b", expected: "dotted", expectedOk: true},
+		{name: "lookup should find nested key next to dotted key", key: "a.c", expected: "nested", expectedOk: true},
+		{name: "lookup should find nil value", key: "empty", expected: nil, expectedOk: true},
+		{name: "lookup should prefer dotted key with nil value", key: "sla.tier", expected: nil, expectedOk: true},
+		{name: "lookup should not find missing key", key: "owner", expectedOk: false},
+		{name: "lookup should not find key under a value", key: "no-map.key", expectedOk: false},
+		{name: "lookup should not find missing nested key", key: "sla.owner", expectedOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := shared.LookupKey(values, tt.key)
+
+			if ok != tt.expectedOk || !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v %v actual %v %v", tt.expected, tt.expectedOk, actual, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidPathTemplate is returned when a path template cannot be compiled.
+var ErrInvalidPathTemplate = errors.New("invalid path template")
+
+var (
+	errInvalidVariableName = errors.New("invalid variable name")
+	errDuplicateVariable   = errors.New("duplicate variable")
+)
+
+// PathTemplate is a path pattern capturing variables.
+//
+// On top of the PathMatcher special characters, a path template can contain the following variables:
+//
+// 1. '{name}' captures the text of a segment, which must not be empty.
+// 2. '{name:regex}' captures the text matching the regex, which should not match '/'.
+//
+// A variable can be a whole segment, like '/users/{id}', or a part of it, like '/files/{name}.{ext}'.
+// Variable names are made of letters, digits and '_', and must not start with a digit.
+type PathTemplate struct {
+	pattern *regexp.Regexp
+	names   []string
+}
+
+// IsPathTemplate checks if the given path pattern contains variables.
+func IsPathTemplate(pattern string) bool {
+	return strings.ContainsRune(pattern, '{')
+}
+
+// CompilePathTemplate compiles the given path template.
+func CompilePathTemplate(template string) (*PathTemplate, error) {
+	segments, ok := splitTemplateSegments(strings.Trim(template, "/"))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s: unbalanced braces", ErrInvalidPathTemplate, template)
+	}
+	var expr strings.Builder
+	var names []string
+	var err error
+	expr.WriteByte('^')
+	for _, segment := range segments {
+		if segment == "**" {
+			expr.WriteString("(?:/.*)?")
+			continue
+		}
+		expr.WriteByte('/')
+		if names, err = writeTemplateSegment(&expr, segment, names); err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPathTemplate, template, err)
+		}
+	}
+	expr.WriteByte('$')
+	pattern, err := regexp.Compile(expr.String())
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", ErrInvalidPathTemplate, template, err)
+	}
+	return &PathTemplate{pattern: pattern, names: names}, nil
+}
+
+// Match matches the path with the template, returning the captured variables. Like PathMatcher, the
+// leading and trailing '/' of the path are not significant.
+func (t *PathTemplate) Match(path string) (map[string]string, bool) {
+	matches := t.pattern.FindStringSubmatch("/" + strings.Trim(path, "/"))
+	if matches == nil {
+		return nil, false
+	}
+	variables := make(map[string]string, len(t.names))
+	for _, name := range t.names {
+		variables[name] = matches[t.pattern.SubexpIndex(name)]
+	}
+	return variables, true
+}
+
+// splitTemplateSegments splits the template by '/', except within variables, since their regex
+// may contain '/'. It returns false when the braces are not balanced.
+func splitTemplateSegments(template string) ([]string, bool) {
+	var segments []string
+	depth, start := 0, 0
+	for i := range len(template) {
+		switch template[i] {
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return nil, false
+			}
+			depth--
+		case '/':
+			if depth == 0 {
+				segments = append(segments, template[start:i])
+				start = i + 1
+			}
+		default:
+		}
+	}
+	return append(segments, template[start:]), depth == 0
+}
+
+// writeTemplateSegment writes the regex of a segment, whose braces are balanced, returning the
+// variable names with the ones of the segment appended.
+func writeTemplateSegment(expr *strings.Builder, segment string, names []string) ([]string, error) {
+	for i := 0; i < len(segment); i++ {
+		switch segment[i] {
+		case '*':
+			expr.WriteString("[^/]*")
+		case '?':
+			expr.WriteString("[^/]")
+		case '{':
+			end := matchingBrace(segment, i)
+			name, regex, hasRegex := strings.Cut(segment[i+1:end], ":")
+			if !isVariableName(name) {
+				return nil, fmt.Errorf("%w: %q", errInvalidVariableName, name)
+			}
+			for _, existing := range names {
+				if existing == name {
+					return nil, fmt.Errorf("%w: %q", errDuplicateVariable, name)
+				}
+			}
+			names = append(names, name)
+			if !hasRegex {
+				regex = "[^/]+"
+			}
+			expr.WriteString("(?P<" + name + ">(?:" + regex + "))")
+			i = end
+		default:
+			expr.WriteString(regexp.QuoteMeta(segment[i : i+1]))
+		}
+	}
+	return names, nil
+}
+
+func matchingBrace(segment string, open int) int {
+	depth := 0
+	for i := open; i < len(segment); i++ {
+		switch segment[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		default:
+		}
+	}
+	return len(segment) - 1
+}
+
+func isVariableName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for i := range len(name) {
+		c := name[i]
+		if c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') && (c < '0' || c > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// VariableTemplate is a text referencing variables as '{name}', like the path variables captured by
+// a PathTemplate. The braces not enclosing a valid variable name are kept as they are.
+type VariableTemplate struct {
+	literals []string
+	names    []string
+}
+
+// ParseVariableTemplate parses the given text into a VariableTemplate.
+func ParseVariableTemplate(text string) VariableTemplate {
+	var template VariableTemplate
+	start := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '{' {
+			continue
+		}
+		end := strings.IndexByte(text[i:], '}')
+		if end == -1 {
+			break
+		}
+		name := text[i+1 : i+end]
+		if !isVariableName(name) {
+			continue
+		}
+		template.literals = append(template.literals, text[start:i])
+		template.names = append(template.names, name)
+		i += end
+		start = i + 1
+	}
+	template.literals = append(template.literals, text[start:])
+	return template
+}
+
+// HasVariables checks if the template references any variable.
+func (t VariableTemplate) HasVariables() bool {
+	return len(t.names) != 0
+}
+
+// Expand returns the text of the template with the variables replaced by their values. The variables
+// missing from the given values are kept as they are.
+func (t VariableTemplate) Expand(variables map[string]string) string {
+	return t.ExpandEscaped(variables, nil)
+}
+
+// ExpandEscaped is Expand with the values of the variables escaped by the given func, if not nil.
+func (t VariableTemplate) ExpandEscaped(variables map[string]string, escape func(string) string) string {
+	if len(t.names) == 0 {
+		return t.literals[0]
+	}
+	var text strings.Builder
+	for i, name := range t.names {
+		text.WriteString(t.literals[i])
+		value, ok := variables[name]
+		switch {
+		case !ok:
+			text.WriteString("{" + name + "}")
+		case escape != nil:
+			text.WriteString(escape(value))
+		default:
+			text.WriteString(value)
+		}
+	}
+	text.WriteString(t.literals[len(t.names)])
+	return text.String()
+}
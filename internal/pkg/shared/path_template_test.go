@@ -0,0 +1,201 @@
+package shared_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+func TestCompilePathTemplate(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		template    string
+	}{
+		{
+			name:        "compile should succeed when variables are valid",
+			template:    "/users/{id:[0-9]{1,6}}/files/{name}.{ext}",
+			expectedErr: nil,
+		},
+		{
+			name:        "compile should fail when braces are not balanced",
+			template:    "/users/{id",
+			expectedErr: errors.New("invalid path template: /users/{id: unbalanced braces"),
+		},
+		{
+			name:        "compile should fail when closing brace is not opened",
+			template:    "/users/id}",
+			expectedErr: errors.New("invalid path template: /users/id}: unbalanced braces"),
+		},
+		{
+			name:        "compile should fail when variable name is not valid",
+			template:    "/users/{1st}",
+			expectedErr: errors.New("invalid path template: /users/{1st}: invalid variable name: \"1st\""),
+		},
+		{
+			name:        "compile should fail when variable is duplicated",
+			template:    "/users/{id}/friends/{id}",
+			expectedErr: errors.New("invalid path template: /users/{id}/friends/{id}: duplicate variable: \"id\""),
+		},
+		{
+			name:        "compile should fail when variable regex is not valid",
+			template:    "/users/{id:[0-9}",
+			expectedErr: errors.New("invalid path template: /users/{id:[0-9}: error parsing regexp: missing closing ]: `[0-9))$`"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := shared.CompilePathTemplate(tt.template)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestPathTemplate_Match(t *testing.T) {
+	tests := []struct {
+		expected   map[string]string
+		name       string
+		template   string
+		path       string
+		expectedOk bool
+	}{
+		{
+			name:       "match should capture segment variable",
+			template:   "/users/{id}",
+			path:       "/users/42",
+			expected:   map[string]string{"id": "42"},
+			expectedOk: true,
+		},
+		{
+			name:       "match should ignore trailing slash",
+			template:   "/users/{id}",
+			path:       "/users/42/",
+			expected:   map[string]string{"id": "42"},
+			expectedOk: true,
+		},
+		{
+			name:       "match should not capture empty segment",
+			template:   "/users/{id}",
+			path:       "/users/",
+			expectedOk: false,
+		},
+		{
+			name:       "match should not capture more than a segment",
+			template:   "/users/{id}",
+			path:       "/users/42/friends",
+			expectedOk: false,
+		},
+		{
+			name:       "match should capture variable with regex",
+			template:   "/users/{id:[0-9]+}",
+			path:       "/users/42",
+			expected:   map[string]string{"id": "42"},
+			expectedOk: true,
+		},
+		{
+			name:       "match should fail when variable regex does not match",
+			template:   "/users/{id:[0-9]+}",
+			path:       "/users/me",
+			expectedOk: false,
+		},
+		{
+			name:       "match should capture variables within a segment",
+			template:   "/files/{name}.{ext:json|yaml}",
+			path:       "/files/config.v2.yaml",
+			expected:   map[string]string{"name": "config.v2", "ext": "yaml"},
+			expectedOk: true,
+		},
+		{
+			name:       "match should capture variables with globs",
+			template:   "/api/*/users/{id}/**",
+			path:       "/api/v1/users/42/friends/7",
+			expected:   map[string]string{"id": "42"},
+			expectedOk: true,
+		},
+		{
+			name:       "match should capture variable after double star",
+			template:   "/**/{file}",
+			path:       "/a/b/c.txt",
+			expected:   map[string]string{"file": "c.txt"},
+			expectedOk: true,
+		},
+		{
+			name:       "match should quote literal characters",
+			template:   "/v1.0/{id}",
+			path:       "/v1x0/42",
+			expectedOk: false,
+		},
+		{
+			name:       "match should capture variable with regex containing slash",
+			template:   "/static/{path:.+/.+}",
+			path:       "/static/css/site.css",
+			expected:   map[string]string{"path": "css/site.css"},
+			expectedOk: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := shared.CompilePathTemplate(tt.template)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			actual, ok := template.Match(tt.path)
+
+			if ok != tt.expectedOk || !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected %v %v actual %v %v", tt.expected, tt.expectedOk, actual, ok)
+			}
+		})
+	}
+}
+
+func TestVariableTemplate_Expand(t *testing.T) {
+	variables := map[string]string{"id": "42", "segment": "users"}
+	tests := []struct {
+		name         string
+		text         string
+		expected     string
+		hasVariables bool
+	}{
+		{name: "expand should keep text without variables", text: "plain", expected: "plain"},
+		{name: "expand should keep empty text", text: "", expected: ""},
+		{name: "expand should replace variables", text: "/{segment}/{id}/profile", expected: "/users/42/profile", hasVariables: true},
+		{name: "expand should keep unknown variables", text: "{id}-{other}", expected: "42-{other}", hasVariables: true},
+		{name: "expand should keep braces not enclosing a variable", text: `{"id": {id}}`, expected: `{"id": 42}`, hasVariables: true},
+		{name: "expand should keep unclosed brace", text: "{id", expected: "{id"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template := shared.ParseVariableTemplate(tt.text)
+
+			if actual := template.Expand(variables); actual != tt.expected {
+				t.Errorf("expected %s actual %s", tt.expected, actual)
+			}
+			if template.HasVariables() != tt.hasVariables {
+				t.Errorf("expected has variables %v actual %v", tt.hasVariables, template.HasVariables())
+			}
+		})
+	}
+}
+
+func TestVariableTemplate_ExpandEscaped(t *testing.T) {
+	template := shared.ParseVariableTemplate("/files/{name}/{other}")
+
+	actual := template.ExpandEscaped(map[string]string{"name": "a b"}, func(value string) string {
+		return strings.ReplaceAll(value, " ", "%20")
+	})
+
+	if expected := "/files/a%20b/{other}"; actual != expected {
+		t.Errorf("expected %s actual %s", expected, actual)
+	}
+}
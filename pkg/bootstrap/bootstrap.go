@@ -5,6 +5,8 @@ import (
 	"log/slog"
 	"net/http"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
 	"github.com/drathveloper/go-cloud-gateway/pkg/config"
 	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -24,9 +26,11 @@ func Initialize(opts *Options) (*http.Server, error) {
 	for _, customFilter := range opts.CustomFilters {
 		filter.BuilderRegistry.Register(customFilter.Name, customFilter.Builder)
 	}
+	config.ConfigureLoadShedding(opts.Config, concurrency.DefaultAdmissionController)
 	filterFactory := filter.NewFactory(filter.BuilderRegistry)
 	predFactory := predicate.NewFactory(predicate.BuilderRegistry)
-	routes, err := config.NewRoutes(opts.Config, predFactory, filterFactory, slog.Default())
+	eventBus := circuitbreaker.NewEventBus(opts.BreakerListeners...)
+	routes, err := config.NewRoutes(opts.Config, predFactory, filterFactory, eventBus, slog.Default())
 	if err != nil {
 		return nil, fmt.Errorf(initializeErrMsg, err)
 	}
@@ -36,12 +40,19 @@ func Initialize(opts *Options) (*http.Server, error) {
 	}
 	gwy := gateway.NewGateway(client)
 
-	gatewayHandler := gatewayhandler.NewGatewayHandler(gwy, routes, opts.GatewayErrorHandler)
+	gatewayHandler := gatewayhandler.NewGatewayHandler(
+		gwy,
+		routes,
+		opts.GatewayErrorHandler,
+		gatewayhandler.WithAdmissionController(concurrency.DefaultAdmissionController))
 
 	mux := http.NewServeMux()
 	for _, customHandler := range opts.ServerOptions.CustomHandlers {
 		mux.Handle(customHandler.Method+" "+customHandler.Path, customHandler.Handler)
 	}
+	if opts.ServerOptions.RoutesDebugPath != "" {
+		mux.Handle(http.MethodGet+" "+opts.ServerOptions.RoutesDebugPath, gatewayhandler.NewRoutesHandler(routes))
+	}
 	mux.Handle("/", gatewayHandler)
 
 	return &http.Server{
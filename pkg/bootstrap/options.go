@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
 	"github.com/drathveloper/go-cloud-gateway/pkg/config"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gatewayhandler"
@@ -15,12 +16,17 @@ type Options struct {
 	CustomFilters       []CustomFilter
 	CustomPredicates    []CustomPredicate
 	GatewayErrorHandler gatewayhandler.ErrorHandler
+	BreakerListeners    []circuitbreaker.Listener
 	ServerOptions       ServerOpts
 }
 
 // ServerOpts is the options for the server.
+//
+// When RoutesDebugPath is set, the routes are served as JSON on GET requests to the path, in the order they
+// are matched.
 type ServerOpts struct {
 	CustomHandlers    []CustomHandler
+	RoutesDebugPath   string
 	ReadHeaderTimeout time.Duration
 	IdleTimeout       time.Duration
 	WriteTimeout      time.Duration
@@ -3,6 +3,7 @@ package bootstrap
 import (
 	"time"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
 	"github.com/drathveloper/go-cloud-gateway/pkg/config"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gatewayhandler"
 )
@@ -20,6 +21,7 @@ type OptionsBuilder struct {
 	customFilters      []CustomFilter
 	customPredicates   []CustomPredicate
 	customErrorHandler gatewayhandler.ErrorHandler
+	breakerListeners   []circuitbreaker.Listener
 	serverOptions      ServerOpts
 }
 
@@ -76,6 +78,9 @@ func (b *OptionsBuilder) WithServerOptions(opts ServerOpts) *OptionsBuilder {
 	if opts.MaxHeaderBytes != 0 {
 		b.serverOptions.MaxHeaderBytes = opts.MaxHeaderBytes
 	}
+	if opts.RoutesDebugPath != "" {
+		b.serverOptions.RoutesDebugPath = opts.RoutesDebugPath
+	}
 	return b
 }
 
@@ -91,6 +96,15 @@ func (b *OptionsBuilder) WithErrorHandler(errorHandler gatewayhandler.ErrorHandl
 	return b
 }
 
+// WithBreakerListeners sets the listeners of the route circuit breaker events.
+//
+// The circuitbreaker package provides listeners logging the events, counting them and keeping
+// the last ones. The last two are http handlers that can be mounted as custom handlers.
+func (b *OptionsBuilder) WithBreakerListeners(listeners ...circuitbreaker.Listener) *OptionsBuilder {
+	b.breakerListeners = listeners
+	return b
+}
+
 // Build builds the options.
 func (b *OptionsBuilder) Build() *Options {
 	return &Options{
@@ -98,6 +112,7 @@ func (b *OptionsBuilder) Build() *Options {
 		CustomFilters:       b.customFilters,
 		CustomPredicates:    b.customPredicates,
 		GatewayErrorHandler: b.customErrorHandler,
+		BreakerListeners:    b.breakerListeners,
 		ServerOptions:       b.serverOptions,
 	}
 }
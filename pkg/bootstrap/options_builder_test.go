@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/drathveloper/go-cloud-gateway/pkg/bootstrap"
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
 	"github.com/drathveloper/go-cloud-gateway/pkg/config"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gatewayhandler"
@@ -29,6 +30,7 @@ func TestOptionsBuilder_Build(t *testing.T) {
 		customPredicates []bootstrap.CustomPredicate
 		customHandlers   []bootstrap.CustomHandler
 		serverOptions    *bootstrap.ServerOpts
+		breakerListeners []circuitbreaker.Listener
 		expectedOpts     bootstrap.Options
 	}{
 		{
@@ -325,6 +327,48 @@ func TestOptionsBuilder_Build(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:          "build should succeed when routes debug path provided",
+			cfg:           dummyConfig,
+			serverOptions: &bootstrap.ServerOpts{RoutesDebugPath: "/debug/routes"},
+			expectedOpts: bootstrap.Options{
+				Config:              dummyConfig,
+				CustomFilters:       make([]bootstrap.CustomFilter, 0),
+				CustomPredicates:    make([]bootstrap.CustomPredicate, 0),
+				GatewayErrorHandler: gatewayhandler.BaseErrorHandler(),
+				ServerOptions: bootstrap.ServerOpts{
+					CustomHandlers:    make([]bootstrap.CustomHandler, 0),
+					RoutesDebugPath:   "/debug/routes",
+					ReadHeaderTimeout: 2 * time.Second,
+					IdleTimeout:       60 * time.Second,
+					WriteTimeout:      10 * time.Second,
+					ReadTimeout:       10 * time.Second,
+					Port:              8000,
+					MaxHeaderBytes:    1048576,
+				},
+			},
+		},
+		{
+			name:             "build should succeed when breaker listeners provided",
+			cfg:              dummyConfig,
+			breakerListeners: []circuitbreaker.Listener{circuitbreaker.NewMetricsListener()},
+			expectedOpts: bootstrap.Options{
+				Config:              dummyConfig,
+				CustomFilters:       make([]bootstrap.CustomFilter, 0),
+				CustomPredicates:    make([]bootstrap.CustomPredicate, 0),
+				GatewayErrorHandler: gatewayhandler.BaseErrorHandler(),
+				BreakerListeners:    []circuitbreaker.Listener{circuitbreaker.NewMetricsListener()},
+				ServerOptions: bootstrap.ServerOpts{
+					CustomHandlers:    make([]bootstrap.CustomHandler, 0),
+					ReadHeaderTimeout: 2 * time.Second,
+					IdleTimeout:       60 * time.Second,
+					WriteTimeout:      10 * time.Second,
+					ReadTimeout:       10 * time.Second,
+					Port:              8000,
+					MaxHeaderBytes:    1048576,
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -342,6 +386,9 @@ func TestOptionsBuilder_Build(t *testing.T) {
 			if tt.serverOptions != nil {
 				builder.WithServerOptions(*tt.serverOptions)
 			}
+			if tt.breakerListeners != nil {
+				builder.WithBreakerListeners(tt.breakerListeners...)
+			}
 
 			opts := builder.Build()
 
@@ -354,6 +401,9 @@ func TestOptionsBuilder_Build(t *testing.T) {
 			if !reflect.DeepEqual(len(tt.expectedOpts.CustomPredicates), len(opts.CustomPredicates)) {
 				t.Errorf("expected %+v actual %+v", tt.expectedOpts, opts)
 			}
+			if !reflect.DeepEqual(len(tt.expectedOpts.BreakerListeners), len(opts.BreakerListeners)) {
+				t.Errorf("expected %+v actual %+v", tt.expectedOpts, opts)
+			}
 			assertServerOpts(t, tt.expectedOpts.ServerOptions, opts.ServerOptions)
 		})
 	}
@@ -379,4 +429,7 @@ func assertServerOpts(t *testing.T, expectedOpts bootstrap.ServerOpts, actualOpt
 	if expectedOpts.MaxHeaderBytes != actualOpts.MaxHeaderBytes {
 		t.Errorf("expected %+v actual %+v", expectedOpts, actualOpts)
 	}
+	if expectedOpts.RoutesDebugPath != actualOpts.RoutesDebugPath {
+		t.Errorf("expected %+v actual %+v", expectedOpts, actualOpts)
+	}
 }
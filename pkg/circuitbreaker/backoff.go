@@ -0,0 +1,33 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidBackoff is returned when an open-state backoff is not valid.
+var ErrInvalidBackoff = errors.New("invalid open state backoff")
+
+// Backoff returns the period of the open state after the given number of consecutive trips,
+// the first trip being 1. The trips are consecutive until the CircuitBreaker closes again.
+type Backoff func(trips uint32) time.Duration
+
+// ExponentialBackoff returns a Backoff starting at base and multiplying it by multiplier at every
+// consecutive trip, up to maxDuration. The multiplier must be at least 1 and maxDuration must not be
+// less than base.
+func ExponentialBackoff(base time.Duration, multiplier float64, maxDuration time.Duration) (Backoff, error) {
+	if base <= 0 || multiplier < 1 || maxDuration < base {
+		return nil, fmt.Errorf("%w: base %s, multiplier %g, max %s", ErrInvalidBackoff, base, multiplier, maxDuration)
+	}
+	return func(trips uint32) time.Duration {
+		duration := float64(base)
+		for range trips - min(trips, 1) {
+			duration *= multiplier
+			if duration >= float64(maxDuration) {
+				return maxDuration
+			}
+		}
+		return time.Duration(duration)
+	}, nil
+}
@@ -0,0 +1,71 @@
+package circuitbreaker_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		expected    []time.Duration
+		base        time.Duration
+		maxDuration time.Duration
+		multiplier  float64
+	}{
+		{
+			name:        "exponential backoff should grow by the multiplier up to the max",
+			base:        time.Second,
+			multiplier:  2,
+			maxDuration: 10 * time.Second,
+			expected:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second},
+		},
+		{
+			name:        "exponential backoff should support fractional multipliers",
+			base:        10 * time.Second,
+			multiplier:  1.5,
+			maxDuration: time.Minute,
+			expected:    []time.Duration{10 * time.Second, 15 * time.Second, 22500 * time.Millisecond},
+		},
+		{
+			name:        "exponential backoff should be fixed when multiplier is 1",
+			base:        time.Second,
+			multiplier:  1,
+			maxDuration: time.Second,
+			expected:    []time.Duration{time.Second, time.Second, time.Second},
+		},
+		{
+			name:        "exponential backoff should return error when multiplier is less than 1",
+			base:        time.Second,
+			multiplier:  0.5,
+			maxDuration: time.Minute,
+			expectedErr: errors.New("invalid open state backoff: base 1s, multiplier 0.5, max 1m0s"),
+		},
+		{
+			name:        "exponential backoff should return error when max is less than base",
+			base:        time.Minute,
+			multiplier:  2,
+			maxDuration: time.Second,
+			expectedErr: errors.New("invalid open state backoff: base 1m0s, multiplier 2, max 1s"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backoff, err := circuitbreaker.ExponentialBackoff(tt.base, tt.multiplier, tt.maxDuration)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Fatalf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			for i, expected := range tt.expected {
+				if actual := backoff(uint32(i + 1)); actual != expected { //nolint:gosec
+					t.Errorf("expected trip %d duration %s actual %s", i+1, expected, actual)
+				}
+			}
+		})
+	}
+}
@@ -10,6 +10,7 @@ const (
 	defaultInterval            = time.Duration(0) * time.Second
 	defaultTimeout             = time.Duration(60) * time.Second
 	defaultConsecutiveFailures = 5
+	defaultTimeWindowLength    = time.Duration(60) * time.Second
 )
 
 // ErrHalfOpenRequestExceeded is returned when the circuit breaker is half-open and the number of requests is over the
@@ -24,15 +25,21 @@ type CircuitBreaker[T any] struct {
 	expiry        time.Time
 	readyToTrip   func(counts Counts) bool
 	isSuccessful  func(err error) bool
+	isIgnored     func(err error) bool
 	onStateChange func(name string, from State, to State)
+	onEvent       func(event Event)
+	openBackoff   Backoff
+	window        slidingWindow
 	name          string
 	interval      time.Duration
 	timeout       time.Duration
+	slowCall      time.Duration
 	state         State
 	generation    uint64
 	counts        Counts
 	mutex         sync.Mutex
 	maxRequests   uint32
+	trips         uint32
 }
 
 // NewCircuitBreaker returns a new CircuitBreaker configured with the given Settings.
@@ -41,6 +48,9 @@ func NewCircuitBreaker[T any](settings Settings) *CircuitBreaker[T] {
 
 	circuitBreaker.name = settings.Name
 	circuitBreaker.onStateChange = settings.OnStateChange
+	circuitBreaker.onEvent = settings.OnEvent
+	circuitBreaker.isIgnored = settings.IsIgnored
+	circuitBreaker.openBackoff = settings.OpenBackoff
 
 	if settings.MaxRequests == 0 {
 		circuitBreaker.maxRequests = 1
@@ -54,6 +64,25 @@ func NewCircuitBreaker[T any](settings Settings) *CircuitBreaker[T] {
 		circuitBreaker.interval = settings.Interval
 	}
 
+	switch settings.WindowType {
+	case WindowCountBased:
+		circuitBreaker.window = newSlidingWindow(settings.WindowType, settings.WindowSize, 0)
+		circuitBreaker.interval = defaultInterval
+	case WindowTimeBased:
+		length := settings.Interval
+		if length <= 0 {
+			length = defaultTimeWindowLength
+		}
+		circuitBreaker.window = newSlidingWindow(settings.WindowType, settings.WindowSize, length)
+		circuitBreaker.interval = defaultInterval
+	case WindowFixed:
+	default:
+	}
+
+	if settings.SlowCallDuration > 0 {
+		circuitBreaker.slowCall = settings.SlowCallDuration
+	}
+
 	if settings.Timeout <= 0 {
 		circuitBreaker.timeout = defaultTimeout
 	} else {
@@ -100,11 +129,13 @@ func (cb *CircuitBreaker[T]) State() State {
 }
 
 // Counts returns internal counters.
+//
+// In the closed state of a sliding window CircuitBreaker, the totals are the ones of the window.
 func (cb *CircuitBreaker[T]) Counts() Counts {
 	cb.mutex.Lock()
 	defer cb.mutex.Unlock()
 
-	return cb.counts
+	return cb.currentCounts(time.Now())
 }
 
 // Execute runs the given request if the CircuitBreaker accepts it.
@@ -121,16 +152,21 @@ func (cb *CircuitBreaker[T]) Execute(req func() (T, error)) (T, error) {
 		return defaultValue, err
 	}
 
+	start := time.Now()
 	defer func() {
 		e := recover()
 		if e != nil {
-			cb.afterRequest(generation, false)
+			cb.afterRequest(generation, false, time.Since(start))
 			panic(e)
 		}
 	}()
 
 	result, err := req()
-	cb.afterRequest(generation, cb.isSuccessful(err))
+	if err != nil && cb.isIgnored != nil && cb.isIgnored(err) {
+		cb.afterIgnoredRequest(generation, time.Since(start))
+		return result, err
+	}
+	cb.afterRequest(generation, cb.isSuccessful(err), time.Since(start))
 	return result, err
 }
 
@@ -142,16 +178,20 @@ func (cb *CircuitBreaker[T]) beforeRequest() (uint64, error) {
 	state, generation := cb.currentState(now)
 
 	if state == StateOpen {
+		cb.publish(Event{Type: EventCallRejected, Time: now, State: state})
 		return generation, ErrOpenState
 	} else if state == StateHalfOpen && cb.counts.Requests >= cb.maxRequests {
+		cb.publish(Event{Type: EventCallRejected, Time: now, State: state})
 		return generation, ErrHalfOpenRequestExceeded
 	}
 
-	cb.counts.onRequest()
+	if !cb.isWindowed(state) {
+		cb.counts.onRequest()
+	}
 	return generation, nil
 }
 
-func (cb *CircuitBreaker[T]) afterRequest(before uint64, success bool) {
+func (cb *CircuitBreaker[T]) afterRequest(before uint64, success bool, elapsed time.Duration) {
 	cb.mutex.Lock()
 	defer cb.mutex.Unlock()
 
@@ -161,17 +201,61 @@ func (cb *CircuitBreaker[T]) afterRequest(before uint64, success bool) {
 		return
 	}
 
+	slow := cb.isSlow(elapsed)
+	if cb.isWindowed(state) {
+		cb.window.record(now, outcome{failure: !success, slow: slow})
+	} else if slow {
+		cb.counts.onSlowCall()
+	}
+	// A slow half-open probe means the backend has not recovered yet.
+	success = success && (!slow || state != StateHalfOpen)
+	cb.publishOutcome(state, now, success, elapsed, slow)
 	if success {
-		cb.onSuccess(state, now)
+		cb.onSuccess(state, now, slow)
 	} else {
 		cb.onFailure(state, now)
 	}
 }
 
-func (cb *CircuitBreaker[T]) onSuccess(state State, now time.Time) {
+// afterIgnoredRequest takes back the request of a call that is not recorded.
+func (cb *CircuitBreaker[T]) afterIgnoredRequest(before uint64, elapsed time.Duration) {
+	cb.mutex.Lock()
+	defer cb.mutex.Unlock()
+
+	now := time.Now()
+	state, generation := cb.currentState(now)
+	if generation != before {
+		return
+	}
+
+	if !cb.isWindowed(state) {
+		cb.counts.onIgnored()
+	}
+	cb.publish(Event{Type: EventIgnored, Time: now, State: state, Duration: elapsed})
+}
+
+// isWindowed reports whether the sliding window keeps the totals of the calls in the given state. The
+// internal counts then only keep the consecutive ones: with no interval to clear them, their totals would
+// grow for as long as the breaker stays closed.
+func (cb *CircuitBreaker[T]) isWindowed(state State) bool {
+	return cb.window != nil && state == StateClosed
+}
+
+func (cb *CircuitBreaker[T]) isSlow(elapsed time.Duration) bool {
+	return cb.slowCall > 0 && elapsed >= cb.slowCall
+}
+
+func (cb *CircuitBreaker[T]) onSuccess(state State, now time.Time, slow bool) {
 	switch state {
 	case StateClosed:
-		cb.counts.onSuccess()
+		if cb.isWindowed(state) {
+			cb.counts.onConsecutiveSuccess()
+		} else {
+			cb.counts.onSuccess()
+		}
+		if slow && cb.readyToTrip(cb.currentCounts(now)) {
+			cb.setState(StateOpen, now)
+		}
 	case StateHalfOpen:
 		cb.counts.onSuccess()
 		if cb.counts.ConsecutiveSuccesses >= cb.maxRequests {
@@ -185,8 +269,12 @@ func (cb *CircuitBreaker[T]) onSuccess(state State, now time.Time) {
 func (cb *CircuitBreaker[T]) onFailure(state State, now time.Time) {
 	switch state {
 	case StateClosed:
-		cb.counts.onFailure()
-		if cb.readyToTrip(cb.counts) {
+		if cb.isWindowed(state) {
+			cb.counts.onConsecutiveFailure()
+		} else {
+			cb.counts.onFailure()
+		}
+		if cb.readyToTrip(cb.currentCounts(now)) {
 			cb.setState(StateOpen, now)
 		}
 	case StateHalfOpen:
@@ -196,6 +284,21 @@ func (cb *CircuitBreaker[T]) onFailure(state State, now time.Time) {
 	}
 }
 
+// currentCounts returns the counts ReadyToTrip is evaluated on: in the closed state of a
+// sliding window CircuitBreaker, the totals are replaced with the ones of the window.
+func (cb *CircuitBreaker[T]) currentCounts(now time.Time) Counts {
+	counts := cb.counts
+	if !cb.isWindowed(cb.state) {
+		return counts
+	}
+	totals := cb.window.totals(now)
+	counts.Requests = totals.calls
+	counts.TotalFailures = totals.failures
+	counts.TotalSuccesses = totals.calls - totals.failures
+	counts.TotalSlowCalls = totals.slow
+	return counts
+}
+
 func (cb *CircuitBreaker[T]) currentState(now time.Time) (State, uint64) {
 	switch cb.state {
 	case StateClosed:
@@ -219,17 +322,47 @@ func (cb *CircuitBreaker[T]) setState(state State, now time.Time) {
 
 	prev := cb.state
 	cb.state = state
+	switch state {
+	case StateOpen:
+		cb.trips++
+	case StateClosed:
+		cb.trips = 0
+	case StateHalfOpen:
+	default:
+	}
 
 	cb.toNewGeneration(now)
 
 	if cb.onStateChange != nil {
 		cb.onStateChange(cb.name, prev, state)
 	}
+	cb.publish(Event{Type: EventStateTransition, Time: now, From: &prev, State: state})
+}
+
+// publishOutcome publishes the outcome of a call before it is counted, so a success or failure
+// event always precedes the state transition it causes.
+func (cb *CircuitBreaker[T]) publishOutcome(state State, now time.Time, success bool, elapsed time.Duration, slow bool) {
+	eventType := EventSuccess
+	if !success {
+		eventType = EventFailure
+	}
+	cb.publish(Event{Type: eventType, Time: now, State: state, Duration: elapsed, Slow: slow})
+}
+
+func (cb *CircuitBreaker[T]) publish(event Event) {
+	if cb.onEvent == nil {
+		return
+	}
+	event.Name = cb.name
+	cb.onEvent(event)
 }
 
 func (cb *CircuitBreaker[T]) toNewGeneration(now time.Time) {
 	cb.generation++
 	cb.counts.clear()
+	if cb.window != nil {
+		cb.window.reset()
+	}
 
 	var zero time.Time
 	switch cb.state {
@@ -240,10 +373,17 @@ func (cb *CircuitBreaker[T]) toNewGeneration(now time.Time) {
 			cb.expiry = now.Add(cb.interval)
 		}
 	case StateOpen:
-		cb.expiry = now.Add(cb.timeout)
+		cb.expiry = now.Add(cb.openDuration())
 	case StateHalfOpen: // StateHalfOpen
 		cb.expiry = zero
 	default:
 		cb.expiry = zero
 	}
 }
+
+func (cb *CircuitBreaker[T]) openDuration() time.Duration {
+	if cb.openBackoff == nil {
+		return cb.timeout
+	}
+	return cb.openBackoff(cb.trips)
+}
@@ -114,7 +114,7 @@ func TestNewCircuitBreaker(t *testing.T) {
 	assert.NotNil(t, defaultCB.readyToTrip)
 	assert.Nil(t, defaultCB.onStateChange)
 	assert.Equal(t, StateClosed, defaultCB.state)
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, defaultCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, defaultCB.counts)
 	assert.True(t, defaultCB.expiry.IsZero())
 
 	customCB := newCustom() //nolint:govet
@@ -125,7 +125,7 @@ func TestNewCircuitBreaker(t *testing.T) {
 	assert.NotNil(t, customCB.readyToTrip)
 	assert.NotNil(t, customCB.onStateChange)
 	assert.Equal(t, StateClosed, customCB.state)
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, customCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, customCB.counts)
 	assert.False(t, customCB.expiry.IsZero())
 
 	negativeDurationCB := newNegativeDurationCB()
@@ -136,7 +136,7 @@ func TestNewCircuitBreaker(t *testing.T) {
 	assert.NotNil(t, negativeDurationCB.readyToTrip)
 	assert.Nil(t, negativeDurationCB.onStateChange)
 	assert.Equal(t, StateClosed, negativeDurationCB.state)
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, negativeDurationCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, negativeDurationCB.counts)
 	assert.True(t, negativeDurationCB.expiry.IsZero())
 }
 
@@ -147,27 +147,27 @@ func TestDefaultCircuitBreaker(t *testing.T) {
 		require.NoError(t, fail(defaultCB))
 	}
 	assert.Equal(t, StateClosed, defaultCB.State())
-	assert.Equal(t, Counts{5, 0, 5, 0, 5}, defaultCB.counts)
+	assert.Equal(t, Counts{5, 0, 5, 0, 5, 0}, defaultCB.counts)
 
 	require.NoError(t, succeed(defaultCB))
 	assert.Equal(t, StateClosed, defaultCB.State())
-	assert.Equal(t, Counts{6, 1, 5, 1, 0}, defaultCB.counts)
+	assert.Equal(t, Counts{6, 1, 5, 1, 0, 0}, defaultCB.counts)
 
 	require.NoError(t, fail(defaultCB))
 	assert.Equal(t, StateClosed, defaultCB.State())
-	assert.Equal(t, Counts{7, 1, 6, 0, 1}, defaultCB.counts)
+	assert.Equal(t, Counts{7, 1, 6, 0, 1, 0}, defaultCB.counts)
 
 	// StateClosed to StateOpen
 	for range 5 {
 		require.NoError(t, fail(defaultCB)) // 6 consecutive failures
 	}
 	assert.Equal(t, StateOpen, defaultCB.State())
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, defaultCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, defaultCB.counts)
 	assert.False(t, defaultCB.expiry.IsZero())
 
 	require.Error(t, succeed(defaultCB))
 	require.Error(t, fail(defaultCB))
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, defaultCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, defaultCB.counts)
 
 	pseudoSleep(defaultCB, time.Duration(59)*time.Second)
 	assert.Equal(t, StateOpen, defaultCB.State())
@@ -180,7 +180,7 @@ func TestDefaultCircuitBreaker(t *testing.T) {
 	// StateHalfOpen to StateOpen
 	require.NoError(t, fail(defaultCB))
 	assert.Equal(t, StateOpen, defaultCB.State())
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, defaultCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, defaultCB.counts)
 	assert.False(t, defaultCB.expiry.IsZero())
 
 	// StateOpen to StateHalfOpen
@@ -191,7 +191,7 @@ func TestDefaultCircuitBreaker(t *testing.T) {
 	// StateHalfOpen to StateClosed
 	require.NoError(t, succeed(defaultCB))
 	assert.Equal(t, StateClosed, defaultCB.State())
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, defaultCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, defaultCB.counts)
 	assert.True(t, defaultCB.expiry.IsZero())
 }
 
@@ -203,23 +203,23 @@ func TestCustomCircuitBreaker(t *testing.T) {
 		require.NoError(t, fail(customCB))
 	}
 	assert.Equal(t, StateClosed, customCB.State())
-	assert.Equal(t, Counts{10, 5, 5, 0, 1}, customCB.counts)
+	assert.Equal(t, Counts{10, 5, 5, 0, 1, 0}, customCB.counts)
 
 	pseudoSleep(customCB, time.Duration(29)*time.Second)
 	require.NoError(t, succeed(customCB))
 	assert.Equal(t, StateClosed, customCB.State())
-	assert.Equal(t, Counts{11, 6, 5, 1, 0}, customCB.counts)
+	assert.Equal(t, Counts{11, 6, 5, 1, 0, 0}, customCB.counts)
 
 	pseudoSleep(customCB, time.Duration(1)*time.Second) // over Interval
 	require.NoError(t, fail(customCB))
 	assert.Equal(t, StateClosed, customCB.State())
-	assert.Equal(t, Counts{1, 0, 1, 0, 1}, customCB.counts)
+	assert.Equal(t, Counts{1, 0, 1, 0, 1, 0}, customCB.counts)
 
 	// StateClosed to StateOpen
 	assert.NoError(t, succeed(customCB))
 	assert.NoError(t, fail(customCB)) // failure ratio: 2/3 >= 0.6
 	assert.Equal(t, StateOpen, customCB.State())
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, customCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, customCB.counts)
 	assert.False(t, customCB.expiry.IsZero())
 	assert.Equal(t, StateChange{"cb", StateClosed, StateOpen}, stateChange)
 
@@ -232,25 +232,25 @@ func TestCustomCircuitBreaker(t *testing.T) {
 	assert.NoError(t, succeed(customCB))
 	assert.NoError(t, succeed(customCB))
 	assert.Equal(t, StateHalfOpen, customCB.State())
-	assert.Equal(t, Counts{2, 2, 0, 2, 0}, customCB.counts)
+	assert.Equal(t, Counts{2, 2, 0, 2, 0, 0}, customCB.counts)
 
 	// StateHalfOpen to StateClosed
 	ch := succeedLater(customCB, time.Duration(100)*time.Millisecond) // 3 consecutive successes
 	time.Sleep(time.Duration(50) * time.Millisecond)
 	// the goroutine spawned by succeedLater is still inside Execute: counts
 	// must be read through the mutex-protected accessor.
-	assert.Equal(t, Counts{3, 2, 0, 2, 0}, customCB.Counts())
+	assert.Equal(t, Counts{3, 2, 0, 2, 0, 0}, customCB.Counts())
 	require.Error(t, succeed(customCB)) // over MaxRequests
 	require.NoError(t, <-ch)
 	assert.Equal(t, StateClosed, customCB.State())
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, customCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, customCB.counts)
 	assert.False(t, customCB.expiry.IsZero())
 	assert.Equal(t, StateChange{"cb", StateHalfOpen, StateClosed}, stateChange)
 }
 
 func TestPanicInRequest(t *testing.T) {
 	assert.Panics(t, func() { _ = causePanic(defaultCB) })
-	assert.Equal(t, Counts{1, 0, 1, 0, 1}, defaultCB.counts)
+	assert.Equal(t, Counts{1, 0, 1, 0, 1, 0}, defaultCB.counts)
 }
 
 func TestGeneration(t *testing.T) {
@@ -260,15 +260,15 @@ func TestGeneration(t *testing.T) {
 	time.Sleep(time.Duration(500) * time.Millisecond)
 	// the goroutine spawned by succeedLater is still inside Execute: counts
 	// must be read through the mutex-protected accessor.
-	assert.Equal(t, Counts{2, 1, 0, 1, 0}, customCB.Counts())
+	assert.Equal(t, Counts{2, 1, 0, 1, 0, 0}, customCB.Counts())
 
 	time.Sleep(time.Duration(500) * time.Millisecond) // over Interval
 	assert.Equal(t, StateClosed, customCB.State())
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, customCB.Counts())
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, customCB.Counts())
 
 	// the request from the previous generation has no effect on customCB.counts
 	require.NoError(t, <-ch)
-	assert.Equal(t, Counts{0, 0, 0, 0, 0}, customCB.counts)
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, customCB.counts)
 }
 
 func TestCustomIsSuccessful(t *testing.T) {
@@ -281,7 +281,7 @@ func TestCustomIsSuccessful(t *testing.T) {
 		require.NoError(t, fail(cb))
 	}
 	assert.Equal(t, StateClosed, cb.State())
-	assert.Equal(t, Counts{5, 5, 0, 5, 0}, cb.counts)
+	assert.Equal(t, Counts{5, 5, 0, 5, 0, 0}, cb.counts)
 
 	cb.counts.clear()
 
@@ -294,6 +294,31 @@ func TestCustomIsSuccessful(t *testing.T) {
 	assert.Equal(t, StateOpen, cb.State())
 }
 
+func TestCustomIsIgnored(t *testing.T) {
+	var events []EventType
+	cb := NewCircuitBreaker[bool](Settings{
+		IsIgnored: func(error) bool { return true },
+		OnEvent:   func(event Event) { events = append(events, event.Type) },
+	})
+
+	require.NoError(t, succeed(cb))
+	for range 10 {
+		require.NoError(t, fail(cb))
+	}
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, Counts{1, 1, 0, 1, 0, 0}, cb.counts)
+	assert.Equal(t, EventIgnored, events[len(events)-1])
+
+	cb.setState(StateHalfOpen, time.Now())
+	for range 3 {
+		require.NoError(t, fail(cb))
+	}
+	assert.Equal(t, StateHalfOpen, cb.State())
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, cb.counts)
+	require.NoError(t, succeed(cb))
+	assert.Equal(t, StateClosed, cb.State())
+}
+
 func TestCircuitBreakerInParallel(t *testing.T) {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
@@ -316,5 +341,38 @@ func TestCircuitBreakerInParallel(t *testing.T) {
 		err := <-ch
 		require.NoError(t, err)
 	}
-	assert.Equal(t, Counts{total, total, 0, total, 0}, customCB.counts)
+	assert.Equal(t, Counts{total, total, 0, total, 0, 0}, customCB.counts)
+}
+
+func TestCircuitBreakerOpenBackoff(t *testing.T) {
+	backoff, err := ExponentialBackoff(10*time.Second, 2, 30*time.Second)
+	require.NoError(t, err)
+	cb := NewCircuitBreaker[bool](Settings{
+		MaxRequests: 1,
+		ReadyToTrip: func(counts Counts) bool { return counts.ConsecutiveFailures >= 1 },
+		OpenBackoff: backoff,
+	})
+	openFor := func() time.Duration {
+		return time.Until(cb.expiry).Round(time.Second)
+	}
+
+	require.NoError(t, fail(cb))
+	assert.Equal(t, StateOpen, cb.State())
+	assert.Equal(t, 10*time.Second, openFor())
+
+	// Every failed half-open probe doubles the open state, up to the max.
+	for _, expected := range []time.Duration{20 * time.Second, 30 * time.Second, 30 * time.Second} {
+		pseudoSleep(cb, time.Minute)
+		assert.Equal(t, StateHalfOpen, cb.State())
+		require.NoError(t, fail(cb))
+		assert.Equal(t, StateOpen, cb.State())
+		assert.Equal(t, expected, openFor())
+	}
+
+	// Closing resets the backoff.
+	pseudoSleep(cb, time.Minute)
+	require.NoError(t, succeed(cb))
+	assert.Equal(t, StateClosed, cb.State())
+	require.NoError(t, fail(cb))
+	assert.Equal(t, 10*time.Second, openFor())
 }
@@ -15,28 +15,58 @@ import (
 // Interval is the cyclic period of the closed state
 // for the CircuitBreaker to clear the internal Counts.
 // If Interval is less than or equal to 0, the CircuitBreaker doesn't clear internal Counts during the closed state.
+// With a time-based window, Interval is the length of the window instead, 60 seconds by default.
+//
+// WindowType is the window the closed-state Counts are aggregated over. With the default WindowFixed,
+// the Counts are cleared at every Interval. Sliding windows never clear them at once: outcomes leave
+// a count-based window when newer calls push them out, and a time-based window a bucket at a time.
+//
+// WindowSize is the number of calls of a count-based window, 100 by default, or the number of buckets
+// a time-based window is split into, 10 by default.
+//
+// SlowCallDuration is the duration above which a call is counted as slow, even if it succeeds.
+// A slow call in the closed state calls ReadyToTrip, and a slow call in the half-open state
+// counts as a failure. If SlowCallDuration is less than or equal to 0, no call is slow.
 //
 // Timeout is the period of the open state,
 // after which the state of the CircuitBreaker becomes half-open.
 // If Timeout is less than or equal to 0, the timeout value of the CircuitBreaker is set to 60 seconds.
 //
-// ReadyToTrip is called with a copy of Counts whenever a request fails in the closed state.
+// OpenBackoff, when set, replaces Timeout as the period of the open state: it is called with the number
+// of consecutive trips, so a backend failing its half-open probes again is left alone for longer.
+// The count resets once the CircuitBreaker closes.
+//
+// ReadyToTrip is called with a copy of Counts whenever a request fails or is slow in the closed state.
 // If ReadyToTrip returns true, the CircuitBreaker will be placed into the open state.
 // If ReadyToTrip is nil, the default ReadyToTrip is used.
 // Default ReadyToTrip returns true when the number of consecutive failures is more than 5.
 //
 // OnStateChange is called whenever the state of the CircuitBreaker changes.
 //
+// OnEvent is called with every Event of the CircuitBreaker: state transitions, rejected calls, the
+// recorded successes and failures, and the ignored calls. Like OnStateChange, it is called with the
+// CircuitBreaker lock held.
+//
 // IsSuccessful is called with the error returned from a request.
 // If IsSuccessful returns true, the error is counted as a success.
 // Otherwise, the error is counted as a failure.
 // If IsSuccessful is nil, default IsSuccessful is used, which returns false for all non-nil errors.
+//
+// IsIgnored, when set, is called with the non-nil errors returned from a request before IsSuccessful.
+// If IsIgnored returns true, the request is not recorded: it counts neither as a success nor as a failure,
+// and does not take a half-open slot.
 type Settings struct {
-	ReadyToTrip   func(counts Counts) bool
-	OnStateChange func(name string, from State, to State)
-	IsSuccessful  func(err error) bool
-	Name          string
-	Interval      time.Duration
-	Timeout       time.Duration
-	MaxRequests   uint32
+	ReadyToTrip      func(counts Counts) bool
+	OnStateChange    func(name string, from State, to State)
+	OnEvent          func(event Event)
+	OpenBackoff      Backoff
+	IsSuccessful     func(err error) bool
+	IsIgnored        func(err error) bool
+	Name             string
+	Interval         time.Duration
+	Timeout          time.Duration
+	SlowCallDuration time.Duration
+	WindowType       WindowType
+	WindowSize       int
+	MaxRequests      uint32
 }
@@ -1,6 +1,6 @@
 package circuitbreaker
 
-// Counts holds the numbers of requests and their successes/failures.
+// Counts holds the numbers of requests and their successes/failures, and how many of them were slow.
 // CircuitBreaker clears the internal Counts either on the change of the state or at the closed-state intervals.
 // Counts ignore the results of the requests sent before clearing.
 type Counts struct {
@@ -9,28 +9,53 @@ type Counts struct {
 	TotalFailures        uint32
 	ConsecutiveSuccesses uint32
 	ConsecutiveFailures  uint32
+	TotalSlowCalls       uint32
 }
 
 func (c *Counts) onRequest() {
 	c.Requests++
 }
 
+// onIgnored takes back the request of a call that is not recorded.
+func (c *Counts) onIgnored() {
+	if c.Requests > 0 {
+		c.Requests--
+	}
+}
+
 func (c *Counts) onSuccess() {
 	c.TotalSuccesses++
-	c.ConsecutiveSuccesses++
-	c.ConsecutiveFailures = 0
+	c.onConsecutiveSuccess()
 }
 
 func (c *Counts) onFailure() {
 	c.TotalFailures++
+	c.onConsecutiveFailure()
+}
+
+// onConsecutiveSuccess counts a success in the consecutive counts only, for when a sliding window keeps the
+// totals.
+func (c *Counts) onConsecutiveSuccess() {
+	c.ConsecutiveSuccesses++
+	c.ConsecutiveFailures = 0
+}
+
+// onConsecutiveFailure counts a failure in the consecutive counts only, for when a sliding window keeps the
+// totals.
+func (c *Counts) onConsecutiveFailure() {
 	c.ConsecutiveFailures++
 	c.ConsecutiveSuccesses = 0
 }
 
+func (c *Counts) onSlowCall() {
+	c.TotalSlowCalls++
+}
+
 func (c *Counts) clear() {
 	c.Requests = 0
 	c.TotalSuccesses = 0
 	c.TotalFailures = 0
 	c.ConsecutiveSuccesses = 0
 	c.ConsecutiveFailures = 0
+	c.TotalSlowCalls = 0
 }
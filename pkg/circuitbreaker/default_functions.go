@@ -9,16 +9,31 @@ const divideByPercentage = 100.0
 // DefaultReadyToTrip returns true if the number of consecutive failures is higher than the failure rate threshold.
 // If there are not enough requests, the function returns always false.
 func DefaultReadyToTrip(minRequestsThreshold, failureRateThreshold int) func(counts Counts) bool {
+	return SlowCallReadyToTrip(minRequestsThreshold, failureRateThreshold, 0)
+}
+
+// SlowCallReadyToTrip returns true if the failure rate is higher than the failure rate threshold or
+// the slow call rate is higher than the slow call rate threshold. A slow call rate threshold of 0
+// disables the slow call check.
+// If there are not enough requests, the function returns always false.
+func SlowCallReadyToTrip(minRequestsThreshold, failureRateThreshold, slowCallRateThreshold int) func(counts Counts) bool {
 	return func(counts Counts) bool {
 		if int(counts.Requests) < minRequestsThreshold {
 			return false
 		}
-		errorRate := float64(counts.TotalFailures) / float64(counts.Requests)
-		threshold := float64(failureRateThreshold) / divideByPercentage
-		return errorRate >= threshold
+		if reachesRate(counts.TotalFailures, counts.Requests, failureRateThreshold) {
+			return true
+		}
+		return slowCallRateThreshold > 0 && reachesRate(counts.TotalSlowCalls, counts.Requests, slowCallRateThreshold)
 	}
 }
 
+func reachesRate(count, total uint32, rateThreshold int) bool {
+	rate := float64(count) / float64(total)
+	threshold := float64(rateThreshold) / divideByPercentage
+	return rate >= threshold
+}
+
 // DefaultIsSuccessful returns true if the error is not nil and is not the expected error.
 //
 // If the error is nil, the function returns true.
@@ -60,6 +60,61 @@ func TestDefaultReadyToTrip(t *testing.T) {
 	}
 }
 
+func TestSlowCallReadyToTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		minRequests  int
+		failureRate  int
+		slowCallRate int
+		counts       circuitbreaker.Counts
+		expected     bool
+	}{
+		{
+			name:         "slow call ready to trip should return false when min requests still not overpassed",
+			minRequests:  2,
+			failureRate:  50,
+			slowCallRate: 50,
+			counts:       circuitbreaker.Counts{Requests: 1, TotalSlowCalls: 1},
+			expected:     false,
+		},
+		{
+			name:         "slow call ready to trip should return true when slow call rate equals threshold",
+			minRequests:  2,
+			failureRate:  50,
+			slowCallRate: 50,
+			counts:       circuitbreaker.Counts{Requests: 2, TotalSuccesses: 2, TotalSlowCalls: 1},
+			expected:     true,
+		},
+		{
+			name:         "slow call ready to trip should return true when failure rate equals threshold",
+			minRequests:  2,
+			failureRate:  50,
+			slowCallRate: 50,
+			counts:       circuitbreaker.Counts{Requests: 2, TotalSuccesses: 1, TotalFailures: 1},
+			expected:     true,
+		},
+		{
+			name:         "slow call ready to trip should return false when slow call rate threshold is disabled",
+			minRequests:  2,
+			failureRate:  50,
+			slowCallRate: 0,
+			counts:       circuitbreaker.Counts{Requests: 2, TotalSuccesses: 2, TotalSlowCalls: 2},
+			expected:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			readyToTripFunc := circuitbreaker.SlowCallReadyToTrip(tt.minRequests, tt.failureRate, tt.slowCallRate)
+
+			result := readyToTripFunc(tt.counts)
+
+			if tt.expected != result {
+				t.Errorf("expected %t actual %t", tt.expected, result)
+			}
+		})
+	}
+}
+
 func TestDefaultIsSuccessful(t *testing.T) {
 	tests := []struct {
 		err         error
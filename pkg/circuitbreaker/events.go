@@ -0,0 +1,84 @@
+package circuitbreaker
+
+import (
+	"sync"
+	"time"
+)
+
+// EventType is the kind of Event a CircuitBreaker publishes.
+type EventType string
+
+// These constants are the event types:
+//
+// EventStateTransition is a change of state, from From to State.
+// EventCallRejected is a call rejected without reaching the backend, in the State it was rejected in.
+// EventSuccess and EventFailure are the outcomes of the calls, as counted by the CircuitBreaker.
+// EventIgnored is a call whose error is not recorded, see Settings.IsIgnored.
+const (
+	EventStateTransition EventType = "state-transition"
+	EventCallRejected    EventType = "call-rejected"
+	EventSuccess         EventType = "success"
+	EventFailure         EventType = "failure"
+	EventIgnored         EventType = "ignored"
+)
+
+// Event is something that happened to a CircuitBreaker.
+//
+// Name is the name of the CircuitBreaker, which is the route ID for the gateway circuit breakers.
+// State is the state of the CircuitBreaker once the event happened. From is the previous state of
+// the state transitions. Duration is the time the call took, for the successes, failures and ignored
+// calls, and Slow whether it was counted as slow, for the successes and failures only.
+type Event struct {
+	Time     time.Time     `json:"time"`
+	Type     EventType     `json:"type"`
+	Name     string        `json:"name"`
+	From     *State        `json:"from,omitempty"`
+	State    State         `json:"state"`
+	Duration time.Duration `json:"duration,omitempty"`
+	Slow     bool          `json:"slow,omitempty"`
+}
+
+// Listener receives the events of the circuit breakers.
+//
+// OnEvent is called synchronously while the CircuitBreaker lock is held: it must be quick and
+// must not call back into the CircuitBreaker.
+type Listener interface {
+	OnEvent(event Event)
+}
+
+// ListenerFunc is a function implementing Listener.
+type ListenerFunc func(event Event)
+
+// OnEvent calls the function.
+func (f ListenerFunc) OnEvent(event Event) {
+	f(event)
+}
+
+// EventBus dispatches the published events to its listeners, in subscription order.
+type EventBus struct {
+	listeners []Listener
+	mutex     sync.RWMutex
+}
+
+// NewEventBus creates a new event bus with the given listeners.
+func NewEventBus(listeners ...Listener) *EventBus {
+	return &EventBus{listeners: listeners}
+}
+
+// Subscribe adds the listeners to the bus.
+func (b *EventBus) Subscribe(listeners ...Listener) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.listeners = append(b.listeners, listeners...)
+}
+
+// Publish dispatches the event to every listener.
+func (b *EventBus) Publish(event Event) {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	for _, listener := range b.listeners {
+		listener.OnEvent(event)
+	}
+}
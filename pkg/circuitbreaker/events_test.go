@@ -0,0 +1,65 @@
+package circuitbreaker_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+)
+
+func TestEventBus_Publish(t *testing.T) {
+	var received []string
+	bus := circuitbreaker.NewEventBus(circuitbreaker.ListenerFunc(func(event circuitbreaker.Event) {
+		received = append(received, "first:"+event.Name)
+	}))
+	bus.Subscribe(circuitbreaker.ListenerFunc(func(event circuitbreaker.Event) {
+		received = append(received, "second:"+event.Name)
+	}))
+
+	bus.Publish(circuitbreaker.Event{Name: "r1"})
+
+	expected := []string{"first:r1", "second:r1"}
+	if !reflect.DeepEqual(expected, received) {
+		t.Errorf("expected %v actual %v", expected, received)
+	}
+}
+
+func TestCircuitBreaker_PublishesEvents(t *testing.T) {
+	var events []circuitbreaker.Event
+	cb := circuitbreaker.NewCircuitBreaker[bool](circuitbreaker.Settings{
+		Name:        "r1",
+		Timeout:     time.Minute,
+		ReadyToTrip: func(counts circuitbreaker.Counts) bool { return counts.ConsecutiveFailures >= 1 },
+		OnEvent: func(event circuitbreaker.Event) {
+			events = append(events, event)
+		},
+	})
+	errBackend := errors.New("backend failed")
+
+	_, _ = cb.Execute(func() (bool, error) { return true, nil })
+	_, _ = cb.Execute(func() (bool, error) { return false, errBackend })
+	_, _ = cb.Execute(func() (bool, error) { return true, nil })
+
+	closed := circuitbreaker.StateClosed
+	expected := []circuitbreaker.Event{
+		{Type: circuitbreaker.EventSuccess, Name: "r1", State: circuitbreaker.StateClosed},
+		{Type: circuitbreaker.EventFailure, Name: "r1", State: circuitbreaker.StateClosed},
+		{Type: circuitbreaker.EventStateTransition, Name: "r1", From: &closed, State: circuitbreaker.StateOpen},
+		{Type: circuitbreaker.EventCallRejected, Name: "r1", State: circuitbreaker.StateOpen},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events actual %v", len(expected), events)
+	}
+	for i, event := range events {
+		if event.Time.IsZero() {
+			t.Errorf("expected event %d to have a timestamp", i)
+		}
+		event.Time = time.Time{}
+		event.Duration = 0
+		if !reflect.DeepEqual(expected[i], event) {
+			t.Errorf("expected event %d %+v actual %+v", i, expected[i], event)
+		}
+	}
+}
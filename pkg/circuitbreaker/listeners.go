@@ -0,0 +1,181 @@
+package circuitbreaker
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+const defaultHistorySize = 100
+
+// NewLogListener returns a listener logging the events with the given logger. A circuit breaker
+// opening is logged at warn level and the other state transitions at info level. The rejected
+// calls and the call outcomes are logged at debug level, since there is one per request.
+func NewLogListener(logger *slog.Logger) ListenerFunc {
+	return func(event Event) {
+		switch event.Type {
+		case EventStateTransition:
+			level := slog.LevelInfo
+			if event.State == StateOpen {
+				level = slog.LevelWarn
+			}
+			logger.Log(context.Background(), level, "circuit breaker state changed",
+				"name", event.Name, "from", *event.From, "to", event.State)
+		case EventCallRejected:
+			logger.Debug("circuit breaker rejected call", "name", event.Name, "state", event.State)
+		case EventSuccess, EventFailure:
+			logger.Debug("circuit breaker recorded call", "name", event.Name, "outcome", event.Type,
+				"state", event.State, "duration", event.Duration, "slow", event.Slow)
+		case EventIgnored:
+			logger.Debug("circuit breaker ignored call", "name", event.Name, "state", event.State,
+				"duration", event.Duration)
+		default:
+		}
+	}
+}
+
+// BreakerMetrics are the event counters of a circuit breaker.
+type BreakerMetrics struct {
+	State       State  `json:"state"`
+	Transitions uint64 `json:"transitions"`
+	Rejected    uint64 `json:"rejected"`
+	Successes   uint64 `json:"successes"`
+	Failures    uint64 `json:"failures"`
+	Ignored     uint64 `json:"ignored"`
+	SlowCalls   uint64 `json:"slow-calls"`
+}
+
+// MetricsListener counts the events of every circuit breaker, by name.
+//
+// It is an http.Handler serving the counters as JSON.
+type MetricsListener struct {
+	metrics map[string]*BreakerMetrics
+	mutex   sync.Mutex
+}
+
+// NewMetricsListener creates a new metrics listener.
+func NewMetricsListener() *MetricsListener {
+	return &MetricsListener{metrics: make(map[string]*BreakerMetrics)}
+}
+
+// OnEvent counts the event.
+func (l *MetricsListener) OnEvent(event Event) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	metrics, ok := l.metrics[event.Name]
+	if !ok {
+		metrics = &BreakerMetrics{}
+		l.metrics[event.Name] = metrics
+	}
+	metrics.State = event.State
+	switch event.Type {
+	case EventStateTransition:
+		metrics.Transitions++
+	case EventCallRejected:
+		metrics.Rejected++
+	case EventSuccess:
+		metrics.Successes++
+	case EventFailure:
+		metrics.Failures++
+	case EventIgnored:
+		metrics.Ignored++
+	default:
+	}
+	if event.Slow {
+		metrics.SlowCalls++
+	}
+}
+
+// Snapshot returns a copy of the counters, by circuit breaker name.
+func (l *MetricsListener) Snapshot() map[string]BreakerMetrics {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	snapshot := make(map[string]BreakerMetrics, len(l.metrics))
+	for name, metrics := range l.metrics {
+		snapshot[name] = *metrics
+	}
+	return snapshot
+}
+
+// ServeHTTP writes the counters as JSON.
+func (l *MetricsListener) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, l.Snapshot())
+}
+
+// EventHistory keeps the last events of the circuit breakers in a bounded ring buffer.
+//
+// It is an http.Handler serving the events as JSON, oldest first. The name query parameter
+// keeps the events of a single circuit breaker, and the limit query parameter the last ones.
+type EventHistory struct {
+	events []Event
+	next   int
+	full   bool
+	mutex  sync.Mutex
+}
+
+// NewEventHistory creates a new event history keeping the last size events.
+// If size is less than or equal to 0, it keeps the last 100 events.
+func NewEventHistory(size int) *EventHistory {
+	if size <= 0 {
+		size = defaultHistorySize
+	}
+	return &EventHistory{events: make([]Event, size)}
+}
+
+// OnEvent records the event, overwriting the oldest one when the history is full.
+func (h *EventHistory) OnEvent(event Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.events[h.next] = event
+	h.next = (h.next + 1) % len(h.events)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Events returns the recorded events of the circuit breaker with the given name, oldest first.
+// An empty name returns the events of every circuit breaker. If limit is greater than 0, only
+// the last limit events are returned.
+func (h *EventHistory) Events(name string, limit int) []Event {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	ordered := h.events[:h.next]
+	if h.full {
+		ordered = append(append([]Event{}, h.events[h.next:]...), h.events[:h.next]...)
+	}
+	events := make([]Event, 0, len(ordered))
+	for _, event := range ordered {
+		if name == "" || event.Name == name {
+			events = append(events, event)
+		}
+	}
+	if limit > 0 && len(events) > limit {
+		events = events[len(events)-limit:]
+	}
+	return events
+}
+
+// ServeHTTP writes the recorded events as JSON.
+func (h *EventHistory) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	limit := 0
+	if rawLimit := r.URL.Query().Get("limit"); rawLimit != "" {
+		var err error
+		if limit, err = strconv.Atoi(rawLimit); err != nil || limit < 0 {
+			http.Error(w, "invalid limit: "+rawLimit, http.StatusBadRequest)
+			return
+		}
+	}
+	writeJSON(w, h.Events(r.URL.Query().Get("name"), limit))
+}
+
+func writeJSON(w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
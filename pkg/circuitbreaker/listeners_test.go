@@ -0,0 +1,211 @@
+package circuitbreaker_test
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+)
+
+func newTransition(name string, from, to circuitbreaker.State) circuitbreaker.Event {
+	return circuitbreaker.Event{Type: circuitbreaker.EventStateTransition, Name: name, From: &from, State: to}
+}
+
+func TestNewLogListener(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		event    circuitbreaker.Event
+	}{
+		{
+			name:     "log listener should log opening at warn level",
+			event:    newTransition("r1", circuitbreaker.StateClosed, circuitbreaker.StateOpen),
+			expected: "level=WARN msg=\"circuit breaker state changed\" name=r1 from=closed to=open\n",
+		},
+		{
+			name:     "log listener should log closing at info level",
+			event:    newTransition("r1", circuitbreaker.StateHalfOpen, circuitbreaker.StateClosed),
+			expected: "level=INFO msg=\"circuit breaker state changed\" name=r1 from=half-open to=closed\n",
+		},
+		{
+			name:     "log listener should log rejected calls at debug level",
+			event:    circuitbreaker.Event{Type: circuitbreaker.EventCallRejected, Name: "r1", State: circuitbreaker.StateOpen},
+			expected: "level=DEBUG msg=\"circuit breaker rejected call\" name=r1 state=open\n",
+		},
+		{
+			name: "log listener should log call outcomes at debug level",
+			event: circuitbreaker.Event{
+				Type:     circuitbreaker.EventFailure,
+				Name:     "r1",
+				State:    circuitbreaker.StateClosed,
+				Duration: time.Second,
+				Slow:     true,
+			},
+			expected: "level=DEBUG msg=\"circuit breaker recorded call\" name=r1 outcome=failure state=closed duration=1s slow=true\n",
+		},
+		{
+			name: "log listener should log ignored calls at debug level",
+			event: circuitbreaker.Event{
+				Type:     circuitbreaker.EventIgnored,
+				Name:     "r1",
+				State:    circuitbreaker.StateClosed,
+				Duration: time.Second,
+			},
+			expected: "level=DEBUG msg=\"circuit breaker ignored call\" name=r1 state=closed duration=1s\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+				Level: slog.LevelDebug,
+				ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+					if attr.Key == slog.TimeKey {
+						return slog.Attr{}
+					}
+					return attr
+				},
+			}))
+
+			circuitbreaker.NewLogListener(logger).OnEvent(tt.event)
+
+			if buf.String() != tt.expected {
+				t.Errorf("expected %q actual %q", tt.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestMetricsListener(t *testing.T) {
+	listener := circuitbreaker.NewMetricsListener()
+	listener.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventSuccess, Name: "r1"})
+	listener.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventFailure, Name: "r1", Slow: true})
+	listener.OnEvent(newTransition("r1", circuitbreaker.StateClosed, circuitbreaker.StateOpen))
+	listener.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventCallRejected, Name: "r1", State: circuitbreaker.StateOpen})
+	listener.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventSuccess, Name: "r2"})
+	listener.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventIgnored, Name: "r2"})
+
+	expected := map[string]circuitbreaker.BreakerMetrics{
+		"r1": {State: circuitbreaker.StateOpen, Transitions: 1, Rejected: 1, Successes: 1, Failures: 1, SlowCalls: 1},
+		"r2": {State: circuitbreaker.StateClosed, Successes: 1, Ignored: 1},
+	}
+	if snapshot := listener.Snapshot(); !reflect.DeepEqual(expected, snapshot) {
+		t.Errorf("expected %v actual %v", expected, snapshot)
+	}
+
+	recorder := httptest.NewRecorder()
+	listener.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/circuit-breakers/metrics", nil))
+
+	expectedBody := `{"r1":{"state":"open","transitions":1,"rejected":1,"successes":1,"failures":1,"ignored":0,"slow-calls":1},` +
+		`"r2":{"state":"closed","transitions":0,"rejected":0,"successes":1,"failures":0,"ignored":1,"slow-calls":0}}` + "\n"
+	if recorder.Body.String() != expectedBody {
+		t.Errorf("expected body %s actual %s", expectedBody, recorder.Body.String())
+	}
+}
+
+func TestEventHistory_Events(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    string
+		expected  []string
+		published []string
+		size      int
+		limit     int
+	}{
+		{
+			name:      "events should return the events oldest first",
+			size:      3,
+			published: []string{"r1", "r2"},
+			expected:  []string{"r1", "r2"},
+		},
+		{
+			name:      "events should drop the oldest events when full",
+			size:      3,
+			published: []string{"r1", "r2", "r3", "r4", "r5"},
+			expected:  []string{"r3", "r4", "r5"},
+		},
+		{
+			name:      "events should keep the events of the given name",
+			size:      3,
+			published: []string{"r1", "r2", "r1", "r2"},
+			filter:    "r2",
+			expected:  []string{"r2", "r2"},
+		},
+		{
+			name:      "events should keep the last events up to the limit",
+			size:      3,
+			published: []string{"r1", "r2", "r3", "r4"},
+			limit:     2,
+			expected:  []string{"r3", "r4"},
+		},
+		{
+			name:      "events should default to 100 events when size is not positive",
+			size:      0,
+			published: []string{"r1"},
+			expected:  []string{"r1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := circuitbreaker.NewEventHistory(tt.size)
+			for _, name := range tt.published {
+				history.OnEvent(circuitbreaker.Event{Name: name})
+			}
+
+			names := []string{}
+			for _, event := range history.Events(tt.filter, tt.limit) {
+				names = append(names, event.Name)
+			}
+
+			if !reflect.DeepEqual(tt.expected, names) {
+				t.Errorf("expected %v actual %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestEventHistory_ServeHTTP(t *testing.T) {
+	tests := []struct {
+		name           string
+		target         string
+		expectedBody   string
+		expectedStatus int
+	}{
+		{
+			name:           "serve http should write the events of the given name",
+			target:         "/circuit-breakers/events?name=r1&limit=1",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"time":"0001-01-01T00:00:00Z","type":"state-transition","name":"r1","from":"closed","state":"open"}]`,
+		},
+		{
+			name:           "serve http should return bad request when limit is not valid",
+			target:         "/circuit-breakers/events?limit=-1",
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   "invalid limit: -1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := circuitbreaker.NewEventHistory(10)
+			history.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventSuccess, Name: "r1"})
+			history.OnEvent(newTransition("r1", circuitbreaker.StateClosed, circuitbreaker.StateOpen))
+			history.OnEvent(circuitbreaker.Event{Type: circuitbreaker.EventSuccess, Name: "r2"})
+			recorder := httptest.NewRecorder()
+
+			history.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("expected status %d actual %d", tt.expectedStatus, recorder.Code)
+			}
+			if body := strings.TrimSpace(recorder.Body.String()); body != tt.expectedBody {
+				t.Errorf("expected body %s actual %s", tt.expectedBody, body)
+			}
+		})
+	}
+}
@@ -23,3 +23,8 @@ func (s State) String() string {
 		return "unknown"
 	}
 }
+
+// MarshalText implements encoding.TextMarshaler interface, so the states are encoded by name.
+func (s State) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
@@ -0,0 +1,201 @@
+package circuitbreaker
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	defaultCountWindowSize = 100
+	defaultTimeWindowSize  = 10
+)
+
+// ErrInvalidWindowType is returned when a sliding window type name is not known.
+var ErrInvalidWindowType = errors.New("invalid sliding window type")
+
+// WindowType is the kind of window the CircuitBreaker aggregates the closed-state outcomes over.
+type WindowType int
+
+// These constants are the window types.
+//
+// WindowFixed clears the outcomes at every Interval, as the original CircuitBreaker does.
+// WindowCountBased keeps the outcomes of the last calls.
+// WindowTimeBased keeps the outcomes of the last Interval, aggregated into buckets.
+const (
+	WindowFixed WindowType = iota
+	WindowCountBased
+	WindowTimeBased
+)
+
+// ParseWindowType returns the window type with the given name, case-insensitively.
+// An empty name is the fixed window.
+func ParseWindowType(name string) (WindowType, error) {
+	switch strings.ToLower(name) {
+	case "", "fixed":
+		return WindowFixed, nil
+	case "count-based":
+		return WindowCountBased, nil
+	case "time-based":
+		return WindowTimeBased, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidWindowType, name)
+	}
+}
+
+// String implements stringer interface.
+func (w WindowType) String() string {
+	switch w {
+	case WindowFixed:
+		return "fixed"
+	case WindowCountBased:
+		return "count-based"
+	case WindowTimeBased:
+		return "time-based"
+	default:
+		return "unknown"
+	}
+}
+
+// outcome is the result of a single call recorded in a sliding window.
+type outcome struct {
+	failure bool
+	slow    bool
+}
+
+// slidingWindow aggregates the call outcomes the breaker trips on.
+type slidingWindow interface {
+	record(now time.Time, result outcome)
+	totals(now time.Time) windowTotals
+	reset()
+}
+
+// windowTotals holds the aggregated outcomes of a sliding window.
+type windowTotals struct {
+	calls    uint32
+	failures uint32
+	slow     uint32
+}
+
+func (t *windowTotals) add(result outcome) {
+	t.calls++
+	if result.failure {
+		t.failures++
+	}
+	if result.slow {
+		t.slow++
+	}
+}
+
+func (t *windowTotals) remove(result outcome) {
+	t.calls--
+	if result.failure {
+		t.failures--
+	}
+	if result.slow {
+		t.slow--
+	}
+}
+
+// countWindow keeps the outcomes of the last size calls in a ring.
+type countWindow struct {
+	ring   []outcome
+	next   int
+	filled bool
+	sum    windowTotals
+}
+
+func newCountWindow(size int) *countWindow {
+	return &countWindow{ring: make([]outcome, size)}
+}
+
+func (w *countWindow) record(_ time.Time, result outcome) {
+	if w.filled {
+		w.sum.remove(w.ring[w.next])
+	}
+	w.ring[w.next] = result
+	w.sum.add(result)
+	w.next++
+	if w.next == len(w.ring) {
+		w.next = 0
+		w.filled = true
+	}
+}
+
+func (w *countWindow) totals(_ time.Time) windowTotals {
+	return w.sum
+}
+
+func (w *countWindow) reset() {
+	w.next = 0
+	w.filled = false
+	w.sum = windowTotals{}
+}
+
+// timeBucket holds the outcomes of one slice of a time window.
+type timeBucket struct {
+	windowTotals
+
+	slot int64
+}
+
+// timeWindow keeps the outcomes of the last length, split into buckets so that old
+// outcomes leave the window a bucket at a time instead of all at once.
+type timeWindow struct {
+	buckets []timeBucket
+	width   time.Duration
+}
+
+func newTimeWindow(length time.Duration, buckets int) *timeWindow {
+	return &timeWindow{
+		buckets: make([]timeBucket, buckets),
+		width:   max(length/time.Duration(buckets), 1),
+	}
+}
+
+func (w *timeWindow) record(now time.Time, result outcome) {
+	slot := now.UnixNano() / int64(w.width)
+	bucket := &w.buckets[slot%int64(len(w.buckets))]
+	if bucket.slot != slot {
+		*bucket = timeBucket{slot: slot}
+	}
+	bucket.add(result)
+}
+
+func (w *timeWindow) totals(now time.Time) windowTotals {
+	oldest := now.UnixNano()/int64(w.width) - int64(len(w.buckets)) + 1
+	var sum windowTotals
+	for _, bucket := range w.buckets {
+		if bucket.slot >= oldest {
+			sum.calls += bucket.calls
+			sum.failures += bucket.failures
+			sum.slow += bucket.slow
+		}
+	}
+	return sum
+}
+
+func (w *timeWindow) reset() {
+	clear(w.buckets)
+}
+
+//nolint:ireturn
+func newSlidingWindow(windowType WindowType, size int, length time.Duration) slidingWindow {
+	switch windowType {
+	case WindowCountBased:
+		if size <= 0 {
+			size = defaultCountWindowSize
+		}
+		return newCountWindow(size)
+	case WindowTimeBased:
+		if size <= 0 {
+			size = defaultTimeWindowSize
+		}
+		return newTimeWindow(length, size)
+	case WindowFixed:
+		return nil
+	default:
+		return nil
+	}
+}
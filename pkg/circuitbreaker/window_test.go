@@ -0,0 +1,151 @@
+package circuitbreaker //nolint:testpackage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func slowSucceed(cb *CircuitBreaker[bool], delay time.Duration) error {
+	_, err := cb.Execute(func() (bool, error) {
+		time.Sleep(delay)
+		return true, nil
+	})
+	return err
+}
+
+func TestParseWindowType(t *testing.T) {
+	for _, windowType := range []WindowType{WindowFixed, WindowCountBased, WindowTimeBased} {
+		parsed, err := ParseWindowType(windowType.String())
+		require.NoError(t, err)
+		assert.Equal(t, windowType, parsed)
+	}
+	parsed, err := ParseWindowType("")
+	require.NoError(t, err)
+	assert.Equal(t, WindowFixed, parsed)
+
+	_, err = ParseWindowType("rolling")
+	require.ErrorIs(t, err, ErrInvalidWindowType)
+	assert.Equal(t, "unknown", WindowType(-1).String())
+}
+
+func TestCountWindow(t *testing.T) {
+	window := newCountWindow(3)
+
+	window.record(time.Time{}, outcome{failure: true})
+	window.record(time.Time{}, outcome{slow: true})
+	window.record(time.Time{}, outcome{})
+	assert.Equal(t, windowTotals{calls: 3, failures: 1, slow: 1}, window.totals(time.Time{}))
+
+	// the oldest failure is pushed out of the window
+	window.record(time.Time{}, outcome{})
+	assert.Equal(t, windowTotals{calls: 3, failures: 0, slow: 1}, window.totals(time.Time{}))
+
+	window.reset()
+	assert.Equal(t, windowTotals{}, window.totals(time.Time{}))
+}
+
+func TestTimeWindow(t *testing.T) {
+	window := newTimeWindow(10*time.Second, 10)
+	start := time.Unix(1000, 0)
+
+	window.record(start, outcome{failure: true})
+	window.record(start.Add(5*time.Second), outcome{slow: true})
+	assert.Equal(t, windowTotals{calls: 2, failures: 1, slow: 1}, window.totals(start.Add(9*time.Second)))
+
+	// the first bucket leaves the window on its own, the second one is still in
+	assert.Equal(t, windowTotals{calls: 1, failures: 0, slow: 1}, window.totals(start.Add(10*time.Second)))
+
+	// a bucket reused after a full turn starts over
+	window.record(start.Add(20*time.Second), outcome{})
+	assert.Equal(t, windowTotals{calls: 1}, window.totals(start.Add(20*time.Second)))
+
+	window.reset()
+	assert.Equal(t, windowTotals{}, window.totals(start.Add(20*time.Second)))
+}
+
+func TestCountBasedCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker[bool](Settings{
+		WindowType:  WindowCountBased,
+		WindowSize:  4,
+		Interval:    time.Second,
+		ReadyToTrip: DefaultReadyToTrip(4, 50),
+	})
+	assert.Equal(t, time.Duration(0), cb.interval)
+
+	require.NoError(t, fail(cb))
+	for range 5 {
+		require.NoError(t, succeed(cb))
+	}
+	// the failure left the window: the rate is not stuck at the previous interval values
+	assert.Equal(t, Counts{4, 4, 0, 5, 0, 0}, cb.Counts())
+
+	require.NoError(t, fail(cb))
+	assert.Equal(t, StateClosed, cb.State())
+	require.NoError(t, fail(cb))
+	assert.Equal(t, StateOpen, cb.State())
+	assert.Equal(t, Counts{0, 0, 0, 0, 0, 0}, cb.Counts())
+}
+
+func TestSlidingWindowCircuitBreaker_KeepsOnlyConsecutiveCounts(t *testing.T) {
+	cb := NewCircuitBreaker[bool](Settings{
+		WindowType:       WindowCountBased,
+		WindowSize:       4,
+		SlowCallDuration: time.Millisecond,
+		ReadyToTrip:      DefaultReadyToTrip(4, 100),
+	})
+
+	for range 10 {
+		require.NoError(t, succeed(cb))
+	}
+	require.NoError(t, slowSucceed(cb, 2*time.Millisecond))
+	require.NoError(t, fail(cb))
+
+	// the totals live in the window only: the internal ones never grow while the breaker is closed
+	assert.Equal(t, Counts{0, 0, 0, 0, 1, 0}, cb.counts)
+	assert.Equal(t, Counts{4, 3, 1, 0, 1, 1}, cb.Counts())
+}
+
+func TestTimeBasedCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker[bool](Settings{
+		WindowType:  WindowTimeBased,
+		Interval:    time.Minute,
+		ReadyToTrip: DefaultReadyToTrip(2, 100),
+	})
+	assert.True(t, cb.expiry.IsZero())
+
+	require.NoError(t, fail(cb))
+	assert.Equal(t, Counts{1, 0, 1, 0, 1, 0}, cb.Counts())
+	require.NoError(t, fail(cb))
+	assert.Equal(t, StateOpen, cb.State())
+}
+
+func TestSlowCallCircuitBreaker(t *testing.T) {
+	cb := NewCircuitBreaker[bool](Settings{
+		WindowType:       WindowCountBased,
+		WindowSize:       10,
+		SlowCallDuration: time.Millisecond,
+		MaxRequests:      1,
+		ReadyToTrip:      SlowCallReadyToTrip(2, 50, 100),
+	})
+
+	require.NoError(t, slowSucceed(cb, 2*time.Millisecond))
+	assert.Equal(t, StateClosed, cb.State())
+	assert.Equal(t, Counts{1, 1, 0, 1, 0, 1}, cb.Counts())
+
+	// successful but slow calls trip the breaker
+	require.NoError(t, slowSucceed(cb, 2*time.Millisecond))
+	assert.Equal(t, StateOpen, cb.State())
+
+	// a slow half-open probe reopens it
+	pseudoSleep(cb, time.Duration(60)*time.Second)
+	assert.Equal(t, StateHalfOpen, cb.State())
+	require.NoError(t, slowSucceed(cb, 2*time.Millisecond))
+	assert.Equal(t, StateOpen, cb.State())
+
+	pseudoSleep(cb, time.Duration(60)*time.Second)
+	require.NoError(t, succeed(cb))
+	assert.Equal(t, StateClosed, cb.State())
+}
@@ -0,0 +1,149 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+const (
+	defaultInitialLimit = 20
+	defaultMinLimit     = 1
+	defaultMaxLimit     = 200
+	defaultHeadroom     = 4
+	defaultSmoothing    = 0.2
+	defaultBackoffRatio = 0.9
+	minGradient         = 0.5
+	appLimitedRatio     = 2
+)
+
+// AdaptiveSettings configures an AdaptiveLimiter:
+//
+// InitialLimit is the concurrency limit before any latency has been observed. Defaults to 20.
+//
+// MinLimit and MaxLimit bound the limit. They default to 1 and 200.
+//
+// Headroom is the number of requests allowed on top of the ones the backend serves at its
+// minimum latency. It is what makes the limit grow back once latency recovers. Defaults to 4.
+//
+// MinRTTWindow is how long the observed minimum latency is trusted. When it expires the
+// minimum is measured again, so a backend that became permanently slower, after a deploy or
+// a data growth, is not throttled forever. Zero keeps the first minimum for good.
+//
+// MaxQueue and QueueTimeout configure the wait queue, see NewLimiter.
+type AdaptiveSettings struct {
+	InitialLimit int
+	MinLimit     int
+	MaxLimit     int
+	Headroom     int
+	MinRTTWindow time.Duration
+	MaxQueue     int
+	QueueTimeout time.Duration
+}
+
+// AdaptiveLimiter is a Limiter whose limit follows the backend latency, using a gradient
+// algorithm in the spirit of TCP Vegas: the ratio between the minimum observed latency and
+// the latest sample shrinks the limit as soon as requests start to queue in the backend,
+// and the headroom grows it back while latency stays at its minimum.
+type AdaptiveLimiter struct {
+	minRTTExpiry time.Time
+	time         shared.TimeProvider
+	limiter      *Limiter
+	settings     AdaptiveSettings
+	estimate     float64
+	minRTT       time.Duration
+	mutex        sync.Mutex
+}
+
+// WithDefaults returns the settings with the defaults applied to the unset limits.
+func (s AdaptiveSettings) WithDefaults() AdaptiveSettings {
+	if s.MinLimit <= 0 {
+		s.MinLimit = defaultMinLimit
+	}
+	if s.MaxLimit <= 0 {
+		s.MaxLimit = defaultMaxLimit
+	}
+	if s.InitialLimit <= 0 {
+		s.InitialLimit = defaultInitialLimit
+	}
+	if s.Headroom <= 0 {
+		s.Headroom = defaultHeadroom
+	}
+	return s
+}
+
+// NewAdaptiveLimiter creates a new adaptive limiter with the given settings.
+func NewAdaptiveLimiter(time shared.TimeProvider, settings AdaptiveSettings) *AdaptiveLimiter {
+	settings = settings.WithDefaults()
+	initial := min(max(settings.InitialLimit, settings.MinLimit), settings.MaxLimit)
+	return &AdaptiveLimiter{
+		time:     time,
+		settings: settings,
+		estimate: float64(initial),
+		limiter:  NewLimiter(initial, settings.MaxQueue, settings.QueueTimeout),
+	}
+}
+
+// Acquire takes a slot. See Limiter.Acquire.
+func (a *AdaptiveLimiter) Acquire(ctx context.Context) error {
+	return a.limiter.Acquire(ctx)
+}
+
+// Release gives back a slot taken by a successful Acquire.
+func (a *AdaptiveLimiter) Release() {
+	a.limiter.Release()
+}
+
+// Limit returns the current concurrency limit.
+func (a *AdaptiveLimiter) Limit() int {
+	return a.limiter.Limit()
+}
+
+// InFlight returns the number of slots currently taken.
+func (a *AdaptiveLimiter) InFlight() int {
+	return a.limiter.InFlight()
+}
+
+// OnSample records the latency of a request that reached the backend and adjusts the
+// limit. It returns the new limit.
+func (a *AdaptiveLimiter) OnSample(rtt time.Duration) int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if rtt <= 0 {
+		return a.limiter.Limit()
+	}
+	now := a.time.Now()
+	if a.minRTT == 0 || rtt < a.minRTT || (!a.minRTTExpiry.IsZero() && now.After(a.minRTTExpiry)) {
+		a.minRTT = rtt
+		if a.settings.MinRTTWindow > 0 {
+			a.minRTTExpiry = now.Add(a.settings.MinRTTWindow)
+		}
+	}
+	gradient := max(minGradient, min(1, float64(a.minRTT)/float64(rtt)))
+	target := a.estimate*gradient + float64(a.settings.Headroom)
+	// A limit that is not being used says nothing about the backend capacity: growing it
+	// while traffic is low would let a later burst through unchecked.
+	if target > a.estimate && a.limiter.InFlight()*appLimitedRatio < int(a.estimate) {
+		return a.limiter.Limit()
+	}
+	return a.apply(a.estimate*(1-defaultSmoothing) + target*defaultSmoothing)
+}
+
+// OnDrop records a request that timed out in the backend and shrinks the limit. It
+// returns the new limit.
+func (a *AdaptiveLimiter) OnDrop() int {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.apply(a.estimate * defaultBackoffRatio)
+}
+
+// apply clamps the estimate to the configured bounds and updates the limiter. The caller
+// holds a.mutex.
+func (a *AdaptiveLimiter) apply(estimate float64) int {
+	a.estimate = min(max(estimate, float64(a.settings.MinLimit)), float64(a.settings.MaxLimit))
+	limit := int(a.estimate)
+	a.limiter.SetLimit(limit)
+	return limit
+}
@@ -0,0 +1,115 @@
+package concurrency_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+)
+
+type MockTimeProvider struct {
+	WantedTime time.Time
+}
+
+func (m *MockTimeProvider) Now() time.Time {
+	return m.WantedTime
+}
+
+func newBusyAdaptiveLimiter(t *testing.T, provider *MockTimeProvider, settings concurrency.AdaptiveSettings) *concurrency.AdaptiveLimiter {
+	t.Helper()
+	limiter := concurrency.NewAdaptiveLimiter(provider, settings)
+	for range limiter.Limit() {
+		if err := limiter.Acquire(t.Context()); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	return limiter
+}
+
+func TestAdaptiveLimiter_OnSample(t *testing.T) {
+	tests := []struct {
+		name       string
+		samples    []time.Duration
+		busy       bool
+		assertion  func(limit int) bool
+		expectText string
+	}{
+		{
+			name:       "on sample should grow the limit when latency stays at its minimum and the limit is used",
+			samples:    []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+			busy:       true,
+			assertion:  func(limit int) bool { return limit > 10 },
+			expectText: "greater than 10",
+		},
+		{
+			name:       "on sample should keep the limit when latency stays at its minimum but the limit is not used",
+			samples:    []time.Duration{10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond, 10 * time.Millisecond},
+			busy:       false,
+			assertion:  func(limit int) bool { return limit == 10 },
+			expectText: "equal to 10",
+		},
+		{
+			name:       "on sample should shrink the limit when latency rises above its minimum",
+			samples:    []time.Duration{10 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond, 40 * time.Millisecond},
+			busy:       true,
+			assertion:  func(limit int) bool { return limit < 10 },
+			expectText: "lower than 10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &MockTimeProvider{WantedTime: time.Now()}
+			settings := concurrency.AdaptiveSettings{InitialLimit: 10, Headroom: 2}
+			limiter := concurrency.NewAdaptiveLimiter(provider, settings)
+			if tt.busy {
+				limiter = newBusyAdaptiveLimiter(t, provider, settings)
+			}
+
+			var limit int
+			for _, sample := range tt.samples {
+				limit = limiter.OnSample(sample)
+			}
+
+			if !tt.assertion(limit) || limiter.Limit() != limit {
+				t.Errorf("expected limit %s actual %d", tt.expectText, limit)
+			}
+		})
+	}
+}
+
+func TestAdaptiveLimiter_OnDrop(t *testing.T) {
+	limiter := concurrency.NewAdaptiveLimiter(&MockTimeProvider{}, concurrency.AdaptiveSettings{
+		InitialLimit: 10,
+		MinLimit:     8,
+	})
+
+	for range 10 {
+		limiter.OnDrop()
+	}
+
+	if limiter.Limit() != 8 {
+		t.Errorf("expected limit to shrink down to the minimum 8 actual %d", limiter.Limit())
+	}
+}
+
+func TestAdaptiveLimiter_OnSample_MinRTTWindowExpires(t *testing.T) {
+	provider := &MockTimeProvider{WantedTime: time.Now()}
+	limiter := newBusyAdaptiveLimiter(t, provider, concurrency.AdaptiveSettings{
+		InitialLimit: 10,
+		Headroom:     2,
+		MinRTTWindow: time.Minute,
+	})
+	limiter.OnSample(10 * time.Millisecond)
+	provider.WantedTime = provider.WantedTime.Add(2 * time.Minute)
+
+	// The backend is now permanently slower: once the window expires the new latency
+	// becomes the minimum and the limit grows instead of shrinking.
+	limit := limiter.OnSample(40 * time.Millisecond)
+	for range 4 {
+		limit = limiter.OnSample(40 * time.Millisecond)
+	}
+
+	if limit <= 10 {
+		t.Errorf("expected the limit to grow back after the minimum latency window expired, actual %d", limit)
+	}
+}
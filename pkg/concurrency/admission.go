@@ -0,0 +1,150 @@
+package concurrency
+
+import (
+	"sync"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+const (
+	defaultClassHeadroom  = 25
+	defaultRetryAfter     = time.Second
+	queueDelayWindow      = time.Second
+	queueDelaySmoothing   = 0.2
+	divideByPercentage    = 100.0
+	admissionFullPressure = 1.0
+)
+
+// AdmissionSettings configures an AdmissionController:
+//
+// MaxInFlight is the gateway-wide number of in-flight requests above which low priority
+// requests are shed. Every request the gateway handles counts, whether its route has a
+// priority filter or not. Zero disables the in-flight threshold.
+//
+// MaxQueueDelay is the time requests may spend inside the gateway before reaching the backend
+// above which low priority requests are shed. Zero disables the queue delay threshold.
+//
+// ClassHeadroom is the extra load, as a percentage of the thresholds, each class above the
+// lowest one tolerates before being shed. With the default 25, normal requests are shed at
+// 125% of the thresholds and high requests at 150%. Critical requests are never shed.
+//
+// RetryAfter is the delay suggested to shed clients. Defaults to one second.
+type AdmissionSettings struct {
+	MaxInFlight   int
+	MaxQueueDelay time.Duration
+	ClassHeadroom int
+	RetryAfter    time.Duration
+}
+
+// DefaultAdmissionController is the gateway-wide admission controller used by the priority
+// filters. It admits every request until it is configured.
+//
+//nolint:gochecknoglobals
+var DefaultAdmissionController = NewAdmissionController(&shared.RealTime{}, AdmissionSettings{})
+
+// AdmissionController decides, gateway-wide, which requests are admitted under load. It
+// tracks the in-flight requests and the time they spend queued inside the gateway, and
+// sheds the lowest priority classes first once either passes its threshold.
+//
+// The gateway handler counts every request with Enter and Done, and the priority filters
+// ask for admission with Admit.
+type AdmissionController struct {
+	queueDelayAt time.Time
+	time         shared.TimeProvider
+	settings     AdmissionSettings
+	inFlight     int
+	queueDelay   time.Duration
+	mutex        sync.Mutex
+}
+
+// NewAdmissionController creates a new admission controller with the given settings.
+func NewAdmissionController(time shared.TimeProvider, settings AdmissionSettings) *AdmissionController {
+	controller := &AdmissionController{time: time}
+	controller.Configure(settings)
+	return controller
+}
+
+// Configure replaces the controller settings. In-flight requests are kept.
+func (c *AdmissionController) Configure(settings AdmissionSettings) {
+	if settings.ClassHeadroom <= 0 {
+		settings.ClassHeadroom = defaultClassHeadroom
+	}
+	if settings.RetryAfter <= 0 {
+		settings.RetryAfter = defaultRetryAfter
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.settings = settings
+}
+
+// Enter counts a request as in-flight until Done is called.
+func (c *AdmissionController) Enter() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inFlight++
+}
+
+// Admit reports whether a request of the given class is admitted. The request is expected
+// to be counted with Enter already, so the in-flight threshold is checked against the other
+// requests in flight.
+func (c *AdmissionController) Admit(priority Priority) bool {
+	if priority >= PriorityCritical {
+		return true
+	}
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	headroom := float64(c.settings.ClassHeadroom) / divideByPercentage
+	return c.pressure() < admissionFullPressure+headroom*float64(priority)
+}
+
+// Done marks a request counted with Enter as finished.
+func (c *AdmissionController) Done() {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.inFlight--
+}
+
+// ObserveQueueDelay records the time an admitted request spent inside the gateway before
+// reaching the backend.
+//
+// The delay only counts for a short window after its last sample: while the lowest classes
+// are shed no sample may arrive for a while, and a stale delay would shed them forever.
+func (c *AdmissionController) ObserveQueueDelay(delay time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	now := c.time.Now()
+	if now.Sub(c.queueDelayAt) > queueDelayWindow {
+		c.queueDelay = delay
+	} else {
+		c.queueDelay = time.Duration(float64(c.queueDelay)*(1-queueDelaySmoothing) + float64(delay)*queueDelaySmoothing)
+	}
+	c.queueDelayAt = now
+}
+
+// InFlight returns the number of requests counted with Enter not finished yet.
+func (c *AdmissionController) InFlight() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.inFlight
+}
+
+// RetryAfter returns the delay suggested to shed clients.
+func (c *AdmissionController) RetryAfter() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.settings.RetryAfter
+}
+
+// pressure returns the load relative to the thresholds: 1 means a threshold is reached.
+// The request asking is not part of the in-flight load. The caller holds c.mutex.
+func (c *AdmissionController) pressure() float64 {
+	var pressure float64
+	if c.settings.MaxInFlight > 0 {
+		pressure = float64(max(c.inFlight-1, 0)) / float64(c.settings.MaxInFlight)
+	}
+	if c.settings.MaxQueueDelay > 0 && c.time.Now().Sub(c.queueDelayAt) <= queueDelayWindow {
+		pressure = max(pressure, float64(c.queueDelay)/float64(c.settings.MaxQueueDelay))
+	}
+	return pressure
+}
@@ -0,0 +1,111 @@
+package concurrency_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+)
+
+func TestAdmissionController_Admit_ShedsLowestClassesFirst(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&MockTimeProvider{}, concurrency.AdmissionSettings{
+		MaxInFlight: 4,
+	})
+	for range 4 {
+		if !enterAndAdmit(controller, concurrency.PriorityNormal) {
+			t.Fatalf("expected normal requests admitted under the threshold")
+		}
+	}
+
+	if enterAndAdmit(controller, concurrency.PriorityLow) {
+		t.Errorf("expected low requests shed at the threshold")
+	}
+	if !enterAndAdmit(controller, concurrency.PriorityNormal) {
+		t.Errorf("expected normal requests admitted within their headroom")
+	}
+	if enterAndAdmit(controller, concurrency.PriorityNormal) {
+		t.Errorf("expected normal requests shed past their headroom")
+	}
+	if !enterAndAdmit(controller, concurrency.PriorityHigh) {
+		t.Errorf("expected high requests admitted within their headroom")
+	}
+	if controller.InFlight() != 6 {
+		t.Errorf("expected 6 in flight actual %d", controller.InFlight())
+	}
+}
+
+// enterAndAdmit counts a request as in-flight and asks for its admission, finishing it
+// when it is shed, as the gateway handler does.
+func enterAndAdmit(controller *concurrency.AdmissionController, priority concurrency.Priority) bool {
+	controller.Enter()
+	if !controller.Admit(priority) {
+		controller.Done()
+		return false
+	}
+	return true
+}
+
+func TestAdmissionController_Admit_NeverShedsCritical(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&MockTimeProvider{}, concurrency.AdmissionSettings{
+		MaxInFlight: 1,
+	})
+	for range 10 {
+		if !enterAndAdmit(controller, concurrency.PriorityCritical) {
+			t.Fatalf("expected critical requests always admitted")
+		}
+	}
+}
+
+func TestAdmissionController_Done_AdmitsAgain(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&MockTimeProvider{}, concurrency.AdmissionSettings{
+		MaxInFlight: 1,
+	})
+	enterAndAdmit(controller, concurrency.PriorityLow)
+	if enterAndAdmit(controller, concurrency.PriorityLow) {
+		t.Fatalf("expected low requests shed at the threshold")
+	}
+
+	controller.Done()
+
+	if !enterAndAdmit(controller, concurrency.PriorityLow) {
+		t.Errorf("expected low requests admitted once the load is gone")
+	}
+}
+
+func TestAdmissionController_ObserveQueueDelay(t *testing.T) {
+	provider := &MockTimeProvider{WantedTime: time.Now()}
+	controller := concurrency.NewAdmissionController(provider, concurrency.AdmissionSettings{
+		MaxQueueDelay: 100 * time.Millisecond,
+	})
+
+	controller.ObserveQueueDelay(200 * time.Millisecond)
+
+	if controller.Admit(concurrency.PriorityLow) {
+		t.Errorf("expected low requests shed while the queue delay is over the threshold")
+	}
+	provider.WantedTime = provider.WantedTime.Add(2 * time.Second)
+	if !controller.Admit(concurrency.PriorityLow) {
+		t.Errorf("expected low requests admitted once the queue delay sample is stale")
+	}
+}
+
+func TestAdmissionController_Configure(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&MockTimeProvider{}, concurrency.AdmissionSettings{})
+	if controller.RetryAfter() != time.Second {
+		t.Errorf("expected default retry after 1s actual %s", controller.RetryAfter())
+	}
+	for range 100 {
+		if !enterAndAdmit(controller, concurrency.PriorityLow) {
+			t.Fatalf("expected every request admitted when not configured")
+		}
+	}
+
+	controller.Configure(concurrency.AdmissionSettings{MaxInFlight: 50, RetryAfter: 5 * time.Second})
+
+	if enterAndAdmit(controller, concurrency.PriorityLow) {
+		t.Errorf("expected low requests shed after configuring the threshold")
+	}
+	if controller.RetryAfter() != 5*time.Second {
+		t.Errorf("expected retry after 5s actual %s", controller.RetryAfter())
+	}
+}
@@ -0,0 +1,78 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// KeyedLimiter keeps an independent Limiter per key, so requests sharing a key (a client
+// IP, a path, a header value) are capped together without affecting other keys.
+//
+// A key's limiter only lives while it has in-flight or queued requests: memory follows the
+// number of active keys, not the number of keys ever seen.
+type KeyedLimiter struct {
+	limiters     map[string]*keyedEntry
+	queueTimeout time.Duration
+	limit        int
+	maxQueue     int
+	mutex        sync.Mutex
+}
+
+type keyedEntry struct {
+	limiter *Limiter
+	refs    int
+}
+
+// NewKeyedLimiter creates a new keyed limiter. The arguments apply to the limiter of each
+// key, see NewLimiter.
+func NewKeyedLimiter(limit, maxQueue int, queueTimeout time.Duration) *KeyedLimiter {
+	return &KeyedLimiter{
+		limiters:     make(map[string]*keyedEntry),
+		limit:        limit,
+		maxQueue:     maxQueue,
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Acquire takes a slot of the given key limiter. On success it returns the function that
+// gives the slot back; it must be called exactly once. Errors are the ones of Limiter.Acquire.
+func (k *KeyedLimiter) Acquire(ctx context.Context, key string) (func(), error) {
+	entry := k.retain(key)
+	if err := entry.limiter.Acquire(ctx); err != nil {
+		k.unretain(key, entry)
+		return nil, err
+	}
+	return func() {
+		entry.limiter.Release()
+		k.unretain(key, entry)
+	}, nil
+}
+
+// Len returns the number of keys with in-flight or queued requests.
+func (k *KeyedLimiter) Len() int {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return len(k.limiters)
+}
+
+func (k *KeyedLimiter) retain(key string) *keyedEntry {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	entry, ok := k.limiters[key]
+	if !ok {
+		entry = &keyedEntry{limiter: NewLimiter(k.limit, k.maxQueue, k.queueTimeout)}
+		k.limiters[key] = entry
+	}
+	entry.refs++
+	return entry
+}
+
+func (k *KeyedLimiter) unretain(key string, entry *keyedEntry) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	entry.refs--
+	if entry.refs == 0 {
+		delete(k.limiters, key)
+	}
+}
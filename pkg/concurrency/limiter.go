@@ -0,0 +1,148 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrLimitExceeded is returned when every slot is taken and the wait queue is full.
+var ErrLimitExceeded = errors.New("concurrency limit exceeded")
+
+// ErrQueueTimeout is returned when a queued request did not get a slot in time.
+var ErrQueueTimeout = errors.New("concurrency queue timeout")
+
+// Limiter is a bulkhead: it caps the number of in-flight requests and lets a bounded
+// number of extra requests wait, in arrival order, for a slot to become free.
+type Limiter struct {
+	waiters      []chan struct{}
+	queueTimeout time.Duration
+	limit        int
+	inFlight     int
+	maxQueue     int
+	mutex        sync.Mutex
+}
+
+// NewLimiter creates a new limiter allowing limit concurrent requests.
+//
+// maxQueue is the number of requests allowed to wait for a slot once the limit is
+// reached; 0 rejects them straight away. queueTimeout bounds how long a queued request
+// waits; 0 means it waits until its context is done.
+func NewLimiter(limit, maxQueue int, queueTimeout time.Duration) *Limiter {
+	return &Limiter{
+		limit:        limit,
+		maxQueue:     maxQueue,
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Acquire takes a slot, waiting in the queue when the limit is reached.
+//
+// It returns ErrLimitExceeded when the queue is full, ErrQueueTimeout when the queue
+// timeout expires and the context error when the context is done while waiting.
+// A nil error means the caller owns a slot and must give it back with Release.
+func (l *Limiter) Acquire(ctx context.Context) error {
+	l.mutex.Lock()
+	// Queued requests go first: a newcomer must not overtake them.
+	if l.inFlight < l.limit && len(l.waiters) == 0 {
+		l.inFlight++
+		l.mutex.Unlock()
+		return nil
+	}
+	if len(l.waiters) >= l.maxQueue {
+		l.mutex.Unlock()
+		return ErrLimitExceeded
+	}
+	ready := make(chan struct{})
+	l.waiters = append(l.waiters, ready)
+	l.mutex.Unlock()
+
+	var timeout <-chan time.Time
+	if l.queueTimeout > 0 {
+		timer := time.NewTimer(l.queueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	var err error
+	select {
+	case <-ready:
+		return nil
+	case <-timeout:
+		err = ErrQueueTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.removeWaiter(ready) {
+		// The slot was granted while giving up: hand it to the next waiter.
+		l.releaseLocked()
+	}
+	return err
+}
+
+// Release gives back a slot taken by a successful Acquire.
+func (l *Limiter) Release() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.releaseLocked()
+}
+
+// SetLimit changes the number of concurrent requests allowed. Lowering it never
+// interrupts in-flight requests: new ones are held back until enough of them finish.
+func (l *Limiter) SetLimit(limit int) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.limit = limit
+	l.grantLocked()
+}
+
+// Limit returns the number of concurrent requests allowed.
+func (l *Limiter) Limit() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.limit
+}
+
+// InFlight returns the number of slots currently taken.
+func (l *Limiter) InFlight() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.inFlight
+}
+
+// Queued returns the number of requests waiting for a slot.
+func (l *Limiter) Queued() int {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return len(l.waiters)
+}
+
+func (l *Limiter) releaseLocked() {
+	l.inFlight--
+	l.grantLocked()
+}
+
+// grantLocked hands free slots to the waiters in arrival order. The caller holds l.mutex.
+func (l *Limiter) grantLocked() {
+	for l.inFlight < l.limit && len(l.waiters) > 0 {
+		ready := l.waiters[0]
+		l.waiters[0] = nil
+		l.waiters = l.waiters[1:]
+		l.inFlight++
+		close(ready)
+	}
+}
+
+// removeWaiter removes the given waiter from the queue. It reports false when the waiter
+// was no longer queued because a slot had already been granted to it.
+func (l *Limiter) removeWaiter(ready chan struct{}) bool {
+	for i, waiter := range l.waiters {
+		if waiter == ready {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
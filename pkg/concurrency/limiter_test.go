@@ -0,0 +1,154 @@
+package concurrency_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+)
+
+func TestLimiter_Acquire(t *testing.T) {
+	tests := []struct {
+		expectedErr  error
+		name         string
+		queueTimeout time.Duration
+		limit        int
+		maxQueue     int
+		taken        int
+	}{
+		{
+			name:        "acquire should succeed when slots are free",
+			limit:       2,
+			taken:       1,
+			expectedErr: nil,
+		},
+		{
+			name:        "acquire should return limit exceeded when slots are taken and there is no queue",
+			limit:       1,
+			taken:       1,
+			expectedErr: concurrency.ErrLimitExceeded,
+		},
+		{
+			name:         "acquire should return queue timeout when no slot is freed in time",
+			limit:        1,
+			maxQueue:     1,
+			taken:        1,
+			queueTimeout: time.Millisecond,
+			expectedErr:  concurrency.ErrQueueTimeout,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := concurrency.NewLimiter(tt.limit, tt.maxQueue, tt.queueTimeout)
+			for range tt.taken {
+				if err := limiter.Acquire(t.Context()); err != nil {
+					t.Fatalf("unexpected error %v", err)
+				}
+			}
+
+			err := limiter.Acquire(t.Context())
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected err %v actual %v", tt.expectedErr, err)
+			}
+			if limiter.Queued() != 0 {
+				t.Errorf("expected empty queue actual %d", limiter.Queued())
+			}
+		})
+	}
+}
+
+func TestLimiter_Acquire_QueuedRequestGetsReleasedSlot(t *testing.T) {
+	limiter := concurrency.NewLimiter(1, 1, time.Second)
+	if err := limiter.Acquire(t.Context()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	acquired := make(chan error)
+	go func() {
+		acquired <- limiter.Acquire(t.Context())
+	}()
+	waitFor(t, func() bool { return limiter.Queued() == 1 })
+
+	limiter.Release()
+
+	if err := <-acquired; err != nil {
+		t.Fatalf("expected queued request to get the slot, actual error %v", err)
+	}
+	if limiter.InFlight() != 1 {
+		t.Errorf("expected 1 in flight actual %d", limiter.InFlight())
+	}
+}
+
+func TestLimiter_Acquire_ContextDoneWhileQueued(t *testing.T) {
+	limiter := concurrency.NewLimiter(1, 1, 0)
+	if err := limiter.Acquire(t.Context()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	err := limiter.Acquire(ctx)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected err %v actual %v", context.Canceled, err)
+	}
+	if limiter.InFlight() != 1 || limiter.Queued() != 0 {
+		t.Errorf("expected 1 in flight and empty queue actual %d and %d", limiter.InFlight(), limiter.Queued())
+	}
+}
+
+func TestLimiter_SetLimit(t *testing.T) {
+	limiter := concurrency.NewLimiter(1, 1, time.Second)
+	if err := limiter.Acquire(t.Context()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	acquired := make(chan error)
+	go func() {
+		acquired <- limiter.Acquire(t.Context())
+	}()
+	waitFor(t, func() bool { return limiter.Queued() == 1 })
+
+	limiter.SetLimit(2)
+
+	if err := <-acquired; err != nil {
+		t.Fatalf("expected raised limit to admit the queued request, actual error %v", err)
+	}
+	if limiter.Limit() != 2 {
+		t.Errorf("expected limit 2 actual %d", limiter.Limit())
+	}
+}
+
+func TestKeyedLimiter_Acquire(t *testing.T) {
+	limiter := concurrency.NewKeyedLimiter(1, 0, 0)
+
+	releaseA, err := limiter.Acquire(t.Context(), "a")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err = limiter.Acquire(t.Context(), "a"); !errors.Is(err, concurrency.ErrLimitExceeded) {
+		t.Errorf("expected err %v actual %v", concurrency.ErrLimitExceeded, err)
+	}
+	releaseB, err := limiter.Acquire(t.Context(), "b")
+	if err != nil {
+		t.Fatalf("expected other key to be independent, actual error %v", err)
+	}
+	releaseA()
+	releaseB()
+
+	if limiter.Len() != 0 {
+		t.Errorf("expected idle keys to be dropped, actual %d keys", limiter.Len())
+	}
+}
+
+func waitFor(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
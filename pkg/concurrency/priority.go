@@ -0,0 +1,53 @@
+package concurrency
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidPriority is returned when a priority class name is not known.
+var ErrInvalidPriority = errors.New("invalid priority class")
+
+// Priority is the class of a request for load shedding. Lower classes are shed first.
+type Priority int
+
+// These constants are the priority classes, from the first to be shed to the never shed.
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+	PriorityCritical
+)
+
+// ParsePriority returns the priority class with the given name, case-insensitively.
+func ParsePriority(name string) (Priority, error) {
+	switch strings.ToLower(name) {
+	case "low":
+		return PriorityLow, nil
+	case "normal":
+		return PriorityNormal, nil
+	case "high":
+		return PriorityHigh, nil
+	case "critical":
+		return PriorityCritical, nil
+	default:
+		return 0, fmt.Errorf("%w: %s", ErrInvalidPriority, name)
+	}
+}
+
+// String implements stringer interface.
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	case PriorityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
@@ -0,0 +1,60 @@
+package concurrency_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		input       string
+		expected    concurrency.Priority
+	}{
+		{
+			name:        "parse should succeed when class is low",
+			input:       "low",
+			expected:    concurrency.PriorityLow,
+			expectedErr: nil,
+		},
+		{
+			name:        "parse should succeed when class is critical in upper case",
+			input:       "CRITICAL",
+			expected:    concurrency.PriorityCritical,
+			expectedErr: nil,
+		},
+		{
+			name:        "parse should return error when class is unknown",
+			input:       "urgent",
+			expected:    concurrency.PriorityLow,
+			expectedErr: errors.New("invalid priority class: urgent"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			priority, err := concurrency.ParsePriority(tt.input)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if priority != tt.expected {
+				t.Errorf("expected priority %s actual %s", tt.expected, priority)
+			}
+		})
+	}
+}
+
+func TestPriority_String(t *testing.T) {
+	for _, priority := range []concurrency.Priority{
+		concurrency.PriorityLow, concurrency.PriorityNormal, concurrency.PriorityHigh, concurrency.PriorityCritical,
+	} {
+		parsed, err := concurrency.ParsePriority(priority.String())
+		if err != nil || parsed != priority {
+			t.Errorf("expected %s to round trip actual %s err %v", priority, parsed, err)
+		}
+	}
+}
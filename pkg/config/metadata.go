@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+// ErrUnknownMetadata is the error returned when a filter argument references a route metadata key that does
+// not exist.
+var ErrUnknownMetadata = errors.New("unknown route metadata")
+
+const (
+	metadataReferencePrefix = "${metadata."
+	metadataReferenceSuffix = "}"
+)
+
+// expandMetadata returns a copy of the filters whose string arguments, in lists and maps too, have their
+// references to the route metadata replaced. An argument that is a single reference is replaced by the
+// metadata value as is, keeping its type. The references within a longer string are replaced by their
+// value formatted.
+func expandMetadata(route Route, filters []ParameterizedItem) ([]ParameterizedItem, error) {
+	expanded := make([]ParameterizedItem, 0, len(filters))
+	for _, filter := range filters {
+		args, missing, ok := expandMetadataMap(route.Metadata, filter.Args)
+		if !ok {
+			return nil, fmt.Errorf("%w: route %s: filter %s: %s", ErrUnknownMetadata, route.ID, filter.Name, missing)
+		}
+		expanded = append(expanded, ParameterizedItem{Name: filter.Name, Args: args})
+	}
+	return expanded, nil
+}
+
+func expandMetadataMap(metadata, values map[string]any) (map[string]any, string, bool) {
+	if values == nil {
+		return nil, "", true
+	}
+	expanded := make(map[string]any, len(values))
+	for key, value := range values {
+		expandedValue, missing, ok := expandMetadataValue(metadata, value)
+		if !ok {
+			return nil, missing, false
+		}
+		expanded[key] = expandedValue
+	}
+	return expanded, "", true
+}
+
+func expandMetadataValue(metadata map[string]any, value any) (any, string, bool) {
+	switch typed := value.(type) {
+	case string:
+		return expandMetadataString(metadata, typed)
+	case []any:
+		expanded := make([]any, 0, len(typed))
+		for _, item := range typed {
+			expandedItem, missing, ok := expandMetadataValue(metadata, item)
+			if !ok {
+				return nil, missing, false
+			}
+			expanded = append(expanded, expandedItem)
+		}
+		return expanded, "", true
+	case map[string]any:
+		return expandMetadataMap(metadata, typed)
+	default:
+		return value, "", true
+	}
+}
+
+func expandMetadataString(metadata map[string]any, value string) (any, string, bool) {
+	if !strings.Contains(value, metadataReferencePrefix) {
+		return value, "", true
+	}
+	if key, ok := singleMetadataReference(value); ok {
+		resolved, found := shared.LookupKey(metadata, key)
+		return resolved, key, found
+	}
+	var builder strings.Builder
+	rest := value
+	for {
+		before, after, found := strings.Cut(rest, metadataReferencePrefix)
+		builder.WriteString(before)
+		if !found {
+			return builder.String(), "", true
+		}
+		if strings.HasSuffix(before, "$") {
+			// An escaped reference is kept as is, for the request-time expressions to unescape it.
+			builder.WriteString(metadataReferencePrefix)
+			rest = after
+			continue
+		}
+		key, next, closed := strings.Cut(after, metadataReferenceSuffix)
+		if !closed {
+			builder.WriteString(metadataReferencePrefix + after)
+			return builder.String(), "", true
+		}
+		resolved, ok := shared.LookupKey(metadata, key)
+		if !ok {
+			return nil, key, false
+		}
+		builder.WriteString(fmt.Sprint(resolved))
+		rest = next
+	}
+}
+
+// singleMetadataReference returns the key of the value when the value is a single metadata reference.
+func singleMetadataReference(value string) (string, bool) {
+	key, ok := strings.CutPrefix(value, metadataReferencePrefix)
+	if !ok {
+		return "", false
+	}
+	key, ok = strings.CutSuffix(key, metadataReferenceSuffix)
+	if !ok || strings.Contains(key, metadataReferenceSuffix) {
+		return "", false
+	}
+	return key, true
+}
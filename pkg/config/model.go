@@ -8,33 +8,119 @@ type Config struct {
 }
 
 // Gateway represents the gateway config.
+//
+// The global request and response sizes limit, in bytes, the bodies of the requests and responses of every
+// route. A route can set its own limits, and a negative one disables the limit for the route.
 type Gateway struct {
-	HTTPClient    *HTTPClient         `json:"httpclient"     yaml:"httpclient"`
-	Routes        []Route             `json:"routes"         yaml:"routes"         validate:"required,min=1,dive"`
-	GlobalFilters []ParameterizedItem `json:"global-filters" yaml:"global-filters" validate:"dive"`
-	GlobalTimeout Duration            `json:"global-timeout" yaml:"global-timeout"`
+	HTTPClient         *HTTPClient         `json:"httpclient"           yaml:"httpclient"`
+	Routes             []Route             `json:"routes"               yaml:"routes"               validate:"required,min=1,dive"`
+	GlobalFilters      []ParameterizedItem `json:"global-filters"       yaml:"global-filters"       validate:"dive"`
+	LoadShedding       *LoadShedding       `json:"load-shedding"        yaml:"load-shedding"`
+	GlobalTimeout      Duration            `json:"global-timeout"       yaml:"global-timeout"`
+	GlobalRequestSize  int64               `json:"global-request-size"  yaml:"global-request-size"`
+	GlobalResponseSize int64               `json:"global-response-size" yaml:"global-response-size"`
 }
 
 // Route represents the gateway route config.
+//
+// The routes are matched by order, the lower first. The routes with the same order, 0 by default, are
+// matched in config order.
+//
+// The metadata is arbitrary data carried into the gateway route. The string filter arguments can reference
+// it as '${metadata.key}', replaced when the route is built. The other '${...}' references in header, parameter
+// and path filter values are request-time expressions (see filter.Expression): the route fails to build when
+// they reference an unknown variable. A literal '${' is written as '$${'.
+//
+// The request and response sizes limit, in bytes, the bodies of the requests and responses of the route. They
+// default to the global ones, and a negative one disables the limit.
 type Route struct {
 	ID             string              `json:"id"              yaml:"id"              validate:"required"`
 	URI            string              `json:"uri"             yaml:"uri"             validate:"required"`
 	Predicates     []ParameterizedItem `json:"predicates"      yaml:"predicates"      validate:"dive"`
 	Filters        []ParameterizedItem `json:"filters"         yaml:"filters"         validate:"dive"`
 	Timeout        Duration            `json:"timeout"         yaml:"timeout"`
+	RequestSize    int64               `json:"request-size"    yaml:"request-size"`
+	ResponseSize   int64               `json:"response-size"   yaml:"response-size"`
 	CircuitBreaker CircuitBreaker      `json:"circuit-breaker" yaml:"circuit-breaker"`
+	Order          int                 `json:"order"           yaml:"order"`
+	Metadata       map[string]any      `json:"metadata"        yaml:"metadata"`
 }
 
 // CircuitBreaker represents the gateway circuit breaker config.
 //
 // The circuit breaker configuration fields are required if the circuit breaker is enabled.
+//
+// The sliding window type is fixed (default), count-based or time-based. A fixed window clears the
+// counts at every interval. A count-based window keeps the last sliding-window-size calls. A time-based
+// window keeps the calls of the last interval, split into sliding-window-size buckets.
+//
+// Calls slower than the slow call duration threshold are slow, and the circuit breaker trips when their
+// rate reaches the slow call rate threshold, even if they succeed. Both are optional.
+//
+// The failure statuses are status codes (503), ranges (500-504) or classes (5xx), 5xx by default.
+// The record errors, when present, restrict the errors counted as failures to the given kinds, and
+// the ignore errors are never counted. The kinds are timeout, connection-refused and tls.
+// The failure header counts as failures the responses with a matching header, whatever their status.
+//
+// The wait duration multiplier, when set, makes the wait duration in open state grow exponentially at every
+// consecutive trip, up to the max wait duration in open state, which is then required. It resets once the
+// circuit breaker closes.
+//
+// The fallback answers the requests the circuit breaker rejects or the backend fails.
 type CircuitBreaker struct {
-	Enabled                 bool     `json:"enabled"                     yaml:"enabled"`
-	Interval                Duration `json:"interval"                    yaml:"interval"                    validate:"required_if=Enabled true"` //nolint:lll
-	FailureRateThreshold    int      `json:"failure-rate-threshold"      yaml:"failure-rate-threshold"      validate:"required_if=Enabled true"` //nolint:lll
-	NumAllowedHalfOpenCalls int      `json:"num-allowed-half-open-calls" yaml:"num-allowed-half-open-calls" validate:"required_if=Enabled true"` //nolint:lll
-	WaitDurationInOpenState Duration `json:"wait-duration-in-open-state" yaml:"wait-duration-in-open-state" validate:"required_if=Enabled true"` //nolint:lll
-	MinRequestsThreshold    int      `json:"min-requests-threshold"      yaml:"min-requests-threshold"      validate:"required_if=Enabled true"` //nolint:lll
+	Enabled                    bool           `json:"enabled"                         yaml:"enabled"`
+	Interval                   Duration       `json:"interval"                        yaml:"interval"                        validate:"required_if=Enabled true"`                     //nolint:lll
+	FailureRateThreshold       int            `json:"failure-rate-threshold"          yaml:"failure-rate-threshold"          validate:"required_if=Enabled true"`                     //nolint:lll
+	NumAllowedHalfOpenCalls    int            `json:"num-allowed-half-open-calls"     yaml:"num-allowed-half-open-calls"     validate:"required_if=Enabled true"`                     //nolint:lll
+	WaitDurationInOpenState    Duration       `json:"wait-duration-in-open-state"     yaml:"wait-duration-in-open-state"     validate:"required_if=Enabled true"`                     //nolint:lll
+	MinRequestsThreshold       int            `json:"min-requests-threshold"          yaml:"min-requests-threshold"          validate:"required_if=Enabled true"`                     //nolint:lll
+	SlidingWindowType          string         `json:"sliding-window-type"             yaml:"sliding-window-type"             validate:"omitempty,oneof=fixed count-based time-based"` //nolint:lll
+	SlidingWindowSize          int            `json:"sliding-window-size"             yaml:"sliding-window-size"             validate:"gte=0"`                                        //nolint:lll
+	SlowCallRateThreshold      int            `json:"slow-call-rate-threshold"        yaml:"slow-call-rate-threshold"        validate:"gte=0,lte=100"`                                //nolint:lll
+	WaitDurationMultiplier     float64        `json:"wait-duration-multiplier"        yaml:"wait-duration-multiplier"        validate:"omitempty,gte=1"`                              //nolint:lll
+	RecordErrors               []string       `json:"record-errors"                   yaml:"record-errors"                   validate:"dive,oneof=timeout connection-refused tls"`    //nolint:lll
+	IgnoreErrors               []string       `json:"ignore-errors"                   yaml:"ignore-errors"                   validate:"dive,oneof=timeout connection-refused tls"`    //nolint:lll
+	SlowCallDurationThreshold  Duration       `json:"slow-call-duration-threshold"    yaml:"slow-call-duration-threshold"`
+	MaxWaitDurationInOpenState Duration       `json:"max-wait-duration-in-open-state" yaml:"max-wait-duration-in-open-state"`
+	FailureHeader              *FailureHeader `json:"failure-header"                  yaml:"failure-header"`
+	FailureStatuses            []string       `json:"failure-statuses"                yaml:"failure-statuses"`
+	Fallback                   *Fallback      `json:"fallback"                        yaml:"fallback"`
+}
+
+// Fallback represents the circuit breaker fallback config.
+//
+// The uri is either another backend (http://fallback:8080/degraded) or another route (forward:route-id).
+// Without uri, the fallback is a static response with the given status, headers and body. The body may
+// reference ${route}, ${reason} and ${error}.
+//
+// The original error message is only sent to the fallback, as ${error} and in the X-Gateway-Fallback-Error
+// header, when expose-error is set. Otherwise ${error} is the fallback reason.
+type Fallback struct {
+	Headers     map[string]string `json:"headers"      yaml:"headers"`
+	URI         string            `json:"uri"          yaml:"uri"          validate:"required_without=Status,excluded_with=Status"`
+	Body        string            `json:"body"         yaml:"body"         validate:"excluded_with=URI"`
+	Status      int               `json:"status"       yaml:"status"       validate:"required_without=URI"`
+	ExposeError bool              `json:"expose-error" yaml:"expose-error"`
+}
+
+// FailureHeader represents the circuit breaker response header predicate config.
+//
+// A response is a failure when the named header is present and, if a pattern is set, any of its
+// values matches the pattern.
+type FailureHeader struct {
+	Name    string `json:"name"    yaml:"name"    validate:"required"`
+	Pattern string `json:"pattern" yaml:"pattern"`
+}
+
+// LoadShedding represents the gateway-wide admission controller config used by the Priority filter.
+//
+// Every request the gateway handles counts as in-flight, whether its route has a Priority filter or not.
+// A zero threshold disables it. The class headroom is a percentage of the thresholds.
+type LoadShedding struct {
+	MaxInFlight   int      `json:"max-in-flight"   yaml:"max-in-flight"   validate:"gte=0"`
+	MaxQueueDelay Duration `json:"max-queue-delay" yaml:"max-queue-delay"`
+	ClassHeadroom int      `json:"class-headroom"  yaml:"class-headroom"  validate:"gte=0"`
+	RetryAfter    Duration `json:"retry-after"     yaml:"retry-after"`
 }
 
 // ParameterizedItem represents the gateway predicate or filter config.
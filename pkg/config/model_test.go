@@ -696,6 +696,45 @@ func TestRoute_ValidateJSON(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name:  "unmarshal and validate should return error when sliding window type is not valid",
+			input: "{\"id\":\"someID\",\"uri\":\"someUri\",\"circuit-breaker\":{\"enabled\":true,\"interval\":\"30s\",\"failure-rate-threshold\":10,\"num-allowed-half-open-calls\":10,\"wait-duration-in-open-state\":\"10s\",\"min-requests-threshold\":10,\"sliding-window-type\":\"rolling\",\"sliding-window-size\":20,\"slow-call-duration-threshold\":\"2s\",\"slow-call-rate-threshold\":101}}",
+			expected: config.Route{
+				ID:  "someID",
+				URI: "someUri",
+				CircuitBreaker: config.CircuitBreaker{
+					Enabled:                   true,
+					Interval:                  config.Duration{Duration: 30 * time.Second},
+					FailureRateThreshold:      10,
+					NumAllowedHalfOpenCalls:   10,
+					WaitDurationInOpenState:   config.Duration{Duration: 10 * time.Second},
+					MinRequestsThreshold:      10,
+					SlidingWindowType:         "rolling",
+					SlidingWindowSize:         20,
+					SlowCallRateThreshold:     101,
+					SlowCallDurationThreshold: config.Duration{Duration: 2 * time.Second},
+				},
+			},
+			expectedErr: errors.New("Key: 'Route.CircuitBreaker.SlidingWindowType' Error:Field validation for 'SlidingWindowType' failed on the 'oneof' tag\nKey: 'Route.CircuitBreaker.SlowCallRateThreshold' Error:Field validation for 'SlowCallRateThreshold' failed on the 'lte' tag"),
+		},
+		{
+			name:  "unmarshal and validate should return error when fallback has both uri and status",
+			input: "{\"id\":\"someID\",\"uri\":\"someUri\",\"circuit-breaker\":{\"enabled\":true,\"interval\":\"30s\",\"failure-rate-threshold\":10,\"num-allowed-half-open-calls\":10,\"wait-duration-in-open-state\":\"10s\",\"min-requests-threshold\":10,\"fallback\":{\"uri\":\"forward:other\",\"status\":503}}}",
+			expected: config.Route{
+				ID:  "someID",
+				URI: "someUri",
+				CircuitBreaker: config.CircuitBreaker{
+					Enabled:                 true,
+					Interval:                config.Duration{Duration: 30 * time.Second},
+					FailureRateThreshold:    10,
+					NumAllowedHalfOpenCalls: 10,
+					WaitDurationInOpenState: config.Duration{Duration: 10 * time.Second},
+					MinRequestsThreshold:    10,
+					Fallback:                &config.Fallback{URI: "forward:other", Status: 503},
+				},
+			},
+			expectedErr: errors.New("Key: 'Route.CircuitBreaker.Fallback.URI' Error:Field validation for 'URI' failed on the 'excluded_with' tag"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -1,7 +1,6 @@
 package config
 
 import (
-	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
@@ -9,27 +8,59 @@ import (
 	"log/slog"
 	"net"
 	"net/http"
+	"net/url"
+	"regexp"
 	"time"
 
 	"golang.org/x/net/http2"
 
 	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
 	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/httpclient"
 	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
 )
 
+// ErrInvalidFallback is the error returned when a circuit breaker fallback is not valid.
+var ErrInvalidFallback = errors.New("invalid circuit breaker fallback")
+
+const (
+	forwardScheme = "forward"
+	minStatusCode = 100
+	maxStatusCode = 599
+)
+
 // ErrInitializeMTLS is the error returned when the mTLS initialization failed.
 var ErrInitializeMTLS = errors.New("failed to initialize mTLS")
 
 // NewRoutes creates a new gateway route from the given config.
+//
+// The routes are sorted by order, keeping the config order of the routes with the same order. A warning is
+// logged for each route shadowed by an earlier catch-all route. The circuit breakers of the routes publish
+// their events to the given event bus.
 func NewRoutes(
 	cfg *Config,
 	predicateFactory *predicate.Factory,
 	filterFactory *filter.Factory,
+	eventBus *circuitbreaker.EventBus,
 	logger *slog.Logger) (gateway.Routes, error) {
-	return mapRoutesFromConfigToGateway(cfg.Gateway, predicateFactory, filterFactory, logger)
+	return mapRoutesFromConfigToGateway(cfg.Gateway, predicateFactory, filterFactory, eventBus, logger)
+}
+
+// ConfigureLoadShedding applies the load shedding config to the given admission controller.
+// Without load shedding config, the controller is left as is.
+func ConfigureLoadShedding(cfg *Config, controller *concurrency.AdmissionController) {
+	if cfg == nil || cfg.Gateway.LoadShedding == nil {
+		return
+	}
+	loadShedding := cfg.Gateway.LoadShedding
+	controller.Configure(concurrency.AdmissionSettings{
+		MaxInFlight:   loadShedding.MaxInFlight,
+		MaxQueueDelay: loadShedding.MaxQueueDelay.Duration,
+		ClassHeadroom: loadShedding.ClassHeadroom,
+		RetryAfter:    loadShedding.RetryAfter.Duration,
+	})
 }
 
 // NewHTTPClient creates a new http client from the given config.
@@ -42,11 +73,13 @@ func NewHTTPClient(cfg *Config) (gateway.HTTPClient, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to build http client: %w", err)
 	}
-	if cfg != nil {
-		for _, route := range cfg.Gateway.Routes {
-			if route.CircuitBreaker.Enabled {
-				return httpclient.NewCircuitBreakerHTTPClient(httpClient), nil
-			}
+	if cfg == nil {
+		return httpClient, nil
+	}
+	// The failure policies are the ones of the route circuit breakers, built with the routes.
+	for _, route := range cfg.Gateway.Routes {
+		if route.CircuitBreaker.Enabled {
+			return httpclient.NewCircuitBreakerHTTPClient(httpClient), nil
 		}
 	}
 	return httpClient, nil
@@ -162,33 +195,152 @@ func mapRoutesFromConfigToGateway(
 	gwConfig Gateway,
 	predicateFactory *predicate.Factory,
 	filterFactory *filter.Factory,
+	eventBus *circuitbreaker.EventBus,
 	logger *slog.Logger) (gateway.Routes, error) {
-	out := make(gateway.Routes, 0)
-	for _, route := range gwConfig.Routes {
+	routes := sortRoutes(gwConfig.Routes)
+	out := make(gateway.Routes, 0, len(routes))
+	for _, route := range routes {
 		predicates, err := mapPredicatesFromConfigToGateway(predicateFactory, route.Predicates...)
 		if err != nil {
 			return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
 		}
-		globalFilters, err := mapFiltersFromConfigToGateway(filterFactory, gwConfig.GlobalFilters...)
+		globalFilters, filters, err := mapRouteFiltersFromConfigToGateway(filterFactory, route, gwConfig.GlobalFilters)
 		if err != nil {
 			return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
 		}
-		filters, err := mapFiltersFromConfigToGateway(filterFactory, route.Filters...)
+		requestSize, responseSize, err := mapSizeFiltersFromConfigToGateway(route, gwConfig)
 		if err != nil {
 			return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
 		}
+		// The request size limit goes first, so that no other filter reads a request body over the limit. The
+		// response size limit goes last: the filters post-process in reverse order, so it limits the body sent
+		// by the backend before any filter compresses or transforms it.
+		globalFilters = append(requestSize, globalFilters...)
+		filters = append(filters, responseSize...)
 		timeout := calculateTimeout(route.Timeout, gwConfig.GlobalTimeout)
-		circuitBreaker := mapCircuitBreakerFromConfigToGateway(route.ID, route.CircuitBreaker)
+		circuitBreaker, err := mapCircuitBreakerFromConfigToGateway(route.ID, route.CircuitBreaker, eventBus)
+		if err != nil {
+			return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
+		}
 		buildRoute, err := gateway.NewRoute(
 			route.ID, route.URI, predicates, globalFilters, filters, timeout, circuitBreaker, logger)
 		if err != nil {
 			return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
 		}
+		buildRoute.Order = route.Order
+		buildRoute.Metadata = route.Metadata
 		out = append(out, *buildRoute)
 	}
+	if err := predicate.LinkWeightGroups(out); err != nil {
+		return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
+	}
+	// Fallbacks are mapped once every route exists, since they may forward to any of them.
+	for idx, route := range routes {
+		if !route.CircuitBreaker.Enabled || route.CircuitBreaker.Fallback == nil {
+			continue
+		}
+		fallback, err := mapFallbackFromConfigToGateway(route.ID, *route.CircuitBreaker.Fallback, out)
+		if err != nil {
+			return nil, fmt.Errorf("map routes from config to gateway failed: %w", err)
+		}
+		out[idx].Fallback = fallback
+	}
+	warnShadowedRoutes(routes, logger)
 	return out, nil
 }
 
+// mapRouteFiltersFromConfigToGateway maps the global filters and the filters of the route, with their references
+// to the route metadata replaced.
+func mapRouteFiltersFromConfigToGateway(
+	filterFactory *filter.Factory,
+	route Route,
+	globalFilterItems []ParameterizedItem) (gateway.Filters, gateway.Filters, error) {
+	globalFilterItems, err := expandMetadata(route, globalFilterItems)
+	if err != nil {
+		return nil, nil, err
+	}
+	filterItems, err := expandMetadata(route, route.Filters)
+	if err != nil {
+		return nil, nil, err
+	}
+	globalFilters, err := mapFiltersFromConfigToGateway(filterFactory, globalFilterItems...)
+	if err != nil {
+		return nil, nil, err
+	}
+	filters, err := mapFiltersFromConfigToGateway(filterFactory, filterItems...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return globalFilters, filters, nil
+}
+
+func mapFallbackFromConfigToGateway(
+	routeID string, fallback Fallback, routes gateway.Routes) (*gateway.Fallback, error) {
+	if fallback.URI == "" {
+		if fallback.Status < minStatusCode || fallback.Status > maxStatusCode {
+			return nil, fmt.Errorf("%w: route %s: status %d", ErrInvalidFallback, routeID, fallback.Status)
+		}
+		headers := make(http.Header, len(fallback.Headers))
+		for key, value := range fallback.Headers {
+			headers.Set(key, value)
+		}
+		return &gateway.Fallback{
+			Static:      &gateway.StaticResponse{Status: fallback.Status, Headers: headers, Body: fallback.Body},
+			ExposeError: fallback.ExposeError,
+		}, nil
+	}
+	fallbackURI, err := url.Parse(fallback.URI)
+	if err != nil {
+		return nil, fmt.Errorf("%w: route %s: %w", ErrInvalidFallback, routeID, err)
+	}
+	if fallbackURI.Scheme != forwardScheme {
+		if fallbackURI.Host == "" {
+			return nil, fmt.Errorf("%w: route %s: uri %s has no host", ErrInvalidFallback, routeID, fallback.URI)
+		}
+		return &gateway.Fallback{URI: fallbackURI, ExposeError: fallback.ExposeError}, nil
+	}
+	targetID := fallbackURI.Opaque
+	if targetID == routeID {
+		return nil, fmt.Errorf("%w: route %s forwards to itself", ErrInvalidFallback, routeID)
+	}
+	for idx := range routes {
+		if routes[idx].ID == targetID {
+			return &gateway.Fallback{Route: &routes[idx], ExposeError: fallback.ExposeError}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: route %s forwards to unknown route %s", ErrInvalidFallback, routeID, targetID)
+}
+
+// mapSizeFiltersFromConfigToGateway maps the request and response size limits of the route to RequestSize and
+// ResponseSize filters.
+func mapSizeFiltersFromConfigToGateway(route Route, gwConfig Gateway) (gateway.Filters, gateway.Filters, error) {
+	var requestFilters, responseFilters gateway.Filters
+	if maxSize := calculateSize(route.RequestSize, gwConfig.GlobalRequestSize); maxSize > 0 {
+		requestSize, err := filter.NewRequestSizeFilter(maxSize)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+		requestFilters = append(requestFilters, requestSize)
+	}
+	if maxSize := calculateSize(route.ResponseSize, gwConfig.GlobalResponseSize); maxSize > 0 {
+		responseSize, err := filter.NewResponseSizeFilter(maxSize)
+		if err != nil {
+			return nil, nil, err //nolint:wrapcheck
+		}
+		responseFilters = append(responseFilters, responseSize)
+	}
+	return requestFilters, responseFilters, nil
+}
+
+// calculateSize returns the size limit of the route, or the global one when the route does not set it. A
+// negative route size disables the limit.
+func calculateSize(routeSize, globalSize int64) int64 {
+	if routeSize != 0 {
+		return routeSize
+	}
+	return globalSize
+}
+
 func calculateTimeout(routeTimeout, globalTimeout Duration) time.Duration {
 	if routeTimeout.Duration > 0 {
 		return routeTimeout.Duration
@@ -227,30 +379,96 @@ func mapFiltersFromConfigToGateway(
 	return out, nil
 }
 
-// isRequestSuccessful is the circuit breaker success policy for gateway routes: any
-// error counts as a failure, since backend 5xx responses (ErrInternalServer), network
-// errors and timeouts all signal an unhealthy backend. The exception is the client
-// cancelling its own request, which says nothing about backend health and would
-// otherwise let a burst of client disconnections trip the breaker.
-func isRequestSuccessful(err error) bool {
-	return err == nil || errors.Is(err, context.Canceled)
-}
-
-//nolint:bodyclose
+//nolint:bodyclose,ireturn
 func mapCircuitBreakerFromConfigToGateway(
-	name string, circuitBreaker CircuitBreaker) gateway.CircuitBreaker[*http.Response] {
+	name string, circuitBreaker CircuitBreaker, eventBus *circuitbreaker.EventBus) (gateway.CircuitBreaker[*http.Response], error) {
 	if !circuitBreaker.Enabled {
-		return nil
+		return nil, nil //nolint:nilnil // a disabled breaker is no breaker
+	}
+	windowType, err := circuitbreaker.ParseWindowType(circuitBreaker.SlidingWindowType)
+	if err != nil {
+		return nil, fmt.Errorf("parse circuit breaker failed: %w", err)
+	}
+	policy, err := mapFailurePolicyFromConfigToGateway(circuitBreaker)
+	if err != nil {
+		return nil, fmt.Errorf("parse circuit breaker failed: %w", err)
+	}
+	backoff, err := mapOpenBackoffFromConfigToGateway(circuitBreaker)
+	if err != nil {
+		return nil, fmt.Errorf("parse circuit breaker failed: %w", err)
 	}
 	settings := circuitbreaker.Settings{
-		Name:        name,
-		MaxRequests: uint32(circuitBreaker.NumAllowedHalfOpenCalls), //nolint:gosec
-		Interval:    circuitBreaker.Interval.Duration,
-		Timeout:     circuitBreaker.WaitDurationInOpenState.Duration,
-		ReadyToTrip: circuitbreaker.DefaultReadyToTrip(
-			circuitBreaker.MinRequestsThreshold, circuitBreaker.FailureRateThreshold),
-		OnStateChange: func(_ string, _, _ circuitbreaker.State) {},
-		IsSuccessful:  isRequestSuccessful,
-	}
-	return circuitbreaker.NewCircuitBreaker[*http.Response](settings)
+		Name:             name,
+		MaxRequests:      uint32(circuitBreaker.NumAllowedHalfOpenCalls), //nolint:gosec
+		Interval:         circuitBreaker.Interval.Duration,
+		Timeout:          circuitBreaker.WaitDurationInOpenState.Duration,
+		WindowType:       windowType,
+		WindowSize:       circuitBreaker.SlidingWindowSize,
+		SlowCallDuration: circuitBreaker.SlowCallDurationThreshold.Duration,
+		ReadyToTrip: circuitbreaker.SlowCallReadyToTrip(
+			circuitBreaker.MinRequestsThreshold,
+			circuitBreaker.FailureRateThreshold,
+			circuitBreaker.SlowCallRateThreshold),
+		OnEvent:     eventBus.Publish,
+		OpenBackoff: backoff,
+	}
+	return httpclient.NewPolicyCircuitBreaker(settings, policy), nil
+}
+
+// mapOpenBackoffFromConfigToGateway maps the exponential wait duration in open state. Without a
+// multiplier, the circuit breaker waits the fixed wait duration in open state.
+func mapOpenBackoffFromConfigToGateway(circuitBreaker CircuitBreaker) (circuitbreaker.Backoff, error) {
+	if circuitBreaker.WaitDurationMultiplier == 0 {
+		return nil, nil
+	}
+	backoff, err := circuitbreaker.ExponentialBackoff(
+		circuitBreaker.WaitDurationInOpenState.Duration,
+		circuitBreaker.WaitDurationMultiplier,
+		circuitBreaker.MaxWaitDurationInOpenState.Duration)
+	if err != nil {
+		return nil, fmt.Errorf("parse open state backoff failed: %w", err)
+	}
+	return backoff, nil
+}
+
+// mapFailurePolicyFromConfigToGateway maps the circuit breaker failure classification. Without
+// it, the policy counts backend 5xx responses, network errors and timeouts as failures, since
+// they all signal an unhealthy backend, but not the client cancelling its own request.
+func mapFailurePolicyFromConfigToGateway(circuitBreaker CircuitBreaker) (*httpclient.FailurePolicy, error) {
+	policy := &httpclient.FailurePolicy{}
+	for _, status := range circuitBreaker.FailureStatuses {
+		statusRange, err := httpclient.ParseStatusRange(status)
+		if err != nil {
+			return nil, fmt.Errorf("parse failure statuses failed: %w", err)
+		}
+		policy.FailureStatuses = append(policy.FailureStatuses, statusRange)
+	}
+	var err error
+	if policy.RecordErrors, err = mapErrorKindsFromConfigToGateway(circuitBreaker.RecordErrors); err != nil {
+		return nil, fmt.Errorf("parse record errors failed: %w", err)
+	}
+	if policy.IgnoreErrors, err = mapErrorKindsFromConfigToGateway(circuitBreaker.IgnoreErrors); err != nil {
+		return nil, fmt.Errorf("parse ignore errors failed: %w", err)
+	}
+	if header := circuitBreaker.FailureHeader; header != nil {
+		policy.FailureHeader = header.Name
+		if header.Pattern != "" {
+			if policy.FailureHeaderPattern, err = regexp.Compile(header.Pattern); err != nil {
+				return nil, fmt.Errorf("parse failure header pattern failed: %w", err)
+			}
+		}
+	}
+	return policy, nil
+}
+
+func mapErrorKindsFromConfigToGateway(names []string) ([]httpclient.ErrorKind, error) {
+	kinds := make([]httpclient.ErrorKind, 0, len(names))
+	for _, name := range names {
+		kind, err := httpclient.ParseErrorKind(name)
+		if err != nil {
+			return nil, err //nolint:wrapcheck
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
 }
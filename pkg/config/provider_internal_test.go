@@ -9,11 +9,12 @@ import (
 	"github.com/drathveloper/go-cloud-gateway/pkg/httpclient"
 )
 
-func TestIsRequestSuccessful(t *testing.T) {
+func TestMapFailurePolicyFromConfigToGateway_DefaultIsSuccessful(t *testing.T) {
 	tests := []struct {
-		err      error
-		name     string
-		expected bool
+		err             error
+		name            string
+		expected        bool
+		expectedIgnored bool
 	}{
 		{
 			name:     "nil error is a success",
@@ -21,14 +22,16 @@ func TestIsRequestSuccessful(t *testing.T) {
 			expected: true,
 		},
 		{
-			name:     "client cancellation is not a backend failure",
-			err:      context.Canceled,
-			expected: true,
+			name:            "client cancellation is not a backend failure",
+			err:             context.Canceled,
+			expected:        false,
+			expectedIgnored: true,
 		},
 		{
-			name:     "wrapped client cancellation is not a backend failure",
-			err:      fmt.Errorf("request failed: %w", context.Canceled),
-			expected: true,
+			name:            "wrapped client cancellation is not a backend failure",
+			err:             fmt.Errorf("request failed: %w", context.Canceled),
+			expected:        false,
+			expectedIgnored: true,
 		},
 		{
 			name:     "timeout is a failure",
@@ -46,10 +49,17 @@ func TestIsRequestSuccessful(t *testing.T) {
 			expected: false,
 		},
 	}
+	policy, err := mapFailurePolicyFromConfigToGateway(CircuitBreaker{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := isRequestSuccessful(tt.err); got != tt.expected {
-				t.Errorf("isRequestSuccessful(%v) = %v, expected %v", tt.err, got, tt.expected)
+			if got := policy.IsSuccessful(tt.err); got != tt.expected {
+				t.Errorf("IsSuccessful(%v) = %v, expected %v", tt.err, got, tt.expected)
+			}
+			if got := policy.IsIgnored(tt.err); got != tt.expectedIgnored {
+				t.Errorf("IsIgnored(%v) = %v, expected %v", tt.err, got, tt.expectedIgnored)
 			}
 		})
 	}
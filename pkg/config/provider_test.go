@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
@@ -10,11 +11,14 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 	"unsafe"
 
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
 	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
 	"github.com/drathveloper/go-cloud-gateway/pkg/config"
 	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -367,6 +371,7 @@ func TestNewRoutes(t *testing.T) {
 				tt.config,
 				predicate.NewFactory(predicate.BuilderRegistry),
 				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
 				logger)
 			if len(routes) != len(tt.expected) {
 				t.Errorf("expected len %v actual len %v", tt.expected, routes)
@@ -405,6 +410,12 @@ func TestNewRoutes(t *testing.T) {
 // This is a nasty approach that should be addressed in the future.
 // We need to find a way to compare the circuit breaker without using reflection.
 func isEqualsCircuitBreakers(a, b gateway.CircuitBreaker[*http.Response]) bool {
+	if policyBreaker, ok := a.(*httpclient.PolicyCircuitBreaker); ok {
+		a = policyBreaker.CircuitBreaker
+	}
+	if policyBreaker, ok := b.(*httpclient.PolicyCircuitBreaker); ok {
+		b = policyBreaker.CircuitBreaker
+	}
 	va := reflect.ValueOf(a).Elem()
 	vb := reflect.ValueOf(b).Elem()
 	typ := va.Type()
@@ -413,7 +424,9 @@ func isEqualsCircuitBreakers(a, b gateway.CircuitBreaker[*http.Response]) bool {
 		if field.Name == "mutex" ||
 			field.Name == "readyToTrip" ||
 			field.Name == "isSuccessful" ||
+			field.Name == "isIgnored" ||
 			field.Name == "onStateChange" ||
+			field.Name == "onEvent" ||
 			field.Name == "expiry" {
 			continue
 		}
@@ -815,6 +828,7 @@ func newCircuitBreakerRoute(t *testing.T) gateway.Routes {
 		cfg,
 		predicate.NewFactory(predicate.BuilderRegistry),
 		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
 		slog.Default())
 	if err != nil {
 		t.Fatalf("NewRoutes() error = %v", err)
@@ -852,6 +866,52 @@ func TestNewRoutes_CircuitBreakerIgnoresClientCancellations(t *testing.T) {
 	}
 }
 
+func TestNewRoutes_CircuitBreakerOpensOnSlowCalls(t *testing.T) {
+	cfg := &config.Config{
+		Gateway: config.Gateway{
+			Routes: []config.Route{
+				{
+					ID:  "r1",
+					URI: "https://example.com",
+					CircuitBreaker: config.CircuitBreaker{
+						Enabled:                   true,
+						Interval:                  config.Duration{Duration: time.Minute},
+						FailureRateThreshold:      50,
+						NumAllowedHalfOpenCalls:   1,
+						WaitDurationInOpenState:   config.Duration{Duration: time.Minute},
+						MinRequestsThreshold:      3,
+						SlidingWindowType:         "count-based",
+						SlidingWindowSize:         10,
+						SlowCallDurationThreshold: config.Duration{Duration: time.Millisecond},
+						SlowCallRateThreshold:     100,
+					},
+				},
+			},
+		},
+	}
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
+		slog.Default())
+	if err != nil {
+		t.Fatalf("NewRoutes() error = %v", err)
+	}
+	breaker := routes[0].CircuitBreaker
+
+	for range 3 {
+		_, _ = breaker.Execute(func() (*http.Response, error) { //nolint:bodyclose // the callback returns no response
+			time.Sleep(2 * time.Millisecond)
+			return nil, nil
+		})
+	}
+
+	if breaker.State() != circuitbreaker.StateOpen {
+		t.Errorf("expected the breaker to open on slow successful calls, actual state %s", breaker.State())
+	}
+}
+
 func TestNewHTTPClient_PoolTimeoutDoesNotCutSlowBodies(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		controller := http.NewResponseController(w)
@@ -970,3 +1030,711 @@ func TestNewHTTPClient_DoesNotFollowRedirects(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureLoadShedding(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{})
+	cfg := &config.Config{
+		Gateway: config.Gateway{
+			LoadShedding: &config.LoadShedding{
+				MaxInFlight: 1,
+				RetryAfter:  config.Duration{Duration: 3 * time.Second},
+			},
+		},
+	}
+
+	config.ConfigureLoadShedding(cfg, controller)
+
+	controller.Enter()
+	controller.Enter()
+	if controller.Admit(concurrency.PriorityLow) {
+		t.Errorf("expected low requests shed over the configured max in flight")
+	}
+	if controller.RetryAfter() != 3*time.Second {
+		t.Errorf("expected retry after 3s actual %s", controller.RetryAfter())
+	}
+}
+
+func TestNewRoutes_CircuitBreakerFailurePolicy(t *testing.T) {
+	tests := []struct {
+		expectedErr    error
+		name           string
+		circuitBreaker config.CircuitBreaker
+	}{
+		{
+			name: "new routes should succeed when failure classification is valid",
+			circuitBreaker: config.CircuitBreaker{
+				Enabled:         true,
+				FailureStatuses: []string{"429", "500-502", "504"},
+				RecordErrors:    []string{"timeout", "connection-refused"},
+				IgnoreErrors:    []string{"tls"},
+				FailureHeader:   &config.FailureHeader{Name: "X-Backend-Health", Pattern: "^degraded$"},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "new routes should return error when failure status is not valid",
+			circuitBreaker: config.CircuitBreaker{
+				Enabled:         true,
+				FailureStatuses: []string{"5xx", "server-error"},
+			},
+			expectedErr: errors.New("map routes from config to gateway failed: parse circuit breaker failed: parse failure statuses failed: invalid status range: server-error"),
+		},
+		{
+			name: "new routes should return error when failure header pattern is not valid",
+			circuitBreaker: config.CircuitBreaker{
+				Enabled:       true,
+				FailureHeader: &config.FailureHeader{Name: "X-Backend-Health", Pattern: "("},
+			},
+			expectedErr: errors.New("map routes from config to gateway failed: parse circuit breaker failed: parse failure header pattern failed: error parsing regexp: missing closing ): `(`"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Gateway: config.Gateway{
+					Routes: []config.Route{
+						{ID: "r1", URI: "https://example.com", CircuitBreaker: tt.circuitBreaker},
+					},
+				},
+			}
+
+			_, err := config.NewRoutes(
+				cfg,
+				predicate.NewFactory(predicate.BuilderRegistry),
+				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
+				slog.Default())
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNewRoutes_CircuitBreakerCarriesFailurePolicy(t *testing.T) {
+	cfg := &config.Config{
+		Gateway: config.Gateway{
+			Routes: []config.Route{
+				{
+					ID:  "r1",
+					URI: "https://example.com",
+					CircuitBreaker: config.CircuitBreaker{
+						Enabled:         true,
+						FailureStatuses: []string{"429"},
+					},
+				},
+			},
+		},
+	}
+
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
+		slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	breaker, ok := routes[0].CircuitBreaker.(*httpclient.PolicyCircuitBreaker)
+	if !ok {
+		t.Fatalf("expected policy circuit breaker actual %T", routes[0].CircuitBreaker)
+	}
+	if !breaker.FailurePolicy().IsFailureResponse(&http.Response{StatusCode: http.StatusTooManyRequests}) {
+		t.Errorf("expected 429 to be a failure status")
+	}
+}
+
+func TestNewRoutes_CircuitBreakerPublishesToEventBus(t *testing.T) {
+	cfg := &config.Config{
+		Gateway: config.Gateway{
+			Routes: []config.Route{
+				{
+					ID:             "r1",
+					URI:            "https://example.com",
+					CircuitBreaker: config.CircuitBreaker{Enabled: true},
+				},
+			},
+		},
+	}
+	var received []string
+	eventBus := circuitbreaker.NewEventBus(circuitbreaker.ListenerFunc(func(event circuitbreaker.Event) {
+		received = append(received, event.Name)
+	}))
+
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		eventBus,
+		slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	_, _ = routes[0].CircuitBreaker.Execute(func() (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+
+	if !reflect.DeepEqual([]string{"r1"}, received) {
+		t.Errorf("expected events of r1 actual %v", received)
+	}
+}
+
+func TestNewRoutes_CircuitBreakerFallback(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		fallback    *config.Fallback
+		assert      func(t *testing.T, routes gateway.Routes)
+		name        string
+	}{
+		{
+			name: "new routes should map static fallback",
+			fallback: &config.Fallback{
+				Status:  http.StatusServiceUnavailable,
+				Headers: map[string]string{"content-type": "application/json"},
+				Body:    `{"error":"${error}"}`,
+			},
+			assert: func(t *testing.T, routes gateway.Routes) {
+				t.Helper()
+				static := routes[0].Fallback.Static
+				if static == nil || static.Status != http.StatusServiceUnavailable ||
+					static.Headers.Get("Content-Type") != "application/json" || static.Body != `{"error":"${error}"}` {
+					t.Errorf("unexpected static fallback %+v", static)
+				}
+			},
+		},
+		{
+			name:     "new routes should map backend fallback",
+			fallback: &config.Fallback{URI: "http://fallback:8080/degraded"},
+			assert: func(t *testing.T, routes gateway.Routes) {
+				t.Helper()
+				if got := routes[0].Fallback.URI; got == nil || got.String() != "http://fallback:8080/degraded" {
+					t.Errorf("unexpected backend fallback %v", got)
+				}
+			},
+		},
+		{
+			name:     "new routes should map forward fallback to the target route",
+			fallback: &config.Fallback{URI: "forward:r2"},
+			assert: func(t *testing.T, routes gateway.Routes) {
+				t.Helper()
+				if routes[0].Fallback.Route != &routes[1] {
+					t.Errorf("expected forward fallback to route r2, actual %v", routes[0].Fallback.Route)
+				}
+			},
+		},
+		{
+			name:     "new routes should map fallback error exposure",
+			fallback: &config.Fallback{URI: "forward:r2", ExposeError: true},
+			assert: func(t *testing.T, routes gateway.Routes) {
+				t.Helper()
+				if !routes[0].Fallback.ExposeError {
+					t.Errorf("expected fallback to expose the error, actual %+v", routes[0].Fallback)
+				}
+			},
+		},
+		{
+			name:        "new routes should return error when forward fallback route does not exist",
+			fallback:    &config.Fallback{URI: "forward:r3"},
+			expectedErr: errors.New("map routes from config to gateway failed: invalid circuit breaker fallback: route r1 forwards to unknown route r3"),
+		},
+		{
+			name:        "new routes should return error when forward fallback route is the same route",
+			fallback:    &config.Fallback{URI: "forward:r1"},
+			expectedErr: errors.New("map routes from config to gateway failed: invalid circuit breaker fallback: route r1 forwards to itself"),
+		},
+		{
+			name:        "new routes should return error when backend fallback has no host",
+			fallback:    &config.Fallback{URI: "/degraded"},
+			expectedErr: errors.New("map routes from config to gateway failed: invalid circuit breaker fallback: route r1: uri /degraded has no host"),
+		},
+		{
+			name:        "new routes should return error when static fallback status is not valid",
+			fallback:    &config.Fallback{Status: 999},
+			expectedErr: errors.New("map routes from config to gateway failed: invalid circuit breaker fallback: route r1: status 999"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Gateway: config.Gateway{
+					Routes: []config.Route{
+						{
+							ID:             "r1",
+							URI:            "https://example.com",
+							CircuitBreaker: config.CircuitBreaker{Enabled: true, Fallback: tt.fallback},
+						},
+						{ID: "r2", URI: "https://secondary.example.com"},
+					},
+				},
+			}
+
+			routes, err := config.NewRoutes(
+				cfg,
+				predicate.NewFactory(predicate.BuilderRegistry),
+				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
+				slog.Default())
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if tt.assert != nil && err == nil {
+				tt.assert(t, routes)
+			}
+		})
+	}
+}
+
+func TestNewRoutes_CircuitBreakerOpenBackoff(t *testing.T) {
+	tests := []struct {
+		expectedErr    error
+		name           string
+		circuitBreaker config.CircuitBreaker
+	}{
+		{
+			name: "new routes should succeed when open state backoff is valid",
+			circuitBreaker: config.CircuitBreaker{
+				Enabled:                    true,
+				WaitDurationInOpenState:    config.Duration{Duration: 10 * time.Second},
+				WaitDurationMultiplier:     2,
+				MaxWaitDurationInOpenState: config.Duration{Duration: 5 * time.Minute},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "new routes should return error when max wait duration in open state is missing",
+			circuitBreaker: config.CircuitBreaker{
+				Enabled:                 true,
+				WaitDurationInOpenState: config.Duration{Duration: 10 * time.Second},
+				WaitDurationMultiplier:  2,
+			},
+			expectedErr: errors.New("map routes from config to gateway failed: parse circuit breaker failed: parse open state backoff failed: invalid open state backoff: base 10s, multiplier 2, max 0s"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{
+				Gateway: config.Gateway{
+					Routes: []config.Route{
+						{ID: "r1", URI: "https://example.com", CircuitBreaker: tt.circuitBreaker},
+					},
+				},
+			}
+
+			_, err := config.NewRoutes(
+				cfg,
+				predicate.NewFactory(predicate.BuilderRegistry),
+				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
+				slog.Default())
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestNewRoutes_WeightGroups(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		weights     []int
+	}{
+		{
+			name:        "new routes should link weight groups",
+			weights:     []int{95, 5},
+			expectedErr: nil,
+		},
+		{
+			name:        "new routes should return error when weight group is not valid",
+			weights:     []int{0, 0},
+			expectedErr: errors.New("map routes from config to gateway failed: invalid weight group: group canary: total weight is 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Gateway: config.Gateway{}}
+			for i, weight := range tt.weights {
+				cfg.Gateway.Routes = append(cfg.Gateway.Routes, config.Route{
+					ID:  fmt.Sprintf("v%d", i+1),
+					URI: "https://example.com",
+					Predicates: []config.ParameterizedItem{
+						{Name: "Weight", Args: map[string]any{"group": "canary", "weight": weight}},
+					},
+				})
+			}
+
+			routes, err := config.NewRoutes(
+				cfg,
+				predicate.NewFactory(predicate.BuilderRegistry),
+				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
+				slog.Default())
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Fatalf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && routes.FindMatching(httptest.NewRequest(http.MethodGet, "/", nil)) == nil {
+				t.Error("expected a route of the weight group to match")
+			}
+		})
+	}
+}
+
+func TestNewRoutes_Order(t *testing.T) {
+	cfg := &config.Config{Gateway: config.Gateway{Routes: []config.Route{
+		{ID: "default-1", URI: "https://example.com"},
+		{ID: "last", URI: "https://example.com", Order: 10},
+		{ID: "first", URI: "https://example.com", Order: -1},
+		{ID: "default-2", URI: "https://example.com"},
+	}}}
+	expected := []string{"first", "default-1", "default-2", "last"}
+
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
+		slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	actual := make([]string, 0, len(routes))
+	for _, route := range routes {
+		actual = append(actual, route.ID)
+	}
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("expected %v actual %v", expected, actual)
+	}
+	if routes[3].Order != 10 || routes[0].Order != -1 {
+		t.Errorf("expected orders -1 and 10 actual %d and %d", routes[0].Order, routes[3].Order)
+	}
+}
+
+func TestNewRoutes_WarnsShadowedRoutes(t *testing.T) {
+	path := func(patterns ...any) []config.ParameterizedItem {
+		return []config.ParameterizedItem{{Name: "Path", Args: map[string]any{"patterns": patterns}}}
+	}
+	tests := []struct {
+		name     string
+		routes   []config.Route
+		expected []string
+	}{
+		{
+			name: "route after a route without predicates should be shadowed",
+			routes: []config.Route{
+				{ID: "all", URI: "https://example.com"},
+				{ID: "users", URI: "https://example.com", Predicates: path("/users")},
+			},
+			expected: []string{"route=users catch-all=all"},
+		},
+		{
+			name: "route under a prefix catch-all should be shadowed",
+			routes: []config.Route{
+				{ID: "api", URI: "https://example.com", Predicates: path("/api/**", "/v1/**")},
+				{ID: "users", URI: "https://example.com", Predicates: path("/api/users/{id}", "/v1")},
+				{ID: "other", URI: "https://example.com", Predicates: path("/api/users", "/other")},
+			},
+			expected: []string{"route=users catch-all=api"},
+		},
+		{
+			name: "route ordered before the catch-all should not be shadowed",
+			routes: []config.Route{
+				{ID: "all", URI: "https://example.com", Predicates: path("/**")},
+				{ID: "users", URI: "https://example.com", Predicates: path("/users/**"), Order: -1},
+			},
+			expected: nil,
+		},
+		{
+			name: "route after a restricted prefix route should not be shadowed",
+			routes: []config.Route{
+				{ID: "api", URI: "https://example.com", Predicates: append(path("/api/**"),
+					config.ParameterizedItem{Name: "Method", Args: map[string]any{"methods": []any{"GET"}}})},
+				{ID: "glob", URI: "https://example.com", Predicates: path("/api/*/users/**")},
+				{ID: "users", URI: "https://example.com", Predicates: path("/api/users")},
+			},
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buffer bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&buffer, &slog.HandlerOptions{
+				ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+					if attr.Key == slog.TimeKey || attr.Key == slog.LevelKey || attr.Key == slog.MessageKey {
+						return slog.Attr{}
+					}
+					return attr
+				},
+			}))
+
+			_, err := config.NewRoutes(
+				&config.Config{Gateway: config.Gateway{Routes: tt.routes}},
+				predicate.NewFactory(predicate.BuilderRegistry),
+				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
+				logger)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			actual := strings.Fields(strings.TrimSpace(buffer.String()))
+			var expected []string
+			for _, warning := range tt.expected {
+				expected = append(expected, strings.Fields(warning)...)
+			}
+			if !reflect.DeepEqual(expected, actual) && (len(expected) != 0 || len(actual) != 0) {
+				t.Errorf("expected warnings %v actual %v", tt.expected, buffer.String())
+			}
+		})
+	}
+}
+
+func TestNewRoutes_Metadata(t *testing.T) {
+	tests := []struct {
+		expectedErr    error
+		name           string
+		globalFilters  []config.ParameterizedItem
+		filters        []config.ParameterizedItem
+		expectedHeader http.Header
+	}{
+		{
+			name: "new routes should replace metadata references in filter args",
+			filters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Team", "value": "${metadata.team}"}},
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Tier", "value": "tier-${metadata.sla.tier}"}},
+			},
+			expectedHeader: http.Header{"X-Team": {"payments"}, "X-Tier": {"tier-1"}},
+		},
+		{
+			name: "new routes should replace metadata references in global filter args",
+			globalFilters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Team", "value": "${metadata.team}"}},
+			},
+			expectedHeader: http.Header{"X-Team": {"payments"}},
+		},
+		{
+			name: "new routes should keep metadata value type when arg is a single reference",
+			filters: []config.ParameterizedItem{
+				{Name: "IPFilter", Args: map[string]any{"allow": "${metadata.allowed}"}},
+			},
+			expectedHeader: http.Header{},
+		},
+		{
+			name: "new routes should keep unclosed metadata reference",
+			filters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Team", "value": "${metadata.team"}},
+			},
+			expectedHeader: http.Header{"X-Team": {"${metadata.team"}},
+		},
+		{
+			name: "new routes should fail when metadata reference is unknown",
+			filters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Owner", "value": "${metadata.owner}"}},
+			},
+			expectedErr: errors.New("map routes from config to gateway failed: unknown route metadata: route r1: filter AddRequestHeader: owner"),
+		},
+		{
+			name: "new routes should keep request-time expressions",
+			filters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Team", "value": "${metadata.team}/${request.method}"}},
+			},
+			expectedHeader: http.Header{"X-Team": {"payments/GET"}},
+		},
+		{
+			name: "new routes should keep escaped references as literals",
+			filters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Team", "value": "$${metadata.team}/$${foo}/${metadata.team}"}},
+			},
+			expectedHeader: http.Header{"X-Team": {"${metadata.team}/${foo}/payments"}},
+		},
+		{
+			name: "new routes should fail when expression variable is unknown",
+			filters: []config.ParameterizedItem{
+				{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Team", "value": "${request.verb}"}},
+			},
+			expectedErr: errors.New("map routes from config to gateway failed: parse filters failed: filter builder failed: " +
+				"filter AddRequestHeader and args map[name:X-Team value:${request.verb}]: " +
+				"failed to build add request header filter: unknown expression variable: request.verb"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{Gateway: config.Gateway{
+				GlobalFilters: tt.globalFilters,
+				Routes: []config.Route{{
+					ID:      "r1",
+					URI:     "https://example.com",
+					Filters: tt.filters,
+					Metadata: map[string]any{
+						"team":    "payments",
+						"sla":     map[string]any{"tier": 1},
+						"allowed": []any{"192.0.2.0/24"},
+					},
+				}},
+			}}
+
+			routes, err := config.NewRoutes(
+				cfg,
+				predicate.NewFactory(predicate.BuilderRegistry),
+				filter.NewFactory(filter.BuilderRegistry),
+				circuitbreaker.NewEventBus(),
+				slog.Default())
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Fatalf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if team, _ := routes[0].MetadataValue("team"); team != "payments" {
+				t.Errorf("expected route metadata team payments actual %v", team)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			ctx, cancel := gateway.NewGatewayContext(t.Context(), &routes[0], gateway.NewGatewayRequest(req))
+			defer cancel()
+			if err := routes[0].Filters.PreProcessAll(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !reflect.DeepEqual(tt.expectedHeader, ctx.Request.Headers) {
+				t.Errorf("expected headers %v actual %v", tt.expectedHeader, ctx.Request.Headers)
+			}
+		})
+	}
+}
+
+func TestNewRoutes_GlobalSecureHeaders(t *testing.T) {
+	cfg := &config.Config{Gateway: config.Gateway{
+		GlobalFilters: []config.ParameterizedItem{
+			{Name: "SecureHeaders", Args: map[string]any{"disable": []any{"Strict-Transport-Security"}}},
+		},
+		Routes: []config.Route{
+			{ID: "r1", URI: "https://example.com"},
+			{ID: "r2", URI: "https://example.org"},
+		},
+	}}
+
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
+		slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, route := range routes {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		ctx, _ := gateway.NewGatewayContext(t.Context(), &route, gateway.NewGatewayRequest(req))
+		ctx.Response = gateway.NewGatewayResponse(&http.Response{StatusCode: http.StatusOK, Header: http.Header{}})
+		if err = route.Filters.PostProcessAll(ctx); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if ctx.Response.Headers.Get("X-Content-Type-Options") != "nosniff" ||
+			ctx.Response.Headers.Get("Strict-Transport-Security") != "" {
+			t.Errorf("expected route %s secure headers actual %v", route.ID, ctx.Response.Headers)
+		}
+	}
+}
+
+func TestNewRoutes_SizeLimits(t *testing.T) {
+	cfg := &config.Config{Gateway: config.Gateway{
+		GlobalRequestSize:  1024,
+		GlobalResponseSize: 2048,
+		GlobalFilters: []config.ParameterizedItem{
+			{Name: "AddRequestHeader", Args: map[string]any{"name": "X-Test", "value": "test"}},
+		},
+		Routes: []config.Route{
+			{ID: "global", URI: "https://example.com"},
+			{ID: "route", URI: "https://example.com", RequestSize: 10, ResponseSize: 20},
+			{ID: "disabled", URI: "https://example.com", RequestSize: -1, ResponseSize: -1},
+		},
+	}}
+	expected := map[string][]string{
+		"global":   {filter.RequestSizeFilterName, filter.AddRequestHeaderFilterName, filter.ResponseSizeFilterName},
+		"route":    {filter.RequestSizeFilterName, filter.AddRequestHeaderFilterName, filter.ResponseSizeFilterName},
+		"disabled": {filter.AddRequestHeaderFilterName},
+	}
+	expectedRequestSizes := map[string]int64{"global": 1024, "route": 10}
+
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
+		slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, route := range routes {
+		names := make([]string, 0, len(route.Filters))
+		for _, f := range route.Filters {
+			names = append(names, f.Name())
+		}
+		if !reflect.DeepEqual(expected[route.ID], names) {
+			t.Errorf("expected route %s filters %v actual %v", route.ID, expected[route.ID], names)
+		}
+		maxSize, limited := expectedRequestSizes[route.ID]
+		if !limited {
+			continue
+		}
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(strings.Repeat("a", int(maxSize)+1)))
+		ctx, _ := gateway.NewGatewayContext(t.Context(), &route, gateway.NewGatewayRequest(req))
+		if err = route.Filters.PreProcessAll(ctx); !errors.Is(err, gateway.ErrRequestTooLarge) {
+			t.Errorf("expected route %s err %s actual %v", route.ID, gateway.ErrRequestTooLarge, err)
+		}
+	}
+}
+
+func TestNewRoutes_ResponseSizeLimitsUncompressedBody(t *testing.T) {
+	cfg := &config.Config{Gateway: config.Gateway{
+		Routes: []config.Route{
+			{
+				ID:           "compressed",
+				URI:          "https://example.com",
+				ResponseSize: 100,
+				Filters:      []config.ParameterizedItem{{Name: "Compress"}},
+			},
+		},
+	}}
+	routes, err := config.NewRoutes(
+		cfg,
+		predicate.NewFactory(predicate.BuilderRegistry),
+		filter.NewFactory(filter.BuilderRegistry),
+		circuitbreaker.NewEventBus(),
+		slog.Default())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &routes[0], gateway.NewGatewayRequest(req))
+	if err = routes[0].Filters.PreProcessAll(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// The body compresses well under the limit: only the uncompressed size goes over it.
+	body := strings.Repeat("a", 2000)
+	ctx.Response = &gateway.Response{
+		Status:     http.StatusOK,
+		Headers:    http.Header{"Content-Type": {"text/plain"}},
+		BodyReader: gateway.NewReplayableBody(io.NopCloser(strings.NewReader(body)), -1),
+	}
+
+	if err = routes[0].Filters.PostProcessAll(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if got := ctx.Response.Headers.Get("Content-Encoding"); got != "gzip" {
+		t.Errorf("expected gzip content encoding actual %s", got)
+	}
+	if _, err = io.ReadAll(ctx.Response.BodyReader); !errors.Is(err, gateway.ErrResponseTooLarge) {
+		t.Errorf("expected err %s actual %v", gateway.ErrResponseTooLarge, err)
+	}
+}
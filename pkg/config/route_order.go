@@ -0,0 +1,127 @@
+package config
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+// sortRoutes returns the routes sorted by order, the lower first. The routes with the same order keep their
+// config order.
+func sortRoutes(routes []Route) []Route {
+	sorted := slices.Clone(routes)
+	slices.SortStableFunc(sorted, func(a, b Route) int {
+		switch {
+		case a.Order < b.Order:
+			return -1
+		case a.Order > b.Order:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return sorted
+}
+
+// warnShadowedRoutes logs a warning for every route that can never match, because an earlier catch-all
+// route matches every request it would.
+//
+// A catch-all route has no predicates, or a single Path predicate whose patterns are all prefixes, like
+// '/api/**'. A later route is shadowed when the literal prefix of each of its Path patterns is under one of
+// the catch-all prefixes.
+func warnShadowedRoutes(routes []Route, logger *slog.Logger) {
+	var catchAlls []catchAllRoute
+	for _, route := range routes {
+		for _, catchAll := range catchAlls {
+			if catchAll.shadows(route) {
+				logger.Warn("route can never match, an earlier catch-all route shadows it",
+					"route", route.ID, "catch-all", catchAll.id)
+				break
+			}
+		}
+		if prefixes, ok := catchAllPrefixes(route); ok {
+			catchAlls = append(catchAlls, catchAllRoute{id: route.ID, prefixes: prefixes})
+		}
+	}
+}
+
+type catchAllRoute struct {
+	id       string
+	prefixes [][]string
+}
+
+func (c catchAllRoute) shadows(route Route) bool {
+	for _, prefix := range c.prefixes {
+		if len(prefix) == 0 {
+			return true
+		}
+	}
+	patterns, ok := pathPatterns(route)
+	if !ok {
+		return false
+	}
+	for _, pattern := range patterns {
+		segments := literalSegments(pattern)
+		if !slices.ContainsFunc(c.prefixes, func(prefix []string) bool {
+			return len(segments) >= len(prefix) && slices.Equal(segments[:len(prefix)], prefix)
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// catchAllPrefixes returns the literal segments of the prefixes matched by a catch-all route. A route with no
+// predicates matches every path, so its single prefix has no segments.
+func catchAllPrefixes(route Route) ([][]string, bool) {
+	if len(route.Predicates) == 0 {
+		return [][]string{{}}, true
+	}
+	if len(route.Predicates) != 1 {
+		return nil, false
+	}
+	patterns, ok := pathPatterns(route)
+	if !ok {
+		return nil, false
+	}
+	prefixes := make([][]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		prefix, isPrefix := strings.CutSuffix(pattern, "**")
+		if !isPrefix || !strings.HasSuffix(prefix, "/") || strings.ContainsAny(prefix, "*?{") {
+			return nil, false
+		}
+		prefixes = append(prefixes, literalSegments(prefix))
+	}
+	return prefixes, true
+}
+
+// pathPatterns returns the patterns of the first Path predicate of the route.
+func pathPatterns(route Route) ([]string, bool) {
+	for _, item := range route.Predicates {
+		if item.Name != predicate.PathPredicateName {
+			continue
+		}
+		patterns, err := shared.ConvertToStringSlice(item.Args["patterns"])
+		if err != nil || len(patterns) == 0 {
+			return nil, false
+		}
+		return patterns, true
+	}
+	return nil, false
+}
+
+// literalSegments returns the path segments of the pattern before the first one containing a wildcard or
+// a variable.
+func literalSegments(pattern string) []string {
+	segments := make([]string, 0)
+	for segment := range strings.SplitSeq(strings.Trim(pattern, "/"), "/") {
+		if segment == "" || strings.ContainsAny(segment, "*?{") {
+			break
+		}
+		segments = append(segments, segment)
+	}
+	return segments
+}
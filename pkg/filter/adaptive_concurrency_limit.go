@@ -0,0 +1,150 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// AdaptiveConcurrencyLimitFilterName is the name of the adaptive concurrency limit filter.
+const AdaptiveConcurrencyLimitFilterName = "AdaptiveConcurrencyLimit"
+
+// AdaptiveConcurrencyLimit is a filter that caps the number of in-flight requests of a route
+// with a limit that follows the backend latency: it shrinks when the backend slows down or
+// times out and grows back when it recovers.
+//
+// Requests over the limit are rejected with ErrConcurrencyLimitExceeded, like ConcurrencyLimit.
+type AdaptiveConcurrencyLimit struct {
+	limiter  *concurrency.AdaptiveLimiter
+	slotAttr string
+}
+
+// NewAdaptiveConcurrencyLimitFilter creates a new AdaptiveConcurrencyLimit filter.
+func NewAdaptiveConcurrencyLimitFilter(limiter *concurrency.AdaptiveLimiter) *AdaptiveConcurrencyLimit {
+	f := &AdaptiveConcurrencyLimit{
+		limiter: limiter,
+	}
+	f.slotAttr = fmt.Sprintf("GATEWAY_ADAPTIVE_CONCURRENCY_LIMIT_SLOT_%p", f)
+	return f
+}
+
+// NewAdaptiveConcurrencyLimitBuilder creates a new AdaptiveConcurrencyLimit builder.
+//
+// All the args are optional:
+// - initial-limit: the limit before any latency has been observed, greater than zero (default 20).
+// - min-limit: the lowest limit, not greater than max-limit (default 1).
+// - max-limit: the highest limit (default 200).
+// - headroom: the requests allowed on top of the ones served at minimum latency (default 4).
+// - min-rtt-window: how long the observed minimum latency is trusted (default forever).
+// - max-queue: the number of requests allowed to wait for a slot, not negative (default 0).
+// - queue-timeout: how long a request waits for a slot (default until the route timeout).
+func NewAdaptiveConcurrencyLimitBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		var settings concurrency.AdaptiveSettings
+		var err error
+		intArgs := []struct {
+			target *int
+			name   string
+		}{
+			{&settings.InitialLimit, "initial-limit"},
+			{&settings.MinLimit, "min-limit"},
+			{&settings.MaxLimit, "max-limit"},
+			{&settings.Headroom, "headroom"},
+			{&settings.MaxQueue, "max-queue"},
+		}
+		for _, arg := range intArgs {
+			if *arg.target, err = convertOptionalInt(args, arg.name); err != nil {
+				return nil, err
+			}
+		}
+		if err = validateAdaptiveSettings(args, settings); err != nil {
+			return nil, err
+		}
+		if settings.MinRTTWindow, err = convertOptionalDuration(args, "min-rtt-window"); err != nil {
+			return nil, err
+		}
+		if settings.QueueTimeout, err = convertOptionalDuration(args, "queue-timeout"); err != nil {
+			return nil, err
+		}
+		limiter := concurrency.NewAdaptiveLimiter(&shared.RealTime{}, settings)
+		return NewAdaptiveConcurrencyLimitFilter(limiter), nil
+	}
+}
+
+func validateAdaptiveSettings(args map[string]any, settings concurrency.AdaptiveSettings) error {
+	if args["initial-limit"] != nil && settings.InitialLimit <= 0 {
+		return fmt.Errorf("%w: 'initial-limit' must be greater than zero: %d",
+			ErrInvalidConcurrencyLimit, settings.InitialLimit)
+	}
+	if settings.MaxQueue < 0 {
+		return fmt.Errorf("%w: 'max-queue' must not be negative: %d", ErrInvalidConcurrencyLimit, settings.MaxQueue)
+	}
+	limits := settings.WithDefaults()
+	if limits.MinLimit > limits.MaxLimit {
+		return fmt.Errorf("%w: 'min-limit' %d must not be greater than 'max-limit' %d",
+			ErrInvalidConcurrencyLimit, limits.MinLimit, limits.MaxLimit)
+	}
+	return nil
+}
+
+// PreProcess takes a concurrency slot, waiting in the queue when configured.
+// If no slot is available, the filter returns an ErrConcurrencyLimitExceeded error.
+//
+// Until PostProcess runs, the slot is given back when the gateway context ends. A route
+// timeout at that point means the backend did not answer in time, which shrinks the limit.
+func (f *AdaptiveConcurrencyLimit) PreProcess(ctx *gateway.Context) error {
+	if err := f.limiter.Acquire(ctx.Context); err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("waiting for concurrency slot: %w", err)
+		}
+		return fmt.Errorf("%w: %w: limit %d", ErrConcurrencyLimitExceeded, err, f.limiter.Limit())
+	}
+	ctx.Attributes[f.slotAttr] = newStreamSlot(ctx.Context, f.limiter.Release, func(err error) {
+		if errors.Is(err, context.DeadlineExceeded) {
+			f.limiter.OnDrop()
+		}
+	})
+	return nil
+}
+
+// PostProcess feeds the backend latency to the limiter and ties the slot release to the
+// response body stream.
+//
+// The context fallback is stopped here on purpose: a long stream reaching the route timeout
+// says nothing about the backend latency, and the handler always closes the response body,
+// which releases the slot.
+func (f *AdaptiveConcurrencyLimit) PostProcess(ctx *gateway.Context) error {
+	slot, ok := ctx.Attributes[f.slotAttr].(*streamSlot)
+	if !ok {
+		return nil
+	}
+	delete(ctx.Attributes, f.slotAttr)
+	if !slot.detach() {
+		// The context already ended and the fallback released the slot.
+		return nil
+	}
+	if latency, ok := ctx.Attributes[gateway.BackendLatencyAttr].(time.Duration); ok {
+		previous := f.limiter.Limit()
+		if limit := f.limiter.OnSample(latency); limit != previous {
+			ctx.Logger.Debug("adaptive concurrency limit changed",
+				"previous", previous, "limit", limit, "latency", latency)
+		}
+	}
+	slot.releaseOnStreamEnd(ctx.Response.BodyReader)
+	return nil
+}
+
+// Limit returns the current concurrency limit.
+func (f *AdaptiveConcurrencyLimit) Limit() int {
+	return f.limiter.Limit()
+}
+
+// Name returns the name of the filter.
+func (f *AdaptiveConcurrencyLimit) Name() string {
+	return AdaptiveConcurrencyLimitFilterName
+}
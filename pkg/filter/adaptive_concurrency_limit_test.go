@@ -0,0 +1,174 @@
+package filter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewAdaptiveConcurrencyLimitBuilder(t *testing.T) {
+	tests := []struct {
+		args        map[string]any
+		expectedErr error
+		name        string
+	}{
+		{
+			name:        "build should succeed when no args are present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name: "build should succeed when args are present and are valid",
+			args: map[string]any{
+				"initial-limit":  10,
+				"min-limit":      2,
+				"max-limit":      50,
+				"headroom":       3,
+				"min-rtt-window": "1m",
+				"max-queue":      5,
+				"queue-timeout":  "100ms",
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "build should return error when max limit is not valid",
+			args: map[string]any{
+				"max-limit": "lots",
+			},
+			expectedErr: errors.New("failed to convert 'max-limit' attribute: value is required to be a valid int"),
+		},
+		{
+			name: "build should return error when initial limit is zero",
+			args: map[string]any{
+				"initial-limit": 0,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'initial-limit' must be greater than zero: 0"),
+		},
+		{
+			name: "build should return error when initial limit is negative",
+			args: map[string]any{
+				"initial-limit": -5,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'initial-limit' must be greater than zero: -5"),
+		},
+		{
+			name: "build should return error when min limit is greater than max limit",
+			args: map[string]any{
+				"min-limit": 50,
+				"max-limit": 10,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'min-limit' 50 must not be greater than 'max-limit' 10"),
+		},
+		{
+			name: "build should return error when min limit is greater than the default max limit",
+			args: map[string]any{
+				"min-limit": 500,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'min-limit' 500 must not be greater than 'max-limit' 200"),
+		},
+		{
+			name: "build should return error when max queue is negative",
+			args: map[string]any{
+				"max-queue": -1,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'max-queue' must not be negative: -1"),
+		},
+		{
+			name: "build should return error when min rtt window is not valid",
+			args: map[string]any{
+				"min-rtt-window": 60,
+			},
+			expectedErr: errors.New("failed to convert 'min-rtt-window' attribute: value is required to be a valid string"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := filter.NewAdaptiveConcurrencyLimitBuilder()
+
+			_, err := builder.Build(tt.args)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestAdaptiveConcurrencyLimit_Name(t *testing.T) {
+	f := filter.NewAdaptiveConcurrencyLimitFilter(
+		concurrency.NewAdaptiveLimiter(&shared.RealTime{}, concurrency.AdaptiveSettings{}))
+	if f.Name() != filter.AdaptiveConcurrencyLimitFilterName {
+		t.Errorf("expected name to be %s, got %s", filter.AdaptiveConcurrencyLimitFilterName, f.Name())
+	}
+}
+
+func TestAdaptiveConcurrencyLimit_PreProcess_RejectsWhenLimitReached(t *testing.T) {
+	f := filter.NewAdaptiveConcurrencyLimitFilter(
+		concurrency.NewAdaptiveLimiter(&shared.RealTime{}, concurrency.AdaptiveSettings{InitialLimit: 1}))
+	first, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+	second, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+
+	if err := f.PreProcess(first); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	err := f.PreProcess(second)
+
+	if !errors.Is(err, filter.ErrConcurrencyLimitExceeded) {
+		t.Errorf("expected err %v actual %v", filter.ErrConcurrencyLimitExceeded, err)
+	}
+}
+
+func TestAdaptiveConcurrencyLimit_ReleasesSlotWhenResponseStreamEnds(t *testing.T) {
+	limiter := concurrency.NewAdaptiveLimiter(&shared.RealTime{}, concurrency.AdaptiveSettings{InitialLimit: 1})
+	f := filter.NewAdaptiveConcurrencyLimitFilter(limiter)
+	route := &gateway.Route{Timeout: time.Minute, Logger: slog.New(slog.DiscardHandler)}
+	ctx, _ := gateway.NewGatewayContext(t.Context(), route, &gateway.Request{})
+
+	if err := f.PreProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	ctx.Attributes[gateway.BackendLatencyAttr] = 10 * time.Millisecond
+	ctx.Response = &gateway.Response{
+		Status:     http.StatusOK,
+		Headers:    http.Header{},
+		BodyReader: gateway.NewReplayableBody(io.NopCloser(bytes.NewReader([]byte("streamed"))), -1),
+	}
+	if err := f.PostProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if limiter.InFlight() != 1 {
+		t.Fatalf("expected the slot held until the body streamed, actual %d in flight", limiter.InFlight())
+	}
+	if _, err := io.ReadAll(ctx.Response.BodyReader); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if limiter.InFlight() != 0 {
+		t.Errorf("expected the slot released after the body streamed, actual %d in flight", limiter.InFlight())
+	}
+}
+
+func TestAdaptiveConcurrencyLimit_ShrinksLimitWhenBackendTimesOut(t *testing.T) {
+	limiter := concurrency.NewAdaptiveLimiter(&shared.RealTime{}, concurrency.AdaptiveSettings{InitialLimit: 10})
+	f := filter.NewAdaptiveConcurrencyLimitFilter(limiter)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Millisecond}, &gateway.Request{})
+
+	if err := f.PreProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	waitUntil(t, func() bool { return limiter.InFlight() == 0 })
+	if f.Limit() >= 10 {
+		t.Errorf("expected the limit to shrink after a backend timeout, actual %d", f.Limit())
+	}
+}
@@ -0,0 +1,401 @@
+package filter
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrUnknownEncoding is returned when a Compress filter is built with an encoding that has no registered encoder.
+var ErrUnknownEncoding = errors.New("unknown encoding")
+
+const (
+	// CompressFilterName is the name of the compress filter.
+	CompressFilterName = "Compress"
+
+	// DefaultCompressMinSize is the default size, in bytes, under which the responses are not compressed.
+	DefaultCompressMinSize = 1024
+
+	compressChunkSize = 32 * 1024
+)
+
+//nolint:gochecknoglobals
+var compressChunkPool = sync.Pool{
+	New: func() any {
+		chunk := make([]byte, compressChunkSize)
+		return &chunk
+	},
+}
+
+// CompressionEncoder is a streaming encoder of a content coding. The encoders of the compress/gzip and
+// compress/zlib packages, and the brotli and zstd writers of the most used libraries, implement it.
+type CompressionEncoder interface {
+	io.WriteCloser
+	// Flush writes the pending data to the underlying writer.
+	Flush() error
+	// Reset discards the encoder state and makes it write to the given writer.
+	Reset(dst io.Writer)
+}
+
+// CompressionEncoderFunc creates a new CompressionEncoder. The encoders are reused between responses through
+// Reset.
+type CompressionEncoderFunc func() CompressionEncoder
+
+// CompressionEncoderRegistry is a compression encoder registry.
+//
+// The key is the content coding, as sent in the Accept-Encoding and Content-Encoding headers.
+// The value is the encoder func.
+//
+// Only gzip and deflate are registered by default. Other codings, like br or zstd, can be registered with the
+// encoder of any library before the routes are built.
+//
+//nolint:gochecknoglobals
+var CompressionEncoderRegistry = map[string]CompressionEncoderFunc{
+	"gzip": func() CompressionEncoder {
+		return gzip.NewWriter(io.Discard)
+	},
+	// the deflate coding is the zlib format, not a raw deflate stream
+	"deflate": func() CompressionEncoder {
+		return zlib.NewWriter(io.Discard)
+	},
+}
+
+// defaultCompressEncodings returns the encodings the Compress filter uses by default, by preference.
+func defaultCompressEncodings() []string {
+	return []string{"br", "zstd", "gzip", "deflate"}
+}
+
+// defaultCompressExcludedTypes returns the media types the Compress filter does not compress by default: the
+// ones that are already compressed.
+func defaultCompressExcludedTypes() []string {
+	return []string{
+		"image/*",
+		"audio/*",
+		"video/*",
+		"font/woff",
+		"font/woff2",
+		"application/gzip",
+		"application/zip",
+		"application/zstd",
+		"application/x-7z-compressed",
+		"application/x-rar-compressed",
+	}
+}
+
+// Compress is a filter that compresses the response body with the encoding the client accepts.
+//
+// The encoding is negotiated with the Accept-Encoding header of the request, among the configured ones: the
+// one with the highest quality wins, and the configured order breaks the ties. The body is compressed while it
+// streams to the client, so it is never buffered.
+//
+// The responses that are already encoded, that are smaller than the min size, whose media type is excluded, or
+// that the backend marked with Cache-Control: no-transform are not compressed. Neither are the responses to
+// HEAD requests, the partial ones and the ones without a body.
+//
+// The server-sent event streams are flushed after each read from the backend, so every event reaches the
+// client as soon as it is received.
+type Compress struct {
+	pools         map[string]*sync.Pool
+	encodings     []string
+	excludedTypes []string
+	minSize       int64
+}
+
+// NewCompressFilter creates a new CompressFilter.
+//
+// The encodings are the content codings offered, by preference, and default to the registered ones among br,
+// zstd, gzip and deflate. It returns an error when an encoding has no registered encoder. The excluded types
+// are media types, like application/pdf, or media ranges, like image/*, and default to the already compressed
+// ones. The min size is in bytes, and a response of unknown length is always compressed.
+func NewCompressFilter(encodings []string, minSize int64, excludedTypes []string) (*Compress, error) {
+	encodings = slices.Clone(encodings)
+	if len(encodings) == 0 {
+		encodings = slices.DeleteFunc(defaultCompressEncodings(), func(encoding string) bool {
+			return CompressionEncoderRegistry[encoding] == nil
+		})
+	}
+	excludedTypes = slices.Clone(excludedTypes)
+	if excludedTypes == nil {
+		excludedTypes = defaultCompressExcludedTypes()
+	}
+	pools := make(map[string]*sync.Pool, len(encodings))
+	for idx, encoding := range encodings {
+		encoding = strings.ToLower(strings.TrimSpace(encoding))
+		newEncoder := CompressionEncoderRegistry[encoding]
+		if newEncoder == nil {
+			return nil, fmt.Errorf("failed to build compress filter: %w: %s", ErrUnknownEncoding, encoding)
+		}
+		encodings[idx] = encoding
+		pools[encoding] = &sync.Pool{New: func() any { return newEncoder() }}
+	}
+	for idx, excludedType := range excludedTypes {
+		excludedTypes[idx] = strings.ToLower(strings.TrimSpace(excludedType))
+	}
+	return &Compress{
+		pools:         pools,
+		encodings:     encodings,
+		excludedTypes: excludedTypes,
+		minSize:       minSize,
+	}, nil
+}
+
+// NewCompressBuilder creates a new CompressBuilder.
+//
+// The args are:
+// - encodings: optional, the content codings offered, by preference (default br, zstd, gzip and deflate, the
+// registered ones).
+// - min-size: optional, the size in bytes under which the responses are not compressed (default 1024).
+// - excluded-types: optional, the media types not compressed (default the already compressed ones).
+func NewCompressBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		encodings, err := convertOptionalStringSlice(args, "encodings")
+		if err != nil {
+			return nil, err
+		}
+		minSize := DefaultCompressMinSize
+		if args["min-size"] != nil {
+			if minSize, err = convertOptionalInt(args, "min-size"); err != nil {
+				return nil, err
+			}
+		}
+		excludedTypes, err := convertOptionalStringSlice(args, "excluded-types")
+		if err != nil {
+			return nil, err
+		}
+		return NewCompressFilter(encodings, int64(minSize), excludedTypes)
+	}
+}
+
+// PreProcess does nothing.
+func (f *Compress) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess compresses the response body when the client accepts one of the encodings.
+func (f *Compress) PostProcess(ctx *gateway.Context) error {
+	if !f.isCompressible(ctx) {
+		return nil
+	}
+	addVary(ctx.Response.Headers, "Accept-Encoding")
+	encoding := negotiateEncoding(ctx.Request.Headers.Values("Accept-Encoding"), f.encodings)
+	if encoding == "" {
+		return nil
+	}
+	headers := ctx.Response.Headers
+	headers.Set("Content-Encoding", encoding)
+	headers.Del("Content-Length")
+	headers.Del("Accept-Ranges")
+	// the compressed representation is not byte for byte the one the backend tagged
+	if etag := headers.Get("Etag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		headers.Set("Etag", "W/"+etag)
+	}
+	pool := f.pools[encoding]
+	body := &compressedBody{
+		source:    ctx.Response.BodyReader,
+		pool:      pool,
+		chunk:     compressChunkPool.Get().(*[]byte), //nolint:forcetypeassert
+		flushEach: isEventStream(headers),
+	}
+	body.encoder = pool.Get().(CompressionEncoder) //nolint:forcetypeassert
+	body.encoder.Reset(&body.buf)
+	ctx.Response.BodyReader = gateway.NewRewrittenReplayableBody(body, ctx.Response.BodyReader)
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *Compress) Name() string {
+	return CompressFilterName
+}
+
+func (f *Compress) isCompressible(ctx *gateway.Context) bool {
+	response := ctx.Response
+	length := response.BodyReader.Len()
+	switch {
+	case ctx.Request.Method == http.MethodHead,
+		response.Status < http.StatusOK,
+		response.Status == http.StatusNoContent,
+		response.Status == http.StatusPartialContent,
+		response.Status == http.StatusNotModified,
+		length == 0,
+		length > 0 && length < f.minSize:
+		return false
+	default:
+	}
+	headers := response.Headers
+	if encoding := headers.Get("Content-Encoding"); encoding != "" && !strings.EqualFold(encoding, "identity") {
+		return false
+	}
+	if headers.Get("Content-Range") != "" || hasDirective(headers.Values("Cache-Control"), "no-transform") {
+		return false
+	}
+	return !f.isExcludedType(headers.Get("Content-Type"))
+}
+
+func (f *Compress) isExcludedType(contentType string) bool {
+	mediaType := mediaType(contentType)
+	if mediaType == "" {
+		return false
+	}
+	mainType, _, _ := strings.Cut(mediaType, "/")
+	for _, excludedType := range f.excludedTypes {
+		if excludedType == mediaType || excludedType == mainType+"/*" {
+			return true
+		}
+	}
+	return false
+}
+
+// negotiateEncoding returns the encoding with the highest quality in the Accept-Encoding header values, the
+// first in the offered order on ties, or an empty string when the client accepts none.
+func negotiateEncoding(acceptEncoding []string, offered []string) string {
+	qualities := make(map[string]float64)
+	for _, value := range acceptEncoding {
+		for item := range strings.SplitSeq(value, ",") {
+			coding, params, _ := strings.Cut(item, ";")
+			coding = strings.ToLower(strings.TrimSpace(coding))
+			if coding == "" {
+				continue
+			}
+			qualities[coding] = encodingQuality(params)
+		}
+	}
+	best, bestQuality := "", 0.0
+	for _, encoding := range offered {
+		quality, ok := qualities[encoding]
+		if !ok {
+			quality = qualities["*"]
+		}
+		if quality > bestQuality {
+			best, bestQuality = encoding, quality
+		}
+	}
+	return best
+}
+
+// encodingQuality returns the q parameter of an Accept-Encoding item, 1 when it is missing or invalid.
+func encodingQuality(params string) float64 {
+	for param := range strings.SplitSeq(params, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		if !strings.EqualFold(strings.TrimSpace(name), "q") {
+			continue
+		}
+		quality, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || quality < 0 || quality > 1 {
+			return 1
+		}
+		return quality
+	}
+	return 1
+}
+
+// mediaType returns the media type of the Content-Type header, without its parameters and in lower case.
+func mediaType(contentType string) string {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+func isEventStream(headers http.Header) bool {
+	return mediaType(headers.Get("Content-Type")) == "text/event-stream"
+}
+
+// hasDirective returns whether the comma separated header values hold the directive.
+func hasDirective(values []string, directive string) bool {
+	for _, value := range values {
+		for item := range strings.SplitSeq(value, ",") {
+			name, _, _ := strings.Cut(item, "=")
+			if strings.EqualFold(strings.TrimSpace(name), directive) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// addVary adds the header name to the Vary header, unless it is already there.
+func addVary(headers http.Header, name string) {
+	if hasDirective(headers.Values("Vary"), name) || hasDirective(headers.Values("Vary"), "*") {
+		return
+	}
+	headers.Add("Vary", name)
+}
+
+// compressedBody compresses the source body as it is read.
+type compressedBody struct {
+	source    io.ReadCloser
+	encoder   CompressionEncoder
+	pool      *sync.Pool
+	chunk     *[]byte
+	buf       bytes.Buffer
+	flushEach bool
+	done      bool
+	closed    bool
+}
+
+// Read returns the compressed data, reading from the source until the encoder has output.
+func (b *compressedBody) Read(output []byte) (int, error) {
+	if b.closed {
+		return 0, http.ErrBodyReadAfterClose
+	}
+	for b.buf.Len() == 0 && !b.done {
+		if err := b.fill(); err != nil {
+			return 0, err
+		}
+	}
+	if b.buf.Len() == 0 {
+		return 0, io.EOF
+	}
+	return b.buf.Read(output) //nolint:wrapcheck
+}
+
+// fill encodes the next read of the source. The encoder is flushed after every read of an event stream, and
+// closed at the end of the source, writing the trailer of the encoding.
+func (b *compressedBody) fill() error {
+	chunk := *b.chunk
+	read, err := b.source.Read(chunk)
+	if read > 0 {
+		if _, writeErr := b.encoder.Write(chunk[:read]); writeErr != nil {
+			return fmt.Errorf("compress response body: %w", writeErr)
+		}
+		if b.flushEach {
+			if flushErr := b.encoder.Flush(); flushErr != nil {
+				return fmt.Errorf("compress response body: %w", flushErr)
+			}
+		}
+	}
+	switch {
+	case errors.Is(err, io.EOF):
+		b.done = true
+		if closeErr := b.encoder.Close(); closeErr != nil {
+			return fmt.Errorf("compress response body: %w", closeErr)
+		}
+		return nil
+	case err != nil:
+		return err //nolint:wrapcheck
+	default:
+		return nil
+	}
+}
+
+// Close closes the source and gives the encoder and the chunk back to their pools.
+func (b *compressedBody) Close() error {
+	if b.closed {
+		return nil
+	}
+	b.closed = true
+	b.encoder.Reset(io.Discard)
+	b.pool.Put(b.encoder)
+	b.encoder = nil
+	compressChunkPool.Put(b.chunk)
+	b.chunk = nil
+	return b.source.Close() //nolint:wrapcheck
+}
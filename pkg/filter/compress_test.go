@@ -0,0 +1,335 @@
+package filter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewCompressBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are not present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name: "build should succeed when args are present and are valid",
+			args: map[string]any{
+				"encodings":      []any{"deflate", "GZIP"},
+				"min-size":       256,
+				"excluded-types": []any{"application/pdf"},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when encodings argument is not valid",
+			args:        map[string]any{"encodings": "gzip"},
+			expectedErr: errors.New("failed to convert 'encodings' attribute: value is required to be a valid slice"),
+		},
+		{
+			name:        "build should fail when min size argument is not valid",
+			args:        map[string]any{"min-size": "big"},
+			expectedErr: errors.New("failed to convert 'min-size' attribute: value is required to be a valid int"),
+		},
+		{
+			name:        "build should fail when excluded types argument is not valid",
+			args:        map[string]any{"excluded-types": "image/*"},
+			expectedErr: errors.New("failed to convert 'excluded-types' attribute: value is required to be a valid slice"),
+		},
+		{
+			name:        "build should fail when encoding has no registered encoder",
+			args:        map[string]any{"encodings": []any{"br"}},
+			expectedErr: errors.New("failed to build compress filter: unknown encoding: br"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewCompressBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestCompressFilter_PostProcess(t *testing.T) {
+	body := strings.Repeat("compressible body ", 100)
+	tests := []struct {
+		requestHeaders   http.Header
+		responseHeaders  http.Header
+		expectedHeaders  http.Header
+		name             string
+		method           string
+		body             string
+		expectedEncoding string
+		status           int
+		contentLength    int64
+	}{
+		{
+			name:             "post process should compress with gzip when client accepts it",
+			requestHeaders:   http.Header{"Accept-Encoding": {"gzip, deflate"}},
+			responseHeaders:  http.Header{"Content-Type": {"application/json"}, "Etag": {`"v1"`}},
+			body:             body,
+			contentLength:    int64(len(body)),
+			expectedEncoding: "gzip",
+			expectedHeaders: http.Header{
+				"Content-Type":     {"application/json"},
+				"Content-Encoding": {"gzip"},
+				"Etag":             {`W/"v1"`},
+				"Vary":             {"Accept-Encoding"},
+			},
+		},
+		{
+			name:             "post process should compress with the encoding of highest quality",
+			requestHeaders:   http.Header{"Accept-Encoding": {"gzip;q=0.5, deflate"}},
+			responseHeaders:  http.Header{"Vary": {"Origin"}},
+			body:             body,
+			contentLength:    -1,
+			expectedEncoding: "deflate",
+			expectedHeaders: http.Header{
+				"Content-Encoding": {"deflate"},
+				"Vary":             {"Origin", "Accept-Encoding"},
+			},
+		},
+		{
+			name:             "post process should compress when client accepts any encoding",
+			requestHeaders:   http.Header{"Accept-Encoding": {"*"}},
+			responseHeaders:  http.Header{"Content-Length": {"1800"}, "Accept-Ranges": {"bytes"}},
+			body:             body,
+			contentLength:    int64(len(body)),
+			expectedEncoding: "gzip",
+			expectedHeaders: http.Header{
+				"Content-Encoding": {"gzip"},
+				"Vary":             {"Accept-Encoding"},
+			},
+		},
+		{
+			name:            "post process should not compress when client does not accept any encoding",
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip;q=0, br"}},
+			responseHeaders: http.Header{},
+			body:            body,
+			contentLength:   int64(len(body)),
+			expectedHeaders: http.Header{"Vary": {"Accept-Encoding"}},
+		},
+		{
+			name:            "post process should not compress when body is smaller than min size",
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip"}},
+			responseHeaders: http.Header{},
+			body:            "small",
+			contentLength:   int64(len("small")),
+			expectedHeaders: http.Header{},
+		},
+		{
+			name:            "post process should not compress when body is already encoded",
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip"}},
+			responseHeaders: http.Header{"Content-Encoding": {"br"}},
+			body:            body,
+			contentLength:   int64(len(body)),
+			expectedHeaders: http.Header{"Content-Encoding": {"br"}},
+		},
+		{
+			name:            "post process should not compress when media type is excluded",
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip"}},
+			responseHeaders: http.Header{"Content-Type": {"image/png"}},
+			body:            body,
+			contentLength:   int64(len(body)),
+			expectedHeaders: http.Header{"Content-Type": {"image/png"}},
+		},
+		{
+			name:            "post process should not compress when backend forbids transformations",
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip"}},
+			responseHeaders: http.Header{"Cache-Control": {"public, no-transform"}},
+			body:            body,
+			contentLength:   int64(len(body)),
+			expectedHeaders: http.Header{"Cache-Control": {"public, no-transform"}},
+		},
+		{
+			name:            "post process should not compress when request is head",
+			method:          http.MethodHead,
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip"}},
+			responseHeaders: http.Header{},
+			body:            body,
+			contentLength:   int64(len(body)),
+			expectedHeaders: http.Header{},
+		},
+		{
+			name:            "post process should not compress when response is not modified",
+			status:          http.StatusNotModified,
+			requestHeaders:  http.Header{"Accept-Encoding": {"gzip"}},
+			responseHeaders: http.Header{},
+			body:            body,
+			contentLength:   int64(len(body)),
+			expectedHeaders: http.Header{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			method := tt.method
+			if method == "" {
+				method = http.MethodGet
+			}
+			status := tt.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+			req, _ := http.NewRequestWithContext(t.Context(), method, "https://example.org/items", nil)
+			req.Header = tt.requestHeaders
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			ctx.Response = gateway.NewGatewayResponse(&http.Response{
+				StatusCode:    status,
+				Header:        tt.responseHeaders,
+				Body:          io.NopCloser(strings.NewReader(tt.body)),
+				ContentLength: tt.contentLength,
+			})
+			f, _ := filter.NewCompressFilter(nil, filter.DefaultCompressMinSize, nil)
+
+			if err := f.PostProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if !reflect.DeepEqual(tt.expectedHeaders, ctx.Response.Headers) {
+				t.Errorf("expected headers %v actual %v", tt.expectedHeaders, ctx.Response.Headers)
+			}
+			if tt.expectedEncoding != "" && ctx.Response.BodyReader.Len() != -1 {
+				t.Errorf("expected unknown length actual %d", ctx.Response.BodyReader.Len())
+			}
+			actual := decodeBody(t, tt.expectedEncoding, ctx.Response.BodyReader)
+			if actual != tt.body {
+				t.Errorf("expected body %q actual %q", tt.body, actual)
+			}
+			if err := ctx.Response.BodyReader.Close(); err != nil {
+				t.Errorf("unexpected close error %v", err)
+			}
+		})
+	}
+}
+
+func TestCompressFilter_PostProcess_FlushesEachEvent(t *testing.T) {
+	source, backend := io.Pipe()
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.org/events", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+	ctx.Response = gateway.NewGatewayResponse(&http.Response{
+		StatusCode:    http.StatusOK,
+		Header:        http.Header{"Content-Type": {"text/event-stream"}},
+		Body:          source,
+		ContentLength: -1,
+	})
+	f, _ := filter.NewCompressFilter(nil, filter.DefaultCompressMinSize, nil)
+	if err := f.PostProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer ctx.Response.BodyReader.Close() //nolint:errcheck
+
+	var received bytes.Buffer
+	for _, event := range []string{"data: first\n\n", "data: second\n\n"} {
+		go func() {
+			_, _ = backend.Write([]byte(event))
+		}()
+		output := make([]byte, 1024)
+		read, err := ctx.Response.BodyReader.Read(output)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		received.Write(output[:read])
+		// the event must be decodable before the stream ends
+		reader, err := gzip.NewReader(bytes.NewReader(received.Bytes()))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		decoded, _ := io.ReadAll(reader)
+		if !strings.HasSuffix(string(decoded), event) {
+			t.Errorf("expected event %q to be flushed actual %q", event, decoded)
+		}
+	}
+}
+
+func TestCompressFilter_PostProcess_KeepsStreamsApart(t *testing.T) {
+	tests := []struct {
+		name           string
+		contentLength  int64
+		expectedStream bool
+	}{
+		{
+			name:           "compressed body of known length should not be a stream",
+			contentLength:  4096,
+			expectedStream: false,
+		},
+		{
+			name:           "compressed stream should be a stream",
+			contentLength:  -1,
+			expectedStream: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.org/users", nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			ctx.Response = gateway.NewGatewayResponse(&http.Response{
+				StatusCode:    http.StatusOK,
+				Header:        http.Header{"Content-Type": {"application/json"}},
+				Body:          io.NopCloser(strings.NewReader(strings.Repeat("a", 4096))),
+				ContentLength: tt.contentLength,
+			})
+			f, _ := filter.NewCompressFilter(nil, filter.DefaultCompressMinSize, nil)
+
+			if err := f.PostProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			defer ctx.Response.BodyReader.Close() //nolint:errcheck
+
+			if ctx.Response.Headers.Get("Content-Encoding") != "gzip" {
+				t.Fatalf("expected the body compressed")
+			}
+			if ctx.Response.BodyReader.IsStream() != tt.expectedStream {
+				t.Errorf("expected stream %t actual %t", tt.expectedStream, ctx.Response.BodyReader.IsStream())
+			}
+		})
+	}
+}
+
+func decodeBody(t *testing.T, encoding string, body io.Reader) string {
+	t.Helper()
+	var reader io.Reader
+	switch encoding {
+	case "gzip":
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		reader = gzipReader
+	case "deflate":
+		zlibReader, err := zlib.NewReader(body)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		reader = zlibReader
+	default:
+		reader = body
+	}
+	decoded, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return string(decoded)
+}
@@ -0,0 +1,159 @@
+package filter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/ratelimit"
+)
+
+// ErrConcurrencyLimitExceeded is returned when the request could not get a concurrency slot.
+var ErrConcurrencyLimitExceeded = errors.New("concurrency limit exceeded")
+
+// ErrInvalidConcurrencyLimit is returned when a concurrency limit filter is built with limits out of range.
+var ErrInvalidConcurrencyLimit = errors.New("invalid concurrency limit")
+
+// ConcurrencyLimitFilterName is the name of the concurrency limit filter.
+const ConcurrencyLimitFilterName = "ConcurrencyLimit"
+
+// ConcurrencyLimit is a filter that caps the number of in-flight requests of a route, or of
+// each key within a route when a key func is configured.
+//
+// The slot is held until the response body has been fully streamed to the client or
+// closed, so long downloads and server-sent event streams count as in-flight for as long
+// as they run.
+type ConcurrencyLimit struct {
+	limiter     *concurrency.KeyedLimiter
+	keyFunc     ratelimit.KeyFunc
+	releaseAttr string
+}
+
+// NewConcurrencyLimitFilter creates a new ConcurrencyLimit filter. A nil keyFunc shares a
+// single limit among all the requests of the route.
+func NewConcurrencyLimitFilter(limiter *concurrency.KeyedLimiter, keyFunc ratelimit.KeyFunc) *ConcurrencyLimit {
+	if keyFunc == nil {
+		keyFunc = func(_ *gateway.Context) string {
+			return ""
+		}
+	}
+	f := &ConcurrencyLimit{
+		limiter: limiter,
+		keyFunc: keyFunc,
+	}
+	// Each instance needs its own attribute: a route may chain a global and a route
+	// concurrency limit, and each must release its own slot.
+	f.releaseAttr = fmt.Sprintf("GATEWAY_CONCURRENCY_LIMIT_RELEASE_%p", f)
+	return f
+}
+
+// NewConcurrencyLimitBuilder creates a new ConcurrencyLimit builder.
+//
+// The args are expected to contain the following keys:
+// - max-concurrent: the number of in-flight requests allowed, greater than zero.
+// - max-queue: optional, the number of requests allowed to wait for a slot, not negative (default 0).
+// - queue-timeout: optional, how long a request waits for a slot (default until the route timeout).
+// - key: optional, a rate limit key func name to apply the limit per key instead of per route.
+// Other specific args are passed to the key func builder depending on the implementation details.
+func NewConcurrencyLimitBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		maxConcurrent, err := shared.ConvertToInt(args["max-concurrent"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'max-concurrent' attribute: %w", err)
+		}
+		if maxConcurrent <= 0 {
+			return nil, fmt.Errorf("%w: 'max-concurrent' must be greater than zero: %d",
+				ErrInvalidConcurrencyLimit, maxConcurrent)
+		}
+		maxQueue, err := convertOptionalInt(args, "max-queue")
+		if err != nil {
+			return nil, err
+		}
+		if maxQueue < 0 {
+			return nil, fmt.Errorf("%w: 'max-queue' must not be negative: %d", ErrInvalidConcurrencyLimit, maxQueue)
+		}
+		queueTimeout, err := convertOptionalDuration(args, "queue-timeout")
+		if err != nil {
+			return nil, err
+		}
+		limiter := concurrency.NewKeyedLimiter(maxConcurrent, maxQueue, queueTimeout)
+		if args["key"] == nil {
+			return NewConcurrencyLimitFilter(limiter, nil), nil
+		}
+		key, err := shared.ConvertToString(args["key"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'key' attribute: %w", err)
+		}
+		keyFuncBuilder, isPresent := ratelimit.KeyFuncBuilderRegistry[key]
+		if !isPresent {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidRateLimitKey, key)
+		}
+		keyFunc, err := keyFuncBuilder.Build(args)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build concurrency limit key: %w", err)
+		}
+		return NewConcurrencyLimitFilter(limiter, keyFunc), nil
+	}
+}
+
+// PreProcess takes a concurrency slot, waiting in the queue when configured.
+// If no slot is available, the filter returns an ErrConcurrencyLimitExceeded error.
+//
+// The slot is also given back when the gateway context ends, which covers every path
+// where PostProcess never runs: backend errors, a failing filter, or a panic.
+func (f *ConcurrencyLimit) PreProcess(ctx *gateway.Context) error {
+	release, err := f.limiter.Acquire(ctx.Context, f.keyFunc(ctx))
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("waiting for concurrency slot: %w", err)
+		}
+		return fmt.Errorf("%w: %w", ErrConcurrencyLimitExceeded, err)
+	}
+	ctx.Attributes[f.releaseAttr] = newStreamSlot(ctx.Context, release, nil)
+	return nil
+}
+
+// PostProcess ties the slot release to the response body stream: it is given back once
+// the body has been fully read or closed.
+func (f *ConcurrencyLimit) PostProcess(ctx *gateway.Context) error {
+	slot, ok := ctx.Attributes[f.releaseAttr].(*streamSlot)
+	if !ok {
+		return nil
+	}
+	delete(ctx.Attributes, f.releaseAttr)
+	slot.releaseOnStreamEnd(ctx.Response.BodyReader)
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *ConcurrencyLimit) Name() string {
+	return ConcurrencyLimitFilterName
+}
+
+// convertOptionalInt converts the named arg to an int, returning 0 when it is not present.
+func convertOptionalInt(args map[string]any, name string) (int, error) {
+	if args[name] == nil {
+		return 0, nil
+	}
+	value, err := shared.ConvertToInt(args[name])
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
+
+// convertOptionalDuration converts the named arg to a time.Duration, returning 0 when it is not present.
+func convertOptionalDuration(args map[string]any, name string) (time.Duration, error) {
+	if args[name] == nil {
+		return 0, nil
+	}
+	value, err := shared.ConvertToDuration(args[name])
+	if err != nil {
+		return 0, fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
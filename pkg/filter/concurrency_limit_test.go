@@ -0,0 +1,187 @@
+package filter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewConcurrencyLimitBuilder(t *testing.T) {
+	tests := []struct {
+		args        map[string]any
+		expectedErr error
+		name        string
+	}{
+		{
+			name: "build should succeed when only max concurrent is present",
+			args: map[string]any{
+				"max-concurrent": 10,
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "build should succeed when queue and key args are present and are valid",
+			args: map[string]any{
+				"max-concurrent": 10,
+				"max-queue":      5,
+				"queue-timeout":  "1s",
+				"key":            "header",
+				"header-name":    "X-Tenant",
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should return error when max concurrent is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'max-concurrent' attribute: value is required"),
+		},
+		{
+			name: "build should return error when max concurrent is zero",
+			args: map[string]any{
+				"max-concurrent": 0,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'max-concurrent' must be greater than zero: 0"),
+		},
+		{
+			name: "build should return error when max concurrent is negative",
+			args: map[string]any{
+				"max-concurrent": -1,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'max-concurrent' must be greater than zero: -1"),
+		},
+		{
+			name: "build should return error when max queue is negative",
+			args: map[string]any{
+				"max-concurrent": 10,
+				"max-queue":      -1,
+			},
+			expectedErr: errors.New("invalid concurrency limit: 'max-queue' must not be negative: -1"),
+		},
+		{
+			name: "build should return error when max queue is not valid",
+			args: map[string]any{
+				"max-concurrent": 10,
+				"max-queue":      "many",
+			},
+			expectedErr: errors.New("failed to convert 'max-queue' attribute: value is required to be a valid int"),
+		},
+		{
+			name: "build should return error when queue timeout is not valid",
+			args: map[string]any{
+				"max-concurrent": 10,
+				"queue-timeout":  "soon",
+			},
+			expectedErr: errors.New("failed to convert 'queue-timeout' attribute: value is required to be a valid duration"),
+		},
+		{
+			name: "build should return error when key is not registered",
+			args: map[string]any{
+				"max-concurrent": 10,
+				"key":            "invent",
+			},
+			expectedErr: errors.New("invalid rate limit key: invent"),
+		},
+		{
+			name: "build should return error when key func build failed",
+			args: map[string]any{
+				"max-concurrent": 10,
+				"key":            "header",
+			},
+			expectedErr: errors.New("failed to build concurrency limit key: failed to convert 'header-name' attribute: value is required"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := filter.NewConcurrencyLimitBuilder()
+
+			_, err := builder.Build(tt.args)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestConcurrencyLimit_Name(t *testing.T) {
+	f := filter.NewConcurrencyLimitFilter(concurrency.NewKeyedLimiter(1, 0, 0), nil)
+	if f.Name() != filter.ConcurrencyLimitFilterName {
+		t.Errorf("expected name to be %s, got %s", filter.ConcurrencyLimitFilterName, f.Name())
+	}
+}
+
+func TestConcurrencyLimit_PreProcess_RejectsWhenLimitReached(t *testing.T) {
+	limiter := concurrency.NewKeyedLimiter(1, 0, 0)
+	f := filter.NewConcurrencyLimitFilter(limiter, nil)
+	first, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+	second, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+
+	if err := f.PreProcess(first); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	err := f.PreProcess(second)
+
+	if !errors.Is(err, filter.ErrConcurrencyLimitExceeded) {
+		t.Errorf("expected err %v actual %v", filter.ErrConcurrencyLimitExceeded, err)
+	}
+}
+
+func TestConcurrencyLimit_ReleasesSlotWhenResponseStreamEnds(t *testing.T) {
+	limiter := concurrency.NewKeyedLimiter(1, 0, 0)
+	f := filter.NewConcurrencyLimitFilter(limiter, nil)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+
+	if err := f.PreProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	ctx.Response = &gateway.Response{
+		Status:     http.StatusOK,
+		Headers:    http.Header{},
+		BodyReader: gateway.NewReplayableBody(io.NopCloser(bytes.NewReader([]byte("streamed"))), -1),
+	}
+	if err := f.PostProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if limiter.Len() != 1 {
+		t.Fatalf("expected the slot held until the body streamed, actual %d keys", limiter.Len())
+	}
+	if _, err := io.ReadAll(ctx.Response.BodyReader); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if limiter.Len() != 0 {
+		t.Errorf("expected the slot released after the body streamed, actual %d keys", limiter.Len())
+	}
+}
+
+func TestConcurrencyLimit_ReleasesSlotWhenContextEndsWithoutPostProcess(t *testing.T) {
+	limiter := concurrency.NewKeyedLimiter(1, 0, 0)
+	f := filter.NewConcurrencyLimitFilter(limiter, nil)
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+
+	if err := f.PreProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	cancel()
+
+	waitUntil(t, func() bool { return limiter.Len() == 0 })
+}
+
+func waitUntil(t *testing.T, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met in time")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,95 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+const (
+	// DecompressRequestFilterName is the name of the decompress request filter.
+	DecompressRequestFilterName = "DecompressRequest"
+
+	// DefaultDecompressMaxSize is the default max size, in bytes, of a decompressed request body.
+	DefaultDecompressMaxSize = 10 * 1024 * 1024
+
+	// DefaultDecompressMaxRatio is the default max ratio between the decompressed and the compressed sizes of a
+	// request body.
+	DefaultDecompressMaxRatio = 100
+)
+
+// DecompressRequest is a filter that decompresses the request body, for the backends that do not accept
+// compressed uploads. The body is decompressed while it streams to the backend, and the Content-Encoding and
+// Content-Length headers are removed.
+//
+// A request encoded with a coding that has no registered decoder, see gateway.ContentDecoderRegistry, fails
+// with a gateway.ErrUnsupportedContentEncoding error, and an invalid one with a gateway.ErrContentDecoding
+// error. A body that goes over the decode limits fails with a gateway.ErrDecodedLimitExceeded error, which
+// stops decompression bombs.
+type DecompressRequest struct {
+	limits gateway.DecodeLimits
+}
+
+// NewDecompressRequestFilter creates a new DecompressRequestFilter. A zero limit disables it.
+func NewDecompressRequestFilter(limits gateway.DecodeLimits) *DecompressRequest {
+	return &DecompressRequest{
+		limits: limits,
+	}
+}
+
+// NewDecompressRequestBuilder creates a new DecompressRequestBuilder.
+//
+// The args are:
+// - max-size: optional, the max size in bytes of the decompressed body (default 10MiB, 0 disables it).
+// - max-ratio: optional, the max ratio between the decompressed and the compressed sizes (default 100, 0
+// disables it).
+func NewDecompressRequestBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		limits := gateway.DecodeLimits{
+			MaxBytes: DefaultDecompressMaxSize,
+			MaxRatio: DefaultDecompressMaxRatio,
+		}
+		if args["max-size"] != nil {
+			maxSize, err := convertOptionalInt(args, "max-size")
+			if err != nil {
+				return nil, err
+			}
+			limits.MaxBytes = int64(maxSize)
+		}
+		if args["max-ratio"] != nil {
+			maxRatio, err := convertOptionalInt(args, "max-ratio")
+			if err != nil {
+				return nil, err
+			}
+			limits.MaxRatio = int64(maxRatio)
+		}
+		return NewDecompressRequestFilter(limits), nil
+	}
+}
+
+// PreProcess replaces the request body with its decompressed content.
+func (f *DecompressRequest) PreProcess(ctx *gateway.Context) error {
+	contentEncoding := strings.Join(ctx.Request.Headers.Values("Content-Encoding"), ",")
+	if len(gateway.ContentCodings(contentEncoding)) == 0 {
+		return nil
+	}
+	decoder, err := gateway.NewContentDecoder(contentEncoding, ctx.Request.BodyReader, f.limits)
+	if err != nil {
+		return fmt.Errorf("failed to decompress request body: %w", err)
+	}
+	ctx.Request.BodyReader = gateway.NewReplayableBody(decoder, -1)
+	ctx.Request.Headers.Del("Content-Encoding")
+	ctx.Request.Headers.Del("Content-Length")
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *DecompressRequest) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *DecompressRequest) Name() string {
+	return DecompressRequestFilterName
+}
@@ -0,0 +1,147 @@
+package filter_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func gzipBody(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestNewDecompressRequestBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are not present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"max-size": 1024, "max-ratio": 0},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when max size argument is not valid",
+			args:        map[string]any{"max-size": "big"},
+			expectedErr: errors.New("failed to convert 'max-size' attribute: value is required to be a valid int"),
+		},
+		{
+			name:        "build should fail when max ratio argument is not valid",
+			args:        map[string]any{"max-ratio": "high"},
+			expectedErr: errors.New("failed to convert 'max-ratio' attribute: value is required to be a valid int"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewDecompressRequestBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestDecompressRequestFilter_PreProcess(t *testing.T) {
+	payload := []byte(strings.Repeat("uploaded body ", 100))
+	tests := []struct {
+		expectedErr     error
+		expectedReadErr error
+		headers         http.Header
+		name            string
+		body            []byte
+		expected        []byte
+		expectedHeaders http.Header
+		limits          gateway.DecodeLimits
+	}{
+		{
+			name:            "pre process should decompress body when it is encoded",
+			headers:         http.Header{"Content-Encoding": {"gzip"}, "Content-Type": {"text/plain"}},
+			body:            gzipBody(t, payload),
+			expected:        payload,
+			expectedHeaders: http.Header{"Content-Type": {"text/plain"}},
+		},
+		{
+			name:            "pre process should leave body as is when it is not encoded",
+			headers:         http.Header{"Content-Type": {"text/plain"}},
+			body:            payload,
+			expected:        payload,
+			expectedHeaders: http.Header{"Content-Type": {"text/plain"}},
+		},
+		{
+			name:        "pre process should fail when encoding is not supported",
+			headers:     http.Header{"Content-Encoding": {"zstd"}},
+			body:        payload,
+			expectedErr: gateway.ErrUnsupportedContentEncoding,
+		},
+		{
+			name:        "pre process should fail when body is not validly encoded",
+			headers:     http.Header{"Content-Encoding": {"gzip"}},
+			body:        payload,
+			expectedErr: gateway.ErrContentDecoding,
+		},
+		{
+			name:            "pre process should succeed but body should fail when decompressed body is over the limits",
+			headers:         http.Header{"Content-Encoding": {"gzip"}},
+			body:            gzipBody(t, payload),
+			limits:          gateway.DecodeLimits{MaxBytes: 100},
+			expectedReadErr: gateway.ErrDecodedLimitExceeded,
+			expectedHeaders: http.Header{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(
+				t.Context(), http.MethodPost, "https://example.org/upload", bytes.NewReader(tt.body))
+			req.Header = tt.headers
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			f := filter.NewDecompressRequestFilter(tt.limits)
+
+			err := f.PreProcess(ctx)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected err %v actual %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(tt.expectedHeaders, ctx.Request.Headers) {
+				t.Errorf("expected headers %v actual %v", tt.expectedHeaders, ctx.Request.Headers)
+			}
+			actual, err := io.ReadAll(ctx.Request.BodyReader)
+			if !errors.Is(err, tt.expectedReadErr) {
+				t.Fatalf("expected read err %v actual %v", tt.expectedReadErr, err)
+			}
+			if tt.expected != nil && !bytes.Equal(tt.expected, actual) {
+				t.Errorf("expected body %q actual %q", tt.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrUnknownExpressionVariable is returned when an expression references a variable that does not exist.
+var ErrUnknownExpressionVariable = errors.New("unknown expression variable")
+
+const (
+	expressionPrefix = "${"
+	expressionSuffix = "}"
+)
+
+type expressionResolver func(ctx *gateway.Context) string
+
+// Expression is a filter value resolved for each request. It references request-time variables as
+// '${namespace.name}':
+//   - ${request.method}, ${request.host}, ${request.scheme}, ${request.path} and ${request.query}: the method,
+//     host, scheme, path and raw query of the request.
+//   - ${path.name}: the path variable captured by the route predicates.
+//   - ${query.name}, ${header.name} and ${cookie.name}: the first value of the request query parameter,
+//     header or cookie.
+//   - ${client.ip}: the client IP address.
+//   - ${route.id}: the ID of the route.
+//   - ${attribute.name}: the context attribute, formatted when it is not a string.
+//   - ${env.NAME}: the environment variable, read once when the expression is compiled.
+//   - ${time.rfc3339}, ${time.unix} and ${time.unix-milli}: the current time in UTC.
+//
+// A variable whose value is missing resolves to an empty string. The path variables can also be referenced as
+// '{name}', keeping the reference as it is when the variable is missing. A '${' without a closing brace is
+// kept as it is, and a literal '${' is written as '$${'.
+type Expression struct {
+	literals  []shared.VariableTemplate
+	resolvers []expressionResolver
+}
+
+// CompileExpression compiles the text into an Expression. It returns an error when the text references an
+// unknown variable.
+func CompileExpression(text string) (Expression, error) {
+	var expression Expression
+	start := 0
+	for {
+		open := strings.Index(text[start:], expressionPrefix)
+		if open == -1 {
+			break
+		}
+		open += start
+		if open > start && text[open-1] == '$' {
+			// '$${' is the escape of a literal '${', kept by a nil resolver.
+			expression.literals = append(expression.literals, shared.ParseVariableTemplate(text[start:open-1]))
+			expression.resolvers = append(expression.resolvers, nil)
+			start = open + len(expressionPrefix)
+			continue
+		}
+		end := strings.Index(text[open:], expressionSuffix)
+		if end == -1 {
+			break
+		}
+		end += open
+		resolver, err := compileExpressionVariable(text[open+len(expressionPrefix) : end])
+		if err != nil {
+			return Expression{}, err
+		}
+		expression.literals = append(expression.literals, shared.ParseVariableTemplate(text[start:open]))
+		expression.resolvers = append(expression.resolvers, resolver)
+		start = end + len(expressionSuffix)
+	}
+	expression.literals = append(expression.literals, shared.ParseVariableTemplate(text[start:]))
+	return expression, nil
+}
+
+// LiteralExpression creates an Expression from the text as it is, without compiling its '${...}' variables.
+// The path variables can still be referenced as '{name}'.
+func LiteralExpression(text string) Expression {
+	return Expression{literals: []shared.VariableTemplate{shared.ParseVariableTemplate(text)}}
+}
+
+// Expand returns the value of the expression for the request.
+func (e Expression) Expand(ctx *gateway.Context) string {
+	return e.ExpandEscaped(ctx, nil)
+}
+
+// ExpandEscaped is Expand with the values of the variables escaped by the given func, if not nil.
+func (e Expression) ExpandEscaped(ctx *gateway.Context, escape func(string) string) string {
+	pathVariables := gateway.PathVariables(ctx)
+	if len(e.resolvers) == 0 {
+		return e.literals[0].ExpandEscaped(pathVariables, escape)
+	}
+	var text strings.Builder
+	for i, resolve := range e.resolvers {
+		text.WriteString(e.literals[i].ExpandEscaped(pathVariables, escape))
+		if resolve == nil {
+			text.WriteString(expressionPrefix)
+			continue
+		}
+		value := resolve(ctx)
+		if escape != nil {
+			value = escape(value)
+		}
+		text.WriteString(value)
+	}
+	text.WriteString(e.literals[len(e.resolvers)].ExpandEscaped(pathVariables, escape))
+	return text.String()
+}
+
+// compileExpressionVariable returns the resolver of the variable, like 'header.X-Request-Id'.
+func compileExpressionVariable(variable string) (expressionResolver, error) {
+	namespace, name, _ := strings.Cut(variable, ".")
+	var resolver expressionResolver
+	switch namespace {
+	case "request":
+		resolver = requestResolver(name)
+	case "client":
+		if name == "ip" {
+			resolver = func(ctx *gateway.Context) string { return ctx.Request.RemoteAddr }
+		}
+	case "route":
+		if name == "id" {
+			resolver = func(ctx *gateway.Context) string { return ctx.Route.ID }
+		}
+	case "time":
+		resolver = timeResolver(name)
+	default:
+		if name != "" {
+			resolver = namedResolver(namespace, name)
+		}
+	}
+	if resolver == nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownExpressionVariable, variable)
+	}
+	return resolver, nil
+}
+
+func requestResolver(name string) expressionResolver {
+	switch name {
+	case "method":
+		return func(ctx *gateway.Context) string { return ctx.Request.Method }
+	case "host":
+		return func(ctx *gateway.Context) string { return ctx.Request.Host }
+	case "scheme":
+		return func(ctx *gateway.Context) string { return ctx.Request.Scheme }
+	case "path":
+		return func(ctx *gateway.Context) string { return ctx.Request.URL.Path }
+	case "query":
+		return func(ctx *gateway.Context) string { return ctx.Request.URL.RawQuery }
+	default:
+		return nil
+	}
+}
+
+func timeResolver(name string) expressionResolver {
+	switch name {
+	case "rfc3339":
+		return func(_ *gateway.Context) string { return time.Now().UTC().Format(time.RFC3339) }
+	case "unix":
+		return func(_ *gateway.Context) string { return strconv.FormatInt(time.Now().Unix(), 10) }
+	case "unix-milli":
+		return func(_ *gateway.Context) string { return strconv.FormatInt(time.Now().UnixMilli(), 10) }
+	default:
+		return nil
+	}
+}
+
+// namedResolver returns the resolver of the variables whose namespace holds any name.
+func namedResolver(namespace, name string) expressionResolver {
+	switch namespace {
+	case "path":
+		return func(ctx *gateway.Context) string { return gateway.PathVariables(ctx)[name] }
+	case "query":
+		return func(ctx *gateway.Context) string { return ctx.Request.URL.Query().Get(name) }
+	case "header":
+		return func(ctx *gateway.Context) string { return ctx.Request.Headers.Get(name) }
+	case "cookie":
+		return func(ctx *gateway.Context) string { return requestCookie(ctx.Request.Headers, name) }
+	case "attribute":
+		return func(ctx *gateway.Context) string {
+			value, _ := attributeString(ctx, name)
+			return value
+		}
+	case "env":
+		value := os.Getenv(name)
+		return func(_ *gateway.Context) string { return value }
+	default:
+		return nil
+	}
+}
+
+// requestCookie returns the value of the first request cookie with the name.
+func requestCookie(headers http.Header, name string) string {
+	for _, line := range headers.Values("Cookie") {
+		cookies, err := http.ParseCookie(line)
+		if err != nil {
+			continue
+		}
+		for _, cookie := range cookies {
+			if cookie.Name == name {
+				return cookie.Value
+			}
+		}
+	}
+	return ""
+}
+
+// attributeString returns the context attribute, formatted when it is not a string, and false when it is
+// missing.
+func attributeString(ctx *gateway.Context, name string) (string, bool) {
+	value, ok := ctx.Attributes[name]
+	if !ok {
+		return "", false
+	}
+	if str, isString := value.(string); isString {
+		return str, true
+	}
+	return fmt.Sprint(value), true
+}
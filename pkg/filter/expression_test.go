@@ -0,0 +1,203 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestCompileExpression(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		text        string
+	}{
+		{
+			name:        "compile should succeed when text has no variables",
+			text:        "plain {id} text",
+			expectedErr: nil,
+		},
+		{
+			name:        "compile should succeed when variables are known",
+			text:        "${request.method} ${header.X-Id} ${time.unix}",
+			expectedErr: nil,
+		},
+		{
+			name:        "compile should succeed when reference is not closed",
+			text:        "${request.method",
+			expectedErr: nil,
+		},
+		{
+			name:        "compile should succeed when unknown variable is escaped",
+			text:        "$${foo}",
+			expectedErr: nil,
+		},
+		{
+			name:        "compile should fail when namespace is unknown",
+			text:        "a ${requests.method}",
+			expectedErr: errors.New("unknown expression variable: requests.method"),
+		},
+		{
+			name:        "compile should fail when name is unknown",
+			text:        "${client.port}",
+			expectedErr: errors.New("unknown expression variable: client.port"),
+		},
+		{
+			name:        "compile should fail when name is missing",
+			text:        "${header}",
+			expectedErr: errors.New("unknown expression variable: header"),
+		},
+		{
+			name:        "compile should fail when time format is unknown",
+			text:        "${time.iso}",
+			expectedErr: errors.New("unknown expression variable: time.iso"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := filter.CompileExpression(tt.text)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestExpression_Expand(t *testing.T) {
+	t.Setenv("GATEWAY_EXPRESSION_TEST", "from-env")
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "expand should resolve request variables",
+			text:     "${request.method} ${request.scheme}://${request.host}${request.path}?${request.query}",
+			expected: "POST http://public.example.org/api/users/42?tab=profile&tab=orders",
+		},
+		{
+			name:     "expand should resolve path variables",
+			text:     "/users/${path.id}/{id}/${path.missing}/{missing}",
+			expected: "/users/42/42//{missing}",
+		},
+		{
+			name:     "expand should resolve first query parameter",
+			text:     "${query.tab}",
+			expected: "profile",
+		},
+		{
+			name:     "expand should resolve header in any case",
+			text:     "${header.x-request-id}",
+			expected: "req-1",
+		},
+		{
+			name:     "expand should resolve cookies",
+			text:     "${cookie.session}|${cookie.missing}",
+			expected: "abc|",
+		},
+		{
+			name:     "expand should resolve client ip and route id",
+			text:     "${client.ip} ${route.id}",
+			expected: "203.0.113.7 users",
+		},
+		{
+			name:     "expand should resolve attributes",
+			text:     "${attribute.tenant}-${attribute.weight}-${attribute.missing}",
+			expected: "acme-3-",
+		},
+		{
+			name:     "expand should resolve environment variables",
+			text:     "${env.GATEWAY_EXPRESSION_TEST}",
+			expected: "from-env",
+		},
+		{
+			name:     "expand should keep unclosed reference",
+			text:     "${request.method",
+			expected: "${request.method",
+		},
+		{
+			name:     "expand should keep escaped reference as literal",
+			text:     "$${request.method} $${id} $${foo} ${request.method}",
+			expected: "${request.method} ${id} ${foo} POST",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newExpressionContext(t)
+			expression, err := filter.CompileExpression(tt.text)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if actual := expression.Expand(ctx); actual != tt.expected {
+				t.Errorf("expected %s actual %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestLiteralExpression_Expand(t *testing.T) {
+	ctx := newExpressionContext(t)
+
+	actual := filter.LiteralExpression("${request.method} {id} ${unknown.name}").Expand(ctx)
+
+	if expected := "${request.method} 42 ${unknown.name}"; actual != expected {
+		t.Errorf("expected %s actual %s", expected, actual)
+	}
+}
+
+func TestExpression_ExpandTime(t *testing.T) {
+	ctx := newExpressionContext(t)
+	before := time.Now()
+	expression, _ := filter.CompileExpression("${time.rfc3339}|${time.unix}|${time.unix-milli}")
+
+	parts := strings.Split(expression.Expand(ctx), "|")
+	if len(parts) != 3 {
+		t.Fatalf("expected three times actual %v", parts)
+	}
+	rfc3339, unix, unixMilli := parts[0], parts[1], parts[2]
+
+	if parsed, err := time.Parse(time.RFC3339, rfc3339); err != nil || parsed.Before(before.Truncate(time.Second)) {
+		t.Errorf("expected current rfc3339 time actual %s", rfc3339)
+	}
+	if seconds, err := strconv.ParseInt(unix, 10, 64); err != nil || seconds < before.Unix() {
+		t.Errorf("expected current unix time actual %s", unix)
+	}
+	if millis, err := strconv.ParseInt(unixMilli, 10, 64); err != nil || millis < before.UnixMilli() {
+		t.Errorf("expected current unix milli time actual %s", unixMilli)
+	}
+}
+
+func TestExpression_ExpandEscaped(t *testing.T) {
+	ctx := newExpressionContext(t)
+	expression, _ := filter.CompileExpression("/${header.X-Path}/{id}/$${id}")
+
+	actual := expression.ExpandEscaped(ctx, func(value string) string { return "<" + value + ">" })
+
+	if actual != "/<a b>/<42>/${id}" {
+		t.Errorf("expected escaped values actual %s", actual)
+	}
+}
+
+func newExpressionContext(t *testing.T) *gateway.Context {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost,
+		"http://public.example.org/api/users/42?tab=profile&tab=orders", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	req.Header.Set("X-Request-Id", "req-1")
+	req.Header.Set("X-Path", "a b")
+	req.Header.Add("Cookie", "theme=dark; session=abc")
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{ID: "users"}, gateway.NewGatewayRequest(req))
+	ctx.Attributes[gateway.PathVariablesAttr] = map[string]string{"id": "42"}
+	ctx.Attributes["tenant"] = "acme"
+	ctx.Attributes["weight"] = 3
+	return ctx
+}
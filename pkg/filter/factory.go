@@ -25,7 +25,8 @@ func NewFactory(registry gateway.FilterBuilderRegistry) *Factory {
 // Build builds a filter from the given name and args.
 //
 // If the filter builder is not found, the factory will return an error.
-// If the filter builder is found but the args are invalid, the factory will return an error.
+// If the filter builder is found but the args are invalid, the factory will return an error wrapping the
+// error of the builder.
 // If the filter builder is found and the args are valid, the factory will return a filter.
 //
 // The args are expected to be a map of strings to any.
@@ -35,7 +36,7 @@ func (f *Factory) Build(name string, args map[string]any) (gateway.Filter, error
 	if f.registry[name] != nil {
 		fi, err := f.registry[name].Build(args)
 		if err != nil {
-			return nil, fmt.Errorf("%w: filter %s and args %v", ErrFilterBuilder, name, args)
+			return nil, fmt.Errorf("%w: filter %s and args %v: %w", ErrFilterBuilder, name, args, err)
 		}
 		return fi, nil
 	}
@@ -34,7 +34,7 @@ func TestFactory_Build(t *testing.T) {
 				"name": "X-Test",
 			},
 			expected:    nil,
-			expectedErr: errors.New("filter builder failed: filter AddRequestHeader and args map[name:X-Test]"),
+			expectedErr: errors.New("filter builder failed: filter AddRequestHeader and args map[name:X-Test]: failed to convert 'value' attribute: value is required"),
 		},
 		{
 			name:        "build should return error when builder is not registered",
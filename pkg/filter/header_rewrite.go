@@ -0,0 +1,57 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+)
+
+// headerValueRewrite replaces the matches of a regexp in the values of a header.
+type headerValueRewrite struct {
+	pattern     *regexp.Regexp
+	name        string
+	replacement string
+}
+
+// newHeaderValueRewrite compiles the regexp of the header value rewrite. The replacement can reference the
+// groups of the regexp as '$name' or '$\name', like the RewritePath filter.
+func newHeaderValueRewrite(name, regexpStr, replacement string) (headerValueRewrite, error) {
+	pattern, err := regexp.Compile(regexpStr)
+	if err != nil {
+		return headerValueRewrite{}, err //nolint:wrapcheck // the filter constructors wrap it
+	}
+	return headerValueRewrite{
+		pattern:     pattern,
+		name:        name,
+		replacement: strings.ReplaceAll(replacement, "$\\", "$"),
+	}, nil
+}
+
+// apply rewrites every value of the header. A header that is missing is left missing.
+func (r headerValueRewrite) apply(headers http.Header) {
+	values := headers.Values(r.name)
+	if len(values) == 0 {
+		return
+	}
+	rewritten := make([]string, 0, len(values))
+	for _, value := range values {
+		rewritten = append(rewritten, r.pattern.ReplaceAllString(value, r.replacement))
+	}
+	headers[http.CanonicalHeaderKey(r.name)] = rewritten
+}
+
+// convertHeaderRewriteArgs converts the name, regexp and replacement args of a header rewrite filter.
+func convertHeaderRewriteArgs(args map[string]any) (string, string, string, error) {
+	values := make([]string, 0, 3) //nolint:mnd // the three args
+	for _, arg := range []string{"name", "regexp", "replacement"} {
+		value, err := shared.ConvertToString(args[arg])
+		if err != nil {
+			return "", "", "", fmt.Errorf("failed to convert '%s' attribute: %w", arg, err)
+		}
+		values = append(values, value)
+	}
+	return values[0], values[1], values[2], nil
+}
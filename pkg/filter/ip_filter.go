@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrIPForbidden is returned when the client address is not allowed by the ip filter.
+var ErrIPForbidden = errors.New("ip address forbidden")
+
+// ErrInvalidIPFilter is returned when the ip filter has neither an allow nor a deny list.
+var ErrInvalidIPFilter = errors.New("invalid ip filter")
+
+// IPFilterFilterName is the name of the ip filter.
+const IPFilterFilterName = "IPFilter"
+
+// IPFilter is a filter that allows or denies the requests by client address, IPv4 or IPv6.
+//
+// The deny list wins over the allow list. When the allow list is not empty, only the addresses
+// in it are allowed; otherwise every address not denied is allowed.
+//
+// By default, the client address is gateway.Request.RemoteAddr, which trusts the X-Forwarded-For
+// and X-Real-Ip headers. When the gateway is not behind a trusted proxy, the peer of the connection
+// must be used instead, since the client chooses those headers.
+type IPFilter struct {
+	allow   *shared.CIDRSet
+	deny    *shared.CIDRSet
+	usePeer bool
+}
+
+// NewIPFilter creates a new IPFilter. The allow and deny lists are CIDRs or single IP addresses,
+// and at least one of them must not be empty.
+func NewIPFilter(allow, deny []string, usePeer bool) (*IPFilter, error) {
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, fmt.Errorf("%w: allow or deny list is required", ErrInvalidIPFilter)
+	}
+	allowSet, err := shared.ParseCIDRSet(allow)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ip filter: allow list: %w", err)
+	}
+	denySet, err := shared.ParseCIDRSet(deny)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build ip filter: deny list: %w", err)
+	}
+	return &IPFilter{
+		allow:   allowSet,
+		deny:    denySet,
+		usePeer: usePeer,
+	}, nil
+}
+
+// NewIPFilterBuilder creates a new IPFilter builder.
+//
+// The args are expected to contain the following keys:
+// - allow: optional, the list of CIDRs or IP addresses allowed.
+// - deny: optional, the list of CIDRs or IP addresses denied.
+// - use-peer: optional, filter the connection peer instead of the resolved client address (default false).
+func NewIPFilterBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		allow, err := convertOptionalStringSlice(args, "allow")
+		if err != nil {
+			return nil, err
+		}
+		deny, err := convertOptionalStringSlice(args, "deny")
+		if err != nil {
+			return nil, err
+		}
+		usePeer, err := convertOptionalBool(args, "use-peer")
+		if err != nil {
+			return nil, err
+		}
+		return NewIPFilter(allow, deny, usePeer)
+	}
+}
+
+// PreProcess checks the client address against the allow and deny lists.
+// If the address is not allowed, the filter returns an ErrIPForbidden error.
+// If the address cannot be parsed, it is only allowed when there is no allow list.
+func (f *IPFilter) PreProcess(ctx *gateway.Context) error {
+	remoteAddr := ctx.Request.RemoteAddr
+	if f.usePeer {
+		remoteAddr = ctx.Request.PeerAddr
+	}
+	addr, ok := shared.ParseRemoteAddr(remoteAddr)
+	switch {
+	case !ok && f.allow.Len() != 0:
+		return fmt.Errorf("%w: unknown address %q", ErrIPForbidden, remoteAddr)
+	case !ok:
+		return nil
+	case f.deny.Contains(addr):
+		return fmt.Errorf("%w: %s is denied", ErrIPForbidden, addr)
+	case f.allow.Len() != 0 && !f.allow.Contains(addr):
+		return fmt.Errorf("%w: %s is not allowed", ErrIPForbidden, addr)
+	default:
+		return nil
+	}
+}
+
+// PostProcess does nothing.
+func (f *IPFilter) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *IPFilter) Name() string {
+	return IPFilterFilterName
+}
+
+// convertOptionalStringSlice converts the named arg to a string slice, returning nil when it is not present.
+func convertOptionalStringSlice(args map[string]any, name string) ([]string, error) {
+	if args[name] == nil {
+		return nil, nil
+	}
+	value, err := shared.ConvertToStringSlice(args[name])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
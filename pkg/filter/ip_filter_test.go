@@ -0,0 +1,172 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewIPFilterBuilder(t *testing.T) {
+	tests := []struct {
+		args        map[string]any
+		expectedErr error
+		name        string
+	}{
+		{
+			name: "build should succeed when allow and deny args are present and are valid",
+			args: map[string]any{
+				"allow":    []any{"10.0.0.0/8", "2001:db8::/32"},
+				"deny":     []any{"10.6.6.0/24"},
+				"use-peer": true,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when only deny is present",
+			args:        map[string]any{"deny": []any{"203.0.113.7"}},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should return error when neither allow nor deny is present",
+			args:        map[string]any{},
+			expectedErr: errors.New("invalid ip filter: allow or deny list is required"),
+		},
+		{
+			name:        "build should return error when allow is not valid",
+			args:        map[string]any{"allow": "10.0.0.0/8"},
+			expectedErr: errors.New("failed to convert 'allow' attribute: value is required to be a valid slice"),
+		},
+		{
+			name:        "build should return error when use peer is not valid",
+			args:        map[string]any{"deny": []any{"10.0.0.0/8"}, "use-peer": "maybe"},
+			expectedErr: errors.New("failed to convert 'use-peer' attribute: value is required to be a valid bool"),
+		},
+		{
+			name:        "build should return error when a denied network is not valid",
+			args:        map[string]any{"deny": []any{"10.0.0.0/8", "10.0.0.0.0"}},
+			expectedErr: errors.New("failed to build ip filter: deny list: invalid CIDR: 10.0.0.0.0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewIPFilterBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestIPFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		remoteAddr  string
+		peerAddr    string
+		allow       []string
+		deny        []string
+		usePeer     bool
+	}{
+		{
+			name:        "pre process should allow address in allow list",
+			allow:       []string{"10.0.0.0/8"},
+			remoteAddr:  "10.1.2.3",
+			expectedErr: nil,
+		},
+		{
+			name:        "pre process should forbid address not in allow list",
+			allow:       []string{"10.0.0.0/8"},
+			remoteAddr:  "11.1.2.3",
+			expectedErr: errors.New("ip address forbidden: 11.1.2.3 is not allowed"),
+		},
+		{
+			name:        "pre process should forbid address in deny list even when allowed",
+			allow:       []string{"10.0.0.0/8"},
+			deny:        []string{"10.6.6.0/24"},
+			remoteAddr:  "10.6.6.6",
+			expectedErr: errors.New("ip address forbidden: 10.6.6.6 is denied"),
+		},
+		{
+			name:        "pre process should allow address not in deny list when there is no allow list",
+			deny:        []string{"2001:db8::/32"},
+			remoteAddr:  "2001:db9::1",
+			expectedErr: nil,
+		},
+		{
+			name:        "pre process should forbid ipv6 address in deny list",
+			deny:        []string{"2001:db8::/32"},
+			remoteAddr:  "2001:db8::1",
+			expectedErr: errors.New("ip address forbidden: 2001:db8::1 is denied"),
+		},
+		{
+			name:        "pre process should check peer address when use peer is enabled",
+			deny:        []string{"10.0.0.0/8"},
+			remoteAddr:  "192.168.0.1",
+			peerAddr:    "10.1.2.3:4321",
+			usePeer:     true,
+			expectedErr: errors.New("ip address forbidden: 10.1.2.3 is denied"),
+		},
+		{
+			name:        "pre process should forbid unknown address when there is an allow list",
+			allow:       []string{"10.0.0.0/8"},
+			remoteAddr:  "unknown",
+			expectedErr: errors.New("ip address forbidden: unknown address \"unknown\""),
+		},
+		{
+			name:        "pre process should allow unknown address when there is no allow list",
+			deny:        []string{"10.0.0.0/8"},
+			remoteAddr:  "unknown",
+			expectedErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := filter.NewIPFilter(tt.allow, tt.deny, tt.usePeer)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			ctx := &gateway.Context{
+				Request: &gateway.Request{RemoteAddr: tt.remoteAddr, PeerAddr: tt.peerAddr},
+			}
+
+			err = f.PreProcess(ctx)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err != nil && !errors.Is(err, filter.ErrIPForbidden) {
+				t.Errorf("expected err %s to be ErrIPForbidden", err)
+			}
+		})
+	}
+}
+
+func TestIPFilter_PostProcess(t *testing.T) {
+	f, err := filter.NewIPFilter(nil, []string{"10.0.0.0/8"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err = f.PostProcess(&gateway.Context{}); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestIPFilter_Name(t *testing.T) {
+	expected := "IPFilter"
+	f, err := filter.NewIPFilter(nil, []string{"10.0.0.0/8"}, false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if name := f.Name(); name != expected {
+		t.Errorf("expected name %s actual %s", expected, name)
+	}
+}
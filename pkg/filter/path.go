@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrInvalidPathFilter is returned when a path filter is built with an invalid path or number of parts.
+var ErrInvalidPathFilter = errors.New("invalid path filter")
+
+// GatewayOriginalRequestAttr is the name of the attribute that contains the original request URL.
+const GatewayOriginalRequestAttr = "GATEWAY_ORIGINAL_REQUEST_URL"
+
+// recordOriginalURL stores the request URL in the context as the GatewayOriginalRequestAttr attribute, unless
+// a previous filter already stored it.
+func recordOriginalURL(ctx *gateway.Context) {
+	if _, ok := ctx.Attributes[GatewayOriginalRequestAttr]; !ok {
+		ctx.Attributes[GatewayOriginalRequestAttr] = ctx.Request.URL
+	}
+}
+
+// setEscapedPath sets the path of the request URL from its escaped form, keeping the escaped form in RawPath
+// when it differs from the default encoding of the path, like an encoded slash does.
+//
+// The URL is shared with the inbound request: it is copied rather than modified.
+func setEscapedPath(ctx *gateway.Context, escapedPath string) error {
+	newURL := *ctx.Request.URL
+	if err := setURLEscapedPath(&newURL, escapedPath); err != nil {
+		return err
+	}
+	ctx.Request.URL = &newURL
+	return nil
+}
+
+// setURLEscapedPath sets the path of the URL from its escaped form, like setEscapedPath.
+func setURLEscapedPath(u *url.URL, escapedPath string) error {
+	path, err := url.PathUnescape(escapedPath)
+	if err != nil {
+		return fmt.Errorf("failed to set path: %w", err)
+	}
+	u.Path = path
+	u.RawPath = ""
+	if u.EscapedPath() != escapedPath {
+		u.RawPath = escapedPath
+	}
+	return nil
+}
+
+// originalURL returns the request URL as the client sent it, before the path filters changed it.
+func originalURL(ctx *gateway.Context) *url.URL {
+	if original, ok := ctx.Attributes[GatewayOriginalRequestAttr].(*url.URL); ok {
+		return original
+	}
+	return ctx.Request.URL
+}
+
+// pathPrefixes returns the escaped path prefix the path filters put in place of the prefix of the path the
+// client requested, and that public prefix. The trailing segments both paths share are left out: when
+// '/api/users/42' is requested and '/users/42' is sent to the backend, the prefixes are the empty string and '/api'.
+func pathPrefixes(ctx *gateway.Context) (string, string) {
+	backend := ctx.Request.URL.EscapedPath()
+	public := originalURL(ctx).EscapedPath()
+	for backend != "" && public != "" {
+		backendIndex := strings.LastIndex(backend, "/")
+		publicIndex := strings.LastIndex(public, "/")
+		if backendIndex == -1 || publicIndex == -1 || backend[backendIndex:] != public[publicIndex:] {
+			break
+		}
+		backend, public = backend[:backendIndex], public[:publicIndex]
+	}
+	return backend, public
+}
+
+// mapPathPrefix replaces the from prefix of the escaped path with the to prefix. It returns false when the
+// prefixes are the same or the path is not under the from prefix.
+func mapPathPrefix(path, from, to string) (string, bool) {
+	if from == to {
+		return path, false
+	}
+	rest, ok := strings.CutPrefix(path, from)
+	if !ok || (rest != "" && !strings.HasPrefix(rest, "/")) {
+		return path, false
+	}
+	return to + rest, true
+}
+
+// validateEscapedPath returns an error when the path is not a valid escaped path.
+func validateEscapedPath(path string) error {
+	if _, err := url.PathUnescape(path); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPathFilter, err)
+	}
+	return nil
+}
+
+// escapePathValue escapes the value for an escaped path, keeping its slashes.
+func escapePathValue(value string) string {
+	if !strings.Contains(value, "/") {
+		return url.PathEscape(value)
+	}
+	segments := strings.Split(value, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,31 @@
+package filter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestPathFilters_KeepFirstOriginalURL(t *testing.T) {
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.org/a/b/c", nil)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+	stripPrefix, _ := filter.NewStripPrefixFilter(1)
+	prefixPath, _ := filter.NewPrefixPathFilter("/api")
+	rewritePath, _ := filter.NewRewritePathFilter("/api/(?<rest>.*)", "/v1/$\\{rest}")
+	setPath, _ := filter.NewSetPathFilter("/final")
+	filters := gateway.Filters{stripPrefix, prefixPath, rewritePath, setPath}
+
+	for _, f := range filters {
+		if err := f.PreProcess(ctx); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if original := ctx.Attributes[filter.GatewayOriginalRequestAttr]; original != req.URL {
+			t.Errorf("expected original url %s after %s actual %v", req.URL, f.Name(), original)
+		}
+	}
+	if ctx.Request.URL.Path != "/final" || req.URL.Path != "/a/b/c" {
+		t.Errorf("expected path /final and inbound path /a/b/c actual %s %s", ctx.Request.URL.Path, req.URL.Path)
+	}
+}
@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// PrefixPathFilterName is the name of the filter.
+const PrefixPathFilterName = "PrefixPath"
+
+// PrefixPath is a filter that adds a prefix to the request path.
+//
+// For example, with the prefix '/api', the request path '/users/42' is set to '/api/users/42'. The prefix is
+// an escaped path, like '/files%2Fv1', so it can contain encoded characters.
+type PrefixPath struct {
+	prefix string
+}
+
+// NewPrefixPathFilter creates a new PrefixPathFilter.
+func NewPrefixPathFilter(prefix string) (*PrefixPath, error) {
+	if err := validateEscapedPath(prefix); err != nil {
+		return nil, fmt.Errorf("failed to build prefix path filter: %w", err)
+	}
+	prefix = strings.Trim(prefix, "/")
+	if prefix != "" {
+		prefix = "/" + prefix
+	}
+	return &PrefixPath{
+		prefix: prefix,
+	}, nil
+}
+
+// NewPrefixPathBuilder creates a new PrefixPathBuilder.
+func NewPrefixPathBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		prefix, err := shared.ConvertToString(args["prefix"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'prefix' attribute: %w", err)
+		}
+		return NewPrefixPathFilter(prefix)
+	}
+}
+
+// PreProcess adds the prefix to the request path.
+// The original request URL is stored in the context as an attribute with the name GatewayOriginalRequestAttr,
+// unless a previous filter already stored it.
+func (f *PrefixPath) PreProcess(ctx *gateway.Context) error {
+	recordOriginalURL(ctx)
+	if f.prefix == "" {
+		return nil
+	}
+	return setEscapedPath(ctx, f.prefix+ctx.Request.URL.EscapedPath())
+}
+
+// PostProcess does nothing.
+func (f *PrefixPath) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *PrefixPath) Name() string {
+	return PrefixPathFilterName
+}
@@ -0,0 +1,127 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewPrefixPathBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"prefix": "/api"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when prefix argument is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'prefix' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when prefix is not a valid escaped path",
+			args:        map[string]any{"prefix": "/api%"},
+			expectedErr: errors.New("failed to build prefix path filter: invalid path filter: invalid URL escape \"%\""),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewPrefixPathBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestPrefixPathFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		prefix      string
+		target      string
+		expectedURL string
+		expectedRaw string
+	}{
+		{
+			name:        "pre process should add prefix",
+			prefix:      "/api",
+			target:      "http://example.org/users/42?verbose=true",
+			expectedURL: "http://example.org/api/users/42?verbose=true",
+		},
+		{
+			name:        "pre process should add prefix with trailing slash once",
+			prefix:      "api/v1/",
+			target:      "http://example.org/users",
+			expectedURL: "http://example.org/api/v1/users",
+		},
+		{
+			name:        "pre process should keep path when prefix is root",
+			prefix:      "/",
+			target:      "http://example.org/users",
+			expectedURL: "http://example.org/users",
+		},
+		{
+			name:        "pre process should keep encoded slash of path",
+			prefix:      "/api",
+			target:      "http://example.org/files/a%2Fb",
+			expectedURL: "http://example.org/api/files/a%2Fb",
+			expectedRaw: "/api/files/a%2Fb",
+		},
+		{
+			name:        "pre process should keep encoded slash of prefix",
+			prefix:      "/api%2Fv1",
+			target:      "http://example.org/users",
+			expectedURL: "http://example.org/api%2Fv1/users",
+			expectedRaw: "/api%2Fv1/users",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.target, nil)
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			f, _ := filter.NewPrefixPathFilter(tt.prefix)
+
+			if err := f.PreProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Request.URL.String() != tt.expectedURL || ctx.Request.URL.RawPath != tt.expectedRaw {
+				t.Errorf("expected %s %s actual %s %s", tt.expectedURL, tt.expectedRaw,
+					ctx.Request.URL, ctx.Request.URL.RawPath)
+			}
+			if original, _ := ctx.Attributes[filter.GatewayOriginalRequestAttr].(*url.URL); original != req.URL {
+				t.Errorf("expected original url %s actual %s", req.URL, original)
+			}
+		})
+	}
+}
+
+func TestPrefixPathFilter_PostProcess(t *testing.T) {
+	f, _ := filter.NewPrefixPathFilter("/api")
+	if err := f.PostProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestPrefixPathFilter_Name(t *testing.T) {
+	expected := "PrefixPath"
+
+	f, _ := filter.NewPrefixPathFilter("/api")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
@@ -0,0 +1,237 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+// ErrLoadShed is returned when a request is shed by the admission controller.
+var ErrLoadShed = errors.New("request shed under load")
+
+// PriorityFilterName is the name of the priority filter.
+const PriorityFilterName = "Priority"
+
+// PriorityAttr is the attribute holding the priority class (concurrency.Priority) assigned to the request.
+const PriorityAttr = "GATEWAY_PRIORITY"
+
+// RetryAfterAttr is the attribute holding the delay (time.Duration) suggested to a rejected client.
+const RetryAfterAttr = "GATEWAY_RETRY_AFTER"
+
+// PriorityRule assigns a priority class to the requests matching all its predicates.
+type PriorityRule struct {
+	Predicates gateway.Predicates
+	Class      concurrency.Priority
+}
+
+// Priority is a filter that assigns a priority class to each request and asks the
+// admission controller whether it is admitted. Under load, the controller sheds the
+// lowest classes first and the filter returns an ErrLoadShed error.
+//
+// The class is taken from the first source that matches:
+// 1. The route ID, from the configured route classes.
+// 2. The first rule whose predicates match the request.
+// 3. The configured header, holding the class name. Clients cannot claim the critical class:
+// a header asking for it is treated as high.
+// 4. The default class.
+type Priority struct {
+	controller   *concurrency.AdmissionController
+	routes       map[string]concurrency.Priority
+	header       string
+	rules        []PriorityRule
+	defaultClass concurrency.Priority
+}
+
+// NewPriorityFilter creates a new Priority filter.
+func NewPriorityFilter(
+	controller *concurrency.AdmissionController,
+	defaultClass concurrency.Priority,
+	header string,
+	routes map[string]concurrency.Priority,
+	rules []PriorityRule) *Priority {
+	return &Priority{
+		controller:   controller,
+		defaultClass: defaultClass,
+		header:       header,
+		routes:       routes,
+		rules:        rules,
+	}
+}
+
+// NewPriorityBuilder creates a new Priority builder using the default admission controller.
+//
+// All the args are optional:
+// - default: the class of the requests no other source classifies (default normal).
+// - header: the request header holding the class name.
+// - routes: a map of route IDs to classes.
+// - rules: a list of rules, each one with a class and a list of predicates with name and args.
+//
+// The classes are low, normal, high and critical. Critical requests are never shed.
+func NewPriorityBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		defaultClass := concurrency.PriorityNormal
+		if args["default"] != nil {
+			className, err := shared.ConvertToString(args["default"])
+			if err != nil {
+				return nil, fmt.Errorf("failed to convert 'default' attribute: %w", err)
+			}
+			if defaultClass, err = concurrency.ParsePriority(className); err != nil {
+				return nil, fmt.Errorf("failed to convert 'default' attribute: %w", err)
+			}
+		}
+		var header string
+		if args["header"] != nil {
+			var err error
+			if header, err = shared.ConvertToString(args["header"]); err != nil {
+				return nil, fmt.Errorf("failed to convert 'header' attribute: %w", err)
+			}
+		}
+		routes, err := buildPriorityRoutes(args["routes"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'routes' attribute: %w", err)
+		}
+		rules, err := buildPriorityRules(args["rules"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'rules' attribute: %w", err)
+		}
+		return NewPriorityFilter(concurrency.DefaultAdmissionController, defaultClass, header, routes, rules), nil
+	}
+}
+
+func buildPriorityRoutes(arg any) (map[string]concurrency.Priority, error) {
+	if arg == nil {
+		return nil, nil //nolint:nilnil
+	}
+	routesArg, err := shared.ConvertToMap(arg)
+	if err != nil {
+		return nil, err
+	}
+	routes := make(map[string]concurrency.Priority, len(routesArg))
+	for routeID, classArg := range routesArg {
+		className, err := shared.ConvertToString(classArg)
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", routeID, err)
+		}
+		if routes[routeID], err = concurrency.ParsePriority(className); err != nil {
+			return nil, fmt.Errorf("route %s: %w", routeID, err)
+		}
+	}
+	return routes, nil
+}
+
+func buildPriorityRules(arg any) ([]PriorityRule, error) {
+	if arg == nil {
+		return nil, nil
+	}
+	rulesArg, ok := arg.([]any)
+	if !ok {
+		return nil, shared.ErrRequiredSliceValue
+	}
+	factory := predicate.NewFactory(predicate.BuilderRegistry)
+	rules := make([]PriorityRule, 0, len(rulesArg))
+	for idx, ruleArg := range rulesArg {
+		rule, err := buildPriorityRule(factory, ruleArg)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: %w", idx, err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func buildPriorityRule(factory *predicate.Factory, arg any) (PriorityRule, error) {
+	ruleArg, err := shared.ConvertToMap(arg)
+	if err != nil {
+		return PriorityRule{}, err
+	}
+	className, err := shared.ConvertToString(ruleArg["class"])
+	if err != nil {
+		return PriorityRule{}, fmt.Errorf("failed to convert 'class' attribute: %w", err)
+	}
+	class, err := concurrency.ParsePriority(className)
+	if err != nil {
+		return PriorityRule{}, fmt.Errorf("failed to convert 'class' attribute: %w", err)
+	}
+	predicatesArg, ok := ruleArg["predicates"].([]any)
+	if !ok || len(predicatesArg) == 0 {
+		return PriorityRule{}, fmt.Errorf("failed to convert 'predicates' attribute: %w", shared.ErrRequiredSliceValue)
+	}
+	predicates := make(gateway.Predicates, 0, len(predicatesArg))
+	for _, predicateArg := range predicatesArg {
+		item, err := shared.ConvertToMap(predicateArg)
+		if err != nil {
+			return PriorityRule{}, fmt.Errorf("failed to convert 'predicates' attribute: %w", err)
+		}
+		name, err := shared.ConvertToString(item["name"])
+		if err != nil {
+			return PriorityRule{}, fmt.Errorf("failed to convert predicate 'name' attribute: %w", err)
+		}
+		var predicateArgs map[string]any
+		if item["args"] != nil {
+			if predicateArgs, err = shared.ConvertToMap(item["args"]); err != nil {
+				return PriorityRule{}, fmt.Errorf("failed to convert predicate 'args' attribute: %w", err)
+			}
+		}
+		built, err := factory.Build(name, predicateArgs)
+		if err != nil {
+			return PriorityRule{}, err
+		}
+		predicates = append(predicates, built)
+	}
+	return PriorityRule{Predicates: predicates, Class: class}, nil
+}
+
+// PreProcess classifies the request and asks the admission controller for admission.
+// If the request is shed, the filter returns an ErrLoadShed error and sets the RetryAfterAttr
+// attribute.
+//
+// The in-flight requests are counted by the gateway handler, for every route.
+func (f *Priority) PreProcess(ctx *gateway.Context) error {
+	class := f.classify(ctx)
+	ctx.Attributes[PriorityAttr] = class
+	if !f.controller.Admit(class) {
+		ctx.Attributes[RetryAfterAttr] = f.controller.RetryAfter()
+		return fmt.Errorf("%w: class %s", ErrLoadShed, class)
+	}
+	return nil
+}
+
+// PostProcess reports to the admission controller the time the request spent inside the
+// gateway, from the moment the gateway handler received it, before the backend was called.
+// See gateway.QueueDelayAttr.
+func (f *Priority) PostProcess(ctx *gateway.Context) error {
+	if queueDelay, ok := ctx.Attributes[gateway.QueueDelayAttr].(time.Duration); ok {
+		f.controller.ObserveQueueDelay(queueDelay)
+	}
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *Priority) Name() string {
+	return PriorityFilterName
+}
+
+func (f *Priority) classify(ctx *gateway.Context) concurrency.Priority {
+	if class, ok := f.routes[ctx.Route.ID]; ok {
+		return class
+	}
+	if len(f.rules) > 0 {
+		req := ctx.Request.AsHTTPRequest(ctx.Context)
+		for _, rule := range f.rules {
+			if rule.Predicates.TestAll(req) {
+				return rule.Class
+			}
+		}
+	}
+	if f.header != "" {
+		if class, err := concurrency.ParsePriority(ctx.Request.Headers.Get(f.header)); err == nil {
+			return min(class, concurrency.PriorityHigh)
+		}
+	}
+	return f.defaultClass
+}
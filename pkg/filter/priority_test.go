@@ -0,0 +1,239 @@
+package filter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+func TestNewPriorityBuilder(t *testing.T) {
+	tests := []struct {
+		args        map[string]any
+		expectedErr error
+		name        string
+	}{
+		{
+			name:        "build should succeed when no args are present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name: "build should succeed when args are present and are valid",
+			args: map[string]any{
+				"default": "low",
+				"header":  "X-Priority",
+				"routes":  map[string]any{"health": "critical"},
+				"rules": []any{
+					map[string]any{
+						"class": "high",
+						"predicates": []any{
+							map[string]any{"name": "Method", "args": map[string]any{"methods": []any{"POST"}}},
+						},
+					},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name: "build should return error when default class is not valid",
+			args: map[string]any{
+				"default": "urgent",
+			},
+			expectedErr: errors.New("failed to convert 'default' attribute: invalid priority class: urgent"),
+		},
+		{
+			name: "build should return error when route class is not valid",
+			args: map[string]any{
+				"routes": map[string]any{"health": "urgent"},
+			},
+			expectedErr: errors.New("failed to convert 'routes' attribute: route health: invalid priority class: urgent"),
+		},
+		{
+			name: "build should return error when rules are not a list",
+			args: map[string]any{
+				"rules": "high",
+			},
+			expectedErr: errors.New("failed to convert 'rules' attribute: value is required to be a valid slice"),
+		},
+		{
+			name: "build should return error when rule has no predicates",
+			args: map[string]any{
+				"rules": []any{map[string]any{"class": "high"}},
+			},
+			expectedErr: errors.New("failed to convert 'rules' attribute: rule 0: failed to convert 'predicates' attribute: value is required to be a valid slice"),
+		},
+		{
+			name: "build should return error when rule predicate is not valid",
+			args: map[string]any{
+				"rules": []any{
+					map[string]any{
+						"class":      "high",
+						"predicates": []any{map[string]any{"name": "Unknown"}},
+					},
+				},
+			},
+			expectedErr: errors.New("failed to convert 'rules' attribute: rule 0: invalid predicate args: name: Unknown"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := filter.NewPriorityBuilder()
+
+			_, err := builder.Build(tt.args)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestPriority_Name(t *testing.T) {
+	f := filter.NewPriorityFilter(concurrency.DefaultAdmissionController, concurrency.PriorityNormal, "", nil, nil)
+	if f.Name() != filter.PriorityFilterName {
+		t.Errorf("expected name to be %s, got %s", filter.PriorityFilterName, f.Name())
+	}
+}
+
+func TestPriority_PreProcess_Classifies(t *testing.T) {
+	postPredicate := predicate.NewMethodPredicate(http.MethodPost)
+	rules := []filter.PriorityRule{{Predicates: gateway.Predicates{postPredicate}, Class: concurrency.PriorityHigh}}
+	routes := map[string]concurrency.Priority{"health": concurrency.PriorityCritical}
+	tests := []struct {
+		headers  http.Header
+		name     string
+		routeID  string
+		method   string
+		expected concurrency.Priority
+	}{
+		{
+			name:     "classify should use the route class first",
+			routeID:  "health",
+			method:   http.MethodPost,
+			headers:  http.Header{"X-Priority": {"low"}},
+			expected: concurrency.PriorityCritical,
+		},
+		{
+			name:     "classify should use the matching rule before the header",
+			routeID:  "orders",
+			method:   http.MethodPost,
+			headers:  http.Header{"X-Priority": {"low"}},
+			expected: concurrency.PriorityHigh,
+		},
+		{
+			name:     "classify should use the header when no rule matches",
+			routeID:  "orders",
+			method:   http.MethodGet,
+			headers:  http.Header{"X-Priority": {"low"}},
+			expected: concurrency.PriorityLow,
+		},
+		{
+			name:     "classify should not let the header claim the critical class",
+			routeID:  "orders",
+			method:   http.MethodGet,
+			headers:  http.Header{"X-Priority": {"critical"}},
+			expected: concurrency.PriorityHigh,
+		},
+		{
+			name:     "classify should use the default class when nothing else matches",
+			routeID:  "orders",
+			method:   http.MethodGet,
+			headers:  http.Header{"X-Priority": {"whatever"}},
+			expected: concurrency.PriorityNormal,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{})
+			f := filter.NewPriorityFilter(controller, concurrency.PriorityNormal, "X-Priority", routes, rules)
+			route := &gateway.Route{ID: tt.routeID, Timeout: time.Minute}
+			req := &gateway.Request{URL: &url.URL{Path: "/"}, Method: tt.method, Headers: tt.headers}
+			ctx, _ := gateway.NewGatewayContext(t.Context(), route, req)
+
+			if err := f.PreProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Attributes[filter.PriorityAttr] != tt.expected {
+				t.Errorf("expected class %s actual %v", tt.expected, ctx.Attributes[filter.PriorityAttr])
+			}
+		})
+	}
+}
+
+func TestPriority_PreProcess_ShedsUnderLoad(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{
+		MaxInFlight: 1,
+		RetryAfter:  2 * time.Second,
+	})
+	f := filter.NewPriorityFilter(controller, concurrency.PriorityLow, "", nil, nil)
+	first, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+	second, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+
+	controller.Enter()
+	if err := f.PreProcess(first); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	controller.Enter()
+	err := f.PreProcess(second)
+
+	if !errors.Is(err, filter.ErrLoadShed) {
+		t.Errorf("expected err %v actual %v", filter.ErrLoadShed, err)
+	}
+	if second.Attributes[filter.RetryAfterAttr] != 2*time.Second {
+		t.Errorf("expected retry after 2s actual %v", second.Attributes[filter.RetryAfterAttr])
+	}
+}
+
+func TestPriority_PreProcess_CountsRequestsOnRoutesWithoutPriority(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{
+		MaxInFlight: 1,
+	})
+	f := filter.NewPriorityFilter(controller, concurrency.PriorityLow, "", nil, nil)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+	// A request on a route without priority filter, counted by the gateway handler.
+	controller.Enter()
+
+	controller.Enter()
+	err := f.PreProcess(ctx)
+
+	if !errors.Is(err, filter.ErrLoadShed) {
+		t.Errorf("expected err %v actual %v", filter.ErrLoadShed, err)
+	}
+}
+
+func TestPriority_PostProcess_ObservesQueueDelay(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{
+		MaxQueueDelay: time.Nanosecond,
+	})
+	f := filter.NewPriorityFilter(controller, concurrency.PriorityNormal, "", nil, nil)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{Timeout: time.Minute}, &gateway.Request{})
+
+	if err := f.PreProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	ctx.Attributes[gateway.QueueDelayAttr] = time.Millisecond
+	ctx.Response = &gateway.Response{
+		Status:     http.StatusOK,
+		Headers:    http.Header{},
+		BodyReader: gateway.NewReplayableBody(io.NopCloser(bytes.NewReader([]byte("streamed"))), -1),
+	}
+	if err := f.PostProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if controller.Admit(concurrency.PriorityLow) {
+		t.Errorf("expected low requests shed once the queue delay is over the threshold")
+	}
+}
@@ -0,0 +1,124 @@
+package filter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// RedirectToFilterName is the name of the filter.
+const RedirectToFilterName = "RedirectTo"
+
+// RedirectTo is a filter that answers the request with a redirect, without calling the backend.
+//
+// The location is a URL template that can reference the path variables captured by the route predicates as
+// '{name}'. The path of the request can be appended to the path of the location, and the query of the request
+// to its query.
+//
+// For example, with the location 'https://new.example.org/v2' keeping path and query, the request
+// '/users/42?verbose=true' is redirected to 'https://new.example.org/v2/users/42?verbose=true'.
+type RedirectTo struct {
+	location  shared.VariableTemplate
+	status    int
+	keepPath  bool
+	keepQuery bool
+}
+
+// NewRedirectToFilter creates a new RedirectToFilter. The status must be 301, 302, 307 or 308.
+func NewRedirectToFilter(status int, location string, keepPath, keepQuery bool) (*RedirectTo, error) {
+	redirectStatuses := []int{
+		http.StatusMovedPermanently, http.StatusFound, http.StatusTemporaryRedirect, http.StatusPermanentRedirect,
+	}
+	if !slices.Contains(redirectStatuses, status) {
+		return nil, fmt.Errorf("failed to build redirect to filter: %w: %d is not a redirect status",
+			ErrInvalidStatus, status)
+	}
+	if _, err := url.Parse(location); err != nil {
+		return nil, fmt.Errorf("failed to build redirect to filter: %w", err)
+	}
+	return &RedirectTo{
+		location:  shared.ParseVariableTemplate(location),
+		status:    status,
+		keepPath:  keepPath,
+		keepQuery: keepQuery,
+	}, nil
+}
+
+// NewRedirectToBuilder creates a new RedirectToBuilder.
+//
+// The args are expected to contain the following keys:
+// - status: the redirect status, 301, 302, 307 or 308.
+// - url: the location URL template.
+// - keep-path: optional, append the request path to the location path (default false).
+// - keep-query: optional, append the request query to the location query (default false).
+func NewRedirectToBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		status, err := shared.ConvertToInt(args["status"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'status' attribute: %w", err)
+		}
+		location, err := shared.ConvertToString(args["url"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'url' attribute: %w", err)
+		}
+		keepPath, err := convertOptionalBool(args, "keep-path")
+		if err != nil {
+			return nil, err
+		}
+		keepQuery, err := convertOptionalBool(args, "keep-query")
+		if err != nil {
+			return nil, err
+		}
+		return NewRedirectToFilter(status, location, keepPath, keepQuery)
+	}
+}
+
+// PreProcess answers the request with the redirect.
+func (f *RedirectTo) PreProcess(ctx *gateway.Context) error {
+	location, err := url.Parse(f.location.ExpandEscaped(gateway.PathVariables(ctx), escapePathValue))
+	if err != nil {
+		return fmt.Errorf("failed to build redirect location: %w", err)
+	}
+	if f.keepPath {
+		escapedPath := strings.TrimSuffix(location.EscapedPath(), "/") + ctx.Request.URL.EscapedPath()
+		location.Path, _ = url.PathUnescape(escapedPath)
+		location.RawPath = escapedPath
+	}
+	if f.keepQuery && ctx.Request.URL.RawQuery != "" {
+		if location.RawQuery != "" {
+			location.RawQuery += "&"
+		}
+		location.RawQuery += ctx.Request.URL.RawQuery
+	}
+	headers := http.Header{}
+	headers.Set("Location", location.String())
+	ctx.Response = gateway.NewStaticGatewayResponse(f.status, headers, "")
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *RedirectTo) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RedirectTo) Name() string {
+	return RedirectToFilterName
+}
+
+// convertOptionalBool converts the named arg to a bool, returning false when it is not present.
+func convertOptionalBool(args map[string]any, name string) (bool, error) {
+	if args[name] == nil {
+		return false, nil
+	}
+	value, err := shared.ConvertToBool(args[name])
+	if err != nil {
+		return false, fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
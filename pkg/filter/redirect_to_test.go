@@ -0,0 +1,151 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewRedirectToBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"status": 301, "url": "https://new.example.org", "keep-path": true, "keep-query": "true"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when status argument is not present",
+			args:        map[string]any{"url": "https://new.example.org"},
+			expectedErr: errors.New("failed to convert 'status' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when status is not a redirect status",
+			args:        map[string]any{"status": 200, "url": "https://new.example.org"},
+			expectedErr: errors.New("failed to build redirect to filter: invalid status: 200 is not a redirect status"),
+		},
+		{
+			name:        "build should fail when url argument is not present",
+			args:        map[string]any{"status": 302},
+			expectedErr: errors.New("failed to convert 'url' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when url is not valid",
+			args:        map[string]any{"status": 302, "url": "http://[::1"},
+			expectedErr: errors.New("failed to build redirect to filter: parse \"http://[::1\": missing ']' in host"),
+		},
+		{
+			name:        "build should fail when keep path argument is not valid",
+			args:        map[string]any{"status": 302, "url": "/", "keep-path": "maybe"},
+			expectedErr: errors.New("failed to convert 'keep-path' attribute: value is required to be a valid bool"),
+		},
+		{
+			name:        "build should fail when keep query argument is not valid",
+			args:        map[string]any{"status": 302, "url": "/", "keep-query": 1},
+			expectedErr: errors.New("failed to convert 'keep-query' attribute: value is required to be a valid bool"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewRedirectToBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRedirectToFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		variables        map[string]string
+		name             string
+		location         string
+		target           string
+		expectedLocation string
+		status           int
+		keepPath         bool
+		keepQuery        bool
+	}{
+		{
+			name:             "pre process should redirect to location",
+			status:           http.StatusMovedPermanently,
+			location:         "https://new.example.org/home",
+			target:           "http://example.org/legacy?a=1",
+			expectedLocation: "https://new.example.org/home",
+		},
+		{
+			name:             "pre process should keep path and query",
+			status:           http.StatusPermanentRedirect,
+			location:         "https://new.example.org/v2/",
+			target:           "http://example.org/users/a%2Fb?a=1",
+			keepPath:         true,
+			keepQuery:        true,
+			expectedLocation: "https://new.example.org/v2/users/a%2Fb?a=1",
+		},
+		{
+			name:             "pre process should append query to location query",
+			status:           http.StatusFound,
+			location:         "https://new.example.org/search?source=legacy",
+			target:           "http://example.org/find?q=go+lang",
+			keepQuery:        true,
+			expectedLocation: "https://new.example.org/search?source=legacy&q=go+lang",
+		},
+		{
+			name:             "pre process should expand path variables",
+			status:           http.StatusTemporaryRedirect,
+			location:         "/users/{id}/profile",
+			variables:        map[string]string{"id": "a b"},
+			target:           "http://example.org/profiles/a%20b",
+			expectedLocation: "/users/a%20b/profile",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.target, nil)
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			if tt.variables != nil {
+				ctx.Attributes[gateway.PathVariablesAttr] = tt.variables
+			}
+			f, _ := filter.NewRedirectToFilter(tt.status, tt.location, tt.keepPath, tt.keepQuery)
+
+			if err := f.PreProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Response == nil || ctx.Response.Status != tt.status {
+				t.Fatalf("expected response with status %d actual %v", tt.status, ctx.Response)
+			}
+			if location := ctx.Response.Headers.Get("Location"); location != tt.expectedLocation {
+				t.Errorf("expected location %s actual %s", tt.expectedLocation, location)
+			}
+		})
+	}
+}
+
+func TestRedirectToFilter_PostProcess(t *testing.T) {
+	f, _ := filter.NewRedirectToFilter(http.StatusFound, "/", false, false)
+	if err := f.PostProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestRedirectToFilter_Name(t *testing.T) {
+	expected := "RedirectTo"
+
+	f, _ := filter.NewRedirectToFilter(http.StatusFound, "/", false, false)
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
@@ -9,13 +9,38 @@ import "github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 //
 //nolint:gochecknoglobals
 var BuilderRegistry gateway.FilterBuilderRegistry = map[string]gateway.FilterBuilder{
-	AddRequestHeaderFilterName:      NewAddRequestHeaderBuilder(),
-	SetRequestHeaderFilterName:      NewSetRequestHeaderBuilder(),
-	RemoveRequestHeaderFilterName:   NewRemoveRequestHeaderBuilder(),
-	AddResponseHeaderFilterName:     NewAddResponseHeaderBuilder(),
-	SetResponseHeaderFilterName:     NewSetResponseHeaderBuilder(),
-	RemoveResponseHeaderFilterName:  NewRemoveResponseHeaderBuilder(),
-	RequestResponseLoggerFilterName: NewRequestResponseLoggerBuilder(),
-	RewritePathFilterName:           NewRewritePathBuilder(),
-	RateLimitFilterName:             NewRateLimitBuilder(),
+	AddRequestHeaderFilterName:              NewAddRequestHeaderBuilder(),
+	SetRequestHeaderFilterName:              NewSetRequestHeaderBuilder(),
+	RemoveRequestHeaderFilterName:           NewRemoveRequestHeaderBuilder(),
+	RewriteRequestHeaderFilterName:          NewRewriteRequestHeaderBuilder(),
+	MapRequestHeaderFilterName:              NewMapRequestHeaderBuilder(),
+	AddResponseHeaderFilterName:             NewAddResponseHeaderBuilder(),
+	SetResponseHeaderFilterName:             NewSetResponseHeaderBuilder(),
+	RemoveResponseHeaderFilterName:          NewRemoveResponseHeaderBuilder(),
+	RewriteResponseHeaderFilterName:         NewRewriteResponseHeaderBuilder(),
+	DedupeResponseHeaderFilterName:          NewDedupeResponseHeaderBuilder(),
+	RewriteLocationResponseHeaderFilterName: NewRewriteLocationResponseHeaderBuilder(),
+	RewriteSetCookieFilterName:              NewRewriteSetCookieBuilder(),
+	SecureHeadersFilterName:                 NewSecureHeadersBuilder(),
+	AddRequestParameterFilterName:           NewAddRequestParameterBuilder(),
+	SetRequestParameterFilterName:           NewSetRequestParameterBuilder(),
+	RemoveRequestParameterFilterName:        NewRemoveRequestParameterBuilder(),
+	RenameRequestParameterFilterName:        NewRenameRequestParameterBuilder(),
+	RequestResponseLoggerFilterName:         NewRequestResponseLoggerBuilder(),
+	RewritePathFilterName:                   NewRewritePathBuilder(),
+	SetPathFilterName:                       NewSetPathBuilder(),
+	StripPrefixFilterName:                   NewStripPrefixBuilder(),
+	PrefixPathFilterName:                    NewPrefixPathBuilder(),
+	RedirectToFilterName:                    NewRedirectToBuilder(),
+	SetStatusFilterName:                     NewSetStatusBuilder(),
+	StaticResponseFilterName:                NewStaticResponseBuilder(),
+	RequestSizeFilterName:                   NewRequestSizeBuilder(),
+	ResponseSizeFilterName:                  NewResponseSizeBuilder(),
+	CompressFilterName:                      NewCompressBuilder(),
+	DecompressRequestFilterName:             NewDecompressRequestBuilder(),
+	RateLimitFilterName:                     NewRateLimitBuilder(),
+	ConcurrencyLimitFilterName:              NewConcurrencyLimitBuilder(),
+	AdaptiveConcurrencyLimitFilterName:      NewAdaptiveConcurrencyLimitBuilder(),
+	PriorityFilterName:                      NewPriorityBuilder(),
+	IPFilterFilterName:                      NewIPFilterBuilder(),
 }
@@ -1,7 +1,9 @@
 package filter
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"log/slog"
 	"strings"
 
@@ -17,6 +19,9 @@ const RequestResponseLoggerFilterName = "RequestResponseLogger"
 const DefaultMaxLoggedBodyBytes int64 = 64 * 1024
 
 // RequestResponseLogger is a filter that logs the request and response.
+//
+// The bodies encoded with a registered content coding, see gateway.ContentDecoderRegistry, are logged decoded,
+// while they are still forwarded as they were received.
 type RequestResponseLogger struct {
 	level        slog.Level
 	maxBodyBytes int64
@@ -99,9 +104,13 @@ func (f *RequestResponseLogger) PreProcess(ctx *gateway.Context) error {
 		return nil
 	}
 	var body []byte
-	if err := ctx.Request.BodyReader.CaptureWithLimit(f.maxBodyBytes); err == nil {
+	contentEncoding := ctx.Request.Headers.Get("Content-Encoding")
+	if err := ctx.Request.BodyReader.CaptureDecoded(f.maxBodyBytes, contentEncoding, f.decodeLimits()); err == nil {
 		// The captured buffer is logged as-is: re-reading the body here would
 		// copy it twice more for no benefit.
+		body = ctx.Request.BodyReader.DecodedBytes()
+	} else {
+		// A body that cannot be decoded is logged as it was received, when it was captured.
 		body = ctx.Request.BodyReader.Bytes()
 	}
 	ctx.Logger.Log(ctx, f.level, "Received request",
@@ -134,7 +143,8 @@ func (f *RequestResponseLogger) PostProcess(ctx *gateway.Context) error {
 			body = appendUpToLimit(body, chunk, f.maxBodyBytes)
 		},
 		func(total int64, err error) {
-			attrs := []any{"status", status, "headers", headers, "body", body, "bytes", total}
+			logged := decodeLoggedBody(body, headers.Get("Content-Encoding"), f.decodeLimits())
+			attrs := []any{"status", status, "headers", headers, "body", logged, "bytes", total}
 			if err != nil {
 				attrs = append(attrs, "error", err)
 			}
@@ -144,6 +154,39 @@ func (f *RequestResponseLogger) PostProcess(ctx *gateway.Context) error {
 	return nil
 }
 
+// decodeLimits returns the limits of the logged decoded bodies: they are as large as the logged bodies, or as
+// large as the decompressed request bodies when the logged bodies are not limited. The ratio is always limited,
+// so that a logged body cannot be a decompression bomb.
+func (f *RequestResponseLogger) decodeLimits() gateway.DecodeLimits {
+	limits := gateway.DecodeLimits{
+		MaxBytes: DefaultDecompressMaxSize,
+		MaxRatio: DefaultDecompressMaxRatio,
+	}
+	if f.maxBodyBytes > 0 {
+		limits.MaxBytes = f.maxBodyBytes
+	}
+	return limits
+}
+
+// decodeLoggedBody returns the logged body decoded from the content encoding. The logged body may be only a
+// prefix of the body, so as much of it as can be decoded is returned. It is returned as is when it cannot be
+// decoded at all.
+func decodeLoggedBody(body []byte, contentEncoding string, limits gateway.DecodeLimits) []byte {
+	if len(body) == 0 || len(gateway.ContentCodings(contentEncoding)) == 0 {
+		return body
+	}
+	decoder, err := gateway.NewContentDecoder(contentEncoding, bytes.NewReader(body), limits)
+	if err != nil {
+		return body
+	}
+	defer decoder.Close() //nolint:errcheck
+	decoded, _ := io.ReadAll(decoder)
+	if len(decoded) == 0 {
+		return body
+	}
+	return decoded
+}
+
 // appendUpToLimit appends chunk to body, capping the total at maxBytes bytes. A negative
 // maxBytes means no cap. It keeps the logged prefix bounded while the full body still
 // streams to the client untouched.
@@ -393,3 +393,85 @@ func TestRequestResponseLogger_PostProcess_TruncatedStreamLogsError(t *testing.T
 		t.Errorf("expected the truncation error logged, got: %s", logged)
 	}
 }
+
+func TestRequestResponseLogger_EncodedBodiesAreLoggedDecoded(t *testing.T) {
+	payload := []byte(`{"k1":"abc"}`)
+	encoded := gzipBody(t, payload)
+
+	t.Run("request body is logged decoded and forwarded encoded", func(t *testing.T) {
+		var buf bytes.Buffer
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.org/test", bytes.NewReader(encoded))
+		req.Header.Set("Content-Encoding", "gzip")
+		ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+		ctx.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+		f := filter.NewRequestResponseLoggerFilter(slog.LevelInfo, filter.DefaultMaxLoggedBodyBytes, true)
+		if err := f.PreProcess(ctx); err != nil {
+			t.Fatalf("pre-process failed: %v", err)
+		}
+
+		if !strings.Contains(buf.String(), `body="{\"k1\":\"abc\"}"`) {
+			t.Errorf("expected the decoded body logged, got: %s", buf.String())
+		}
+		got, err := io.ReadAll(ctx.Request.BodyReader)
+		if err != nil || !bytes.Equal(got, encoded) {
+			t.Errorf("expected the encoded body forwarded, got %q (err %v)", got, err)
+		}
+	})
+
+	t.Run("request body decoded over the max body bytes is logged as its decoded prefix", func(t *testing.T) {
+		var buf bytes.Buffer
+		large := gzipBody(t, []byte(strings.Repeat("a", 1000)))
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.org/test", bytes.NewReader(large))
+		req.Header.Set("Content-Encoding", "gzip")
+		ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+		ctx.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+		f := filter.NewRequestResponseLoggerFilter(slog.LevelInfo, 64, true)
+		if err := f.PreProcess(ctx); err != nil {
+			t.Fatalf("pre-process failed: %v", err)
+		}
+
+		if !strings.Contains(buf.String(), `body="`+strings.Repeat("a", 64)+`"`) {
+			t.Errorf("expected the decoded prefix logged, got: %s", buf.String())
+		}
+	})
+
+	t.Run("request body decoded over the max ratio is bounded when the logged bodies are not limited", func(t *testing.T) {
+		var buf bytes.Buffer
+		decodedSize := 4 * gateway.DecodeRatioThreshold
+		bomb := gzipBody(t, make([]byte, decodedSize))
+		req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.org/test", bytes.NewReader(bomb))
+		req.Header.Set("Content-Encoding", "gzip")
+		ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+		ctx.Logger = slog.New(slog.NewTextHandler(&buf, nil))
+
+		f := filter.NewRequestResponseLoggerFilter(slog.LevelInfo, -1, true)
+		if err := f.PreProcess(ctx); err != nil {
+			t.Fatalf("pre-process failed: %v", err)
+		}
+
+		if decoded := len(ctx.Request.BodyReader.DecodedBytes()); decoded == 0 || decoded >= decodedSize {
+			t.Errorf("expected the decoded view bounded below %d bytes, actual %d", decodedSize, decoded)
+		}
+	})
+
+	t.Run("response body is logged decoded and streamed encoded", func(t *testing.T) {
+		var buf bytes.Buffer
+		ctx := newLoggedResponseContext(t, &buf, encoded, int64(len(encoded)))
+		ctx.Response.Headers.Set("Content-Encoding", "gzip")
+
+		f := filter.NewRequestResponseLoggerFilter(slog.LevelInfo, filter.DefaultMaxLoggedBodyBytes, true)
+		if err := f.PostProcess(ctx); err != nil {
+			t.Fatalf("post-process failed: %v", err)
+		}
+		got, err := io.ReadAll(ctx.Response.BodyReader)
+		if err != nil || !bytes.Equal(got, encoded) {
+			t.Errorf("expected the encoded body streamed, got %q (err %v)", got, err)
+		}
+
+		if !strings.Contains(buf.String(), `body="{\"k1\":\"abc\"}"`) {
+			t.Errorf("expected the decoded body logged, got: %s", buf.String())
+		}
+	})
+}
@@ -2,6 +2,7 @@ package filter
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -16,16 +17,29 @@ const (
 
 	// RemoveRequestHeaderFilterName is the name of the filter.
 	RemoveRequestHeaderFilterName = "RemoveRequestHeader"
+
+	// RewriteRequestHeaderFilterName is the name of the filter.
+	RewriteRequestHeaderFilterName = "RewriteRequestHeader"
+
+	// MapRequestHeaderFilterName is the name of the filter.
+	MapRequestHeaderFilterName = "MapRequestHeader"
 )
 
 // AddRequestHeader is a filter that adds a header to the request.
+//
+// The value is an Expression, resolved for each request.
 type AddRequestHeader struct {
+	headerValue Expression
 	headerName  string
-	headerValue string
 }
 
-// NewAddRequestHeaderFilter creates a new AddRequestHeaderFilter.
+// NewAddRequestHeaderFilter creates a new AddRequestHeaderFilter. The value is a LiteralExpression.
 func NewAddRequestHeaderFilter(name, value string) *AddRequestHeader {
+	return NewAddRequestHeaderFilterWithExpression(name, LiteralExpression(value))
+}
+
+// NewAddRequestHeaderFilterWithExpression creates a new AddRequestHeaderFilter whose value is an Expression.
+func NewAddRequestHeaderFilterWithExpression(name string, value Expression) *AddRequestHeader {
 	return &AddRequestHeader{
 		headerName:  name,
 		headerValue: value,
@@ -43,13 +57,17 @@ func NewAddRequestHeaderBuilder() gateway.FilterBuilderFunc {
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert 'value' attribute: %w", err)
 		}
-		return NewAddRequestHeaderFilter(name, value), nil
+		expression, err := CompileExpression(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build add request header filter: %w", err)
+		}
+		return NewAddRequestHeaderFilterWithExpression(name, expression), nil
 	}
 }
 
 // PreProcess adds the header to the request.
 func (f *AddRequestHeader) PreProcess(ctx *gateway.Context) error {
-	ctx.Request.Headers.Add(f.headerName, f.headerValue)
+	ctx.Request.Headers.Add(f.headerName, f.headerValue.Expand(ctx))
 	return nil
 }
 
@@ -64,9 +82,11 @@ func (f *AddRequestHeader) Name() string {
 }
 
 // SetRequestHeader is a filter that sets a header in the request.
+//
+// The value is an Expression, resolved for each request.
 type SetRequestHeader struct {
+	headerValue Expression
 	headerName  string
-	headerValue string
 }
 
 // NewSetRequestHeaderBuilder creates a new SetRequestHeaderBuilder.
@@ -80,12 +100,21 @@ func NewSetRequestHeaderBuilder() gateway.FilterBuilderFunc {
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert 'value' attribute: %w", err)
 		}
-		return NewSetRequestHeaderFilter(name, value), nil
+		expression, err := CompileExpression(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build set request header filter: %w", err)
+		}
+		return NewSetRequestHeaderFilterWithExpression(name, expression), nil
 	}
 }
 
-// NewSetRequestHeaderFilter creates a new SetRequestHeaderFilter.
+// NewSetRequestHeaderFilter creates a new SetRequestHeaderFilter. The value is a LiteralExpression.
 func NewSetRequestHeaderFilter(name, value string) *SetRequestHeader {
+	return NewSetRequestHeaderFilterWithExpression(name, LiteralExpression(value))
+}
+
+// NewSetRequestHeaderFilterWithExpression creates a new SetRequestHeaderFilter whose value is an Expression.
+func NewSetRequestHeaderFilterWithExpression(name string, value Expression) *SetRequestHeader {
 	return &SetRequestHeader{
 		headerName:  name,
 		headerValue: value,
@@ -94,7 +123,7 @@ func NewSetRequestHeaderFilter(name, value string) *SetRequestHeader {
 
 // PreProcess sets the header in the request.
 func (f *SetRequestHeader) PreProcess(ctx *gateway.Context) error {
-	ctx.Request.Headers.Set(f.headerName, f.headerValue)
+	ctx.Request.Headers.Set(f.headerName, f.headerValue.Expand(ctx))
 	return nil
 }
 
@@ -146,3 +175,97 @@ func (f *RemoveRequestHeader) PostProcess(_ *gateway.Context) error {
 func (f *RemoveRequestHeader) Name() string {
 	return RemoveRequestHeaderFilterName
 }
+
+// RewriteRequestHeader is a filter that replaces the matches of a regexp in the values of a request header.
+//
+// The replacement can reference the groups of the regexp as '$name' or '$\name'.
+type RewriteRequestHeader struct {
+	rewrite headerValueRewrite
+}
+
+// NewRewriteRequestHeaderFilter creates a new RewriteRequestHeaderFilter.
+func NewRewriteRequestHeaderFilter(name, regexpStr, replacement string) (*RewriteRequestHeader, error) {
+	rewrite, err := newHeaderValueRewrite(name, regexpStr, replacement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rewrite request header filter: %w", err)
+	}
+	return &RewriteRequestHeader{
+		rewrite: rewrite,
+	}, nil
+}
+
+// NewRewriteRequestHeaderBuilder creates a new RewriteRequestHeaderBuilder.
+func NewRewriteRequestHeaderBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		name, regexpStr, replacement, err := convertHeaderRewriteArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		return NewRewriteRequestHeaderFilter(name, regexpStr, replacement)
+	}
+}
+
+// PreProcess rewrites the values of the request header.
+func (f *RewriteRequestHeader) PreProcess(ctx *gateway.Context) error {
+	f.rewrite.apply(ctx.Request.Headers)
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *RewriteRequestHeader) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RewriteRequestHeader) Name() string {
+	return RewriteRequestHeaderFilterName
+}
+
+// MapRequestHeader is a filter that adds the values of a request header to another request header.
+//
+// When the source header is missing, the request is left as is.
+type MapRequestHeader struct {
+	fromHeader string
+	toHeader   string
+}
+
+// NewMapRequestHeaderFilter creates a new MapRequestHeaderFilter.
+func NewMapRequestHeaderFilter(from, to string) *MapRequestHeader {
+	return &MapRequestHeader{
+		fromHeader: from,
+		toHeader:   to,
+	}
+}
+
+// NewMapRequestHeaderBuilder creates a new MapRequestHeaderBuilder.
+func NewMapRequestHeaderBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		from, err := shared.ConvertToString(args["from"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'from' attribute: %w", err)
+		}
+		to, err := shared.ConvertToString(args["to"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'to' attribute: %w", err)
+		}
+		return NewMapRequestHeaderFilter(from, to), nil
+	}
+}
+
+// PreProcess adds the values of the source header to the target header.
+func (f *MapRequestHeader) PreProcess(ctx *gateway.Context) error {
+	for _, value := range slices.Clone(ctx.Request.Headers.Values(f.fromHeader)) {
+		ctx.Request.Headers.Add(f.toHeader, value)
+	}
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *MapRequestHeader) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *MapRequestHeader) Name() string {
+	return MapRequestHeaderFilterName
+}
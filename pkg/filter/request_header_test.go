@@ -39,6 +39,15 @@ func TestNewAddRequestHeaderBuilder(t *testing.T) {
 			},
 			expectedErr: errors.New("failed to convert 'value' attribute: value is required"),
 		},
+		{
+			name: "build should fail when value references unknown variable",
+			args: map[string]any{
+				"name":  "First",
+				"value": "${request.unknown}",
+			},
+			expectedErr: errors.New(
+				"failed to build add request header filter: unknown expression variable: request.unknown"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -88,6 +97,13 @@ func TestAddRequestHeaderFilter_PreProcess(t *testing.T) {
 			currentHeaders:  map[string][]string{"Accept-Language": {"es_ES"}, "X-Test-Header": {"False"}},
 			expectedHeaders: map[string][]string{"Accept-Language": {"es_ES"}, "X-Test-Header": {"False", "True"}},
 		},
+		{
+			name:            "add request header should succeed when value references path variables",
+			headerKey:       "X-Test-Header",
+			headerValue:     "{segment}-{unknown}",
+			currentHeaders:  map[string][]string{},
+			expectedHeaders: map[string][]string{"X-Test-Header": {"users-{unknown}"}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -95,6 +111,7 @@ func TestAddRequestHeaderFilter_PreProcess(t *testing.T) {
 			req.Header = tt.currentHeaders
 			gwReq := gateway.NewGatewayRequest(req)
 			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gwReq)
+			ctx.Attributes[gateway.PathVariablesAttr] = map[string]string{"segment": "users"}
 
 			f := filter.NewAddRequestHeaderFilter(tt.headerKey, tt.headerValue)
 
@@ -144,6 +161,15 @@ func TestNewSetRequestHeaderBuilder(t *testing.T) {
 			},
 			expectedErr: errors.New("failed to convert 'value' attribute: value is required"),
 		},
+		{
+			name: "build should fail when value references unknown variable",
+			args: map[string]any{
+				"name":  "First",
+				"value": "${request.unknown}",
+			},
+			expectedErr: errors.New(
+				"failed to build set request header filter: unknown expression variable: request.unknown"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -193,6 +219,13 @@ func TestSetRequestHeaderFilter_PreProcess(t *testing.T) {
 			currentHeaders:  map[string][]string{"Accept-Language": {"es_ES"}, "X-Test-Header": {"False"}},
 			expectedHeaders: map[string][]string{"Accept-Language": {"es_ES"}, "X-Test-Header": {"True"}},
 		},
+		{
+			name:            "set request header should succeed when value references path variables",
+			headerKey:       "X-Test-Header",
+			headerValue:     "/{segment}",
+			currentHeaders:  map[string][]string{"X-Test-Header": {"False"}},
+			expectedHeaders: map[string][]string{"X-Test-Header": {"/users"}},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -200,6 +233,7 @@ func TestSetRequestHeaderFilter_PreProcess(t *testing.T) {
 			req.Header = tt.currentHeaders
 			gwReq := gateway.NewGatewayRequest(req)
 			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gwReq)
+			ctx.Attributes[gateway.PathVariablesAttr] = map[string]string{"segment": "users"}
 
 			f := filter.NewSetRequestHeaderFilter(tt.headerKey, tt.headerValue)
 
@@ -312,3 +346,203 @@ func TestRemoveRequestHeaderFilter_PostProcess(t *testing.T) {
 		t.Errorf("expected nil err actual %s", err)
 	}
 }
+
+func TestNewRewriteRequestHeaderBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"name": "X-Test-Header", "regexp": "password=[^&]+", "replacement": "password=***"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when name argument is not present",
+			args:        map[string]any{"regexp": "a", "replacement": "b"},
+			expectedErr: errors.New("failed to convert 'name' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when regexp argument is not present",
+			args:        map[string]any{"name": "X-Test-Header", "replacement": "b"},
+			expectedErr: errors.New("failed to convert 'regexp' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when replacement argument is not present",
+			args:        map[string]any{"name": "X-Test-Header", "regexp": "a"},
+			expectedErr: errors.New("failed to convert 'replacement' attribute: value is required"),
+		},
+		{
+			name: "build should fail when regexp argument is not valid",
+			args: map[string]any{"name": "X-Test-Header", "regexp": "(", "replacement": "b"},
+			expectedErr: errors.New(
+				"failed to build rewrite request header filter: error parsing regexp: missing closing ): `(`"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewRewriteRequestHeaderBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRewriteRequestHeaderFilter_Name(t *testing.T) {
+	expected := "RewriteRequestHeader"
+
+	f, _ := filter.NewRewriteRequestHeaderFilter("X-Test-Header", "a", "b")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
+
+func TestRewriteRequestHeaderFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		currentHeaders  http.Header
+		expectedHeaders http.Header
+		name            string
+		regexp          string
+		replacement     string
+	}{
+		{
+			name:            "rewrite request header should replace every match of every value",
+			regexp:          "password=[^&]+",
+			replacement:     "password=***",
+			currentHeaders:  map[string][]string{"X-Test-Header": {"user=a&password=b", "password=c"}},
+			expectedHeaders: map[string][]string{"X-Test-Header": {"user=a&password=***", "password=***"}},
+		},
+		{
+			name:            "rewrite request header should replace groups",
+			regexp:          `^Bearer (?P<token>.+)$`,
+			replacement:     "Token $\\token",
+			currentHeaders:  map[string][]string{"X-Test-Header": {"Bearer abc"}},
+			expectedHeaders: map[string][]string{"X-Test-Header": {"Token abc"}},
+		},
+		{
+			name:            "rewrite request header should do nothing when header not present",
+			regexp:          "a",
+			replacement:     "b",
+			currentHeaders:  map[string][]string{"Accept-Language": {"es_ES"}},
+			expectedHeaders: map[string][]string{"Accept-Language": {"es_ES"}, "X-Test-Header": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			req.Header = tt.currentHeaders
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			f, _ := filter.NewRewriteRequestHeaderFilter("X-Test-Header", tt.regexp, tt.replacement)
+
+			_ = f.PreProcess(ctx)
+
+			for k, valueList := range tt.expectedHeaders {
+				if !slices.Equal(valueList, ctx.Request.Headers[k]) {
+					t.Errorf("expected %v actual %v", valueList, ctx.Request.Headers[k])
+				}
+			}
+		})
+	}
+}
+
+func TestRewriteRequestHeaderFilter_PostProcess(t *testing.T) {
+	f, _ := filter.NewRewriteRequestHeaderFilter("X-Test-Header", "a", "b")
+	if err := f.PostProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestNewMapRequestHeaderBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"from": "X-Request-Id", "to": "X-Correlation-Id"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when from argument is not present",
+			args:        map[string]any{"to": "X-Correlation-Id"},
+			expectedErr: errors.New("failed to convert 'from' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when to argument is not present",
+			args:        map[string]any{"from": "X-Request-Id"},
+			expectedErr: errors.New("failed to convert 'to' attribute: value is required"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewMapRequestHeaderBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestMapRequestHeaderFilter_Name(t *testing.T) {
+	expected := "MapRequestHeader"
+
+	f := filter.NewMapRequestHeaderFilter("X-Request-Id", "X-Correlation-Id")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
+
+func TestMapRequestHeaderFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		currentHeaders  http.Header
+		expectedHeaders http.Header
+		name            string
+	}{
+		{
+			name:            "map request header should add every value to target header",
+			currentHeaders:  map[string][]string{"X-Request-Id": {"1", "2"}, "X-Correlation-Id": {"0"}},
+			expectedHeaders: map[string][]string{"X-Request-Id": {"1", "2"}, "X-Correlation-Id": {"0", "1", "2"}},
+		},
+		{
+			name:            "map request header should do nothing when source header not present",
+			currentHeaders:  map[string][]string{"Accept-Language": {"es_ES"}},
+			expectedHeaders: map[string][]string{"Accept-Language": {"es_ES"}, "X-Correlation-Id": nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			req.Header = tt.currentHeaders
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			f := filter.NewMapRequestHeaderFilter("X-Request-Id", "X-Correlation-Id")
+
+			_ = f.PreProcess(ctx)
+
+			for k, valueList := range tt.expectedHeaders {
+				if !slices.Equal(valueList, ctx.Request.Headers[k]) {
+					t.Errorf("expected %v actual %v", valueList, ctx.Request.Headers[k])
+				}
+			}
+		})
+	}
+}
+
+func TestMapRequestHeaderFilter_PostProcess(t *testing.T) {
+	f := filter.NewMapRequestHeaderFilter("X-Request-Id", "X-Correlation-Id")
+	if err := f.PostProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
@@ -0,0 +1,382 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrInvalidRequestParameterFilter is returned when a request parameter filter has no value or more than one.
+var ErrInvalidRequestParameterFilter = errors.New("invalid request parameter filter")
+
+const (
+	// AddRequestParameterFilterName is the name of the filter.
+	AddRequestParameterFilterName = "AddRequestParameter"
+
+	// SetRequestParameterFilterName is the name of the filter.
+	SetRequestParameterFilterName = "SetRequestParameter"
+
+	// RemoveRequestParameterFilterName is the name of the filter.
+	RemoveRequestParameterFilterName = "RemoveRequestParameter"
+
+	// RenameRequestParameterFilterName is the name of the filter.
+	RenameRequestParameterFilterName = "RenameRequestParameter"
+)
+
+type parameterValueSource int
+
+const (
+	parameterValueLiteral parameterValueSource = iota
+	parameterValueHeader
+	parameterValueAttribute
+)
+
+// ParameterValue is the value of a request parameter filter: a literal value, which is an Expression resolved
+// for each request, the value of a request header, or the value of a context attribute.
+type ParameterValue struct {
+	expression Expression
+	name       string
+	source     parameterValueSource
+}
+
+// LiteralParameterValue creates a ParameterValue from a literal value, which is a LiteralExpression.
+func LiteralParameterValue(value string) ParameterValue {
+	return ExpressionParameterValue(LiteralExpression(value))
+}
+
+// ExpressionParameterValue creates a ParameterValue from an Expression.
+func ExpressionParameterValue(value Expression) ParameterValue {
+	return ParameterValue{expression: value, source: parameterValueLiteral}
+}
+
+// HeaderParameterValue creates a ParameterValue from the first value of the given request header.
+func HeaderParameterValue(name string) ParameterValue {
+	return ParameterValue{name: name, source: parameterValueHeader}
+}
+
+// AttributeParameterValue creates a ParameterValue from the given context attribute. The values that are not
+// strings are formatted.
+func AttributeParameterValue(name string) ParameterValue {
+	return ParameterValue{name: name, source: parameterValueAttribute}
+}
+
+// resolve returns the value for the request, and false when its header or attribute is missing.
+func (v ParameterValue) resolve(ctx *gateway.Context) (string, bool) {
+	switch v.source {
+	case parameterValueHeader:
+		values := ctx.Request.Headers.Values(v.name)
+		if len(values) == 0 {
+			return "", false
+		}
+		return values[0], true
+	case parameterValueAttribute:
+		return attributeString(ctx, v.name)
+	default:
+		return v.expression.Expand(ctx), true
+	}
+}
+
+// convertParameterValue converts the value arg of a request parameter filter, one of:
+// - value: a literal value, which is an Expression.
+// - from-header: the name of the request header holding the value.
+// - from-attribute: the name of the context attribute holding the value.
+func convertParameterValue(args map[string]any) (ParameterValue, error) {
+	sources := []struct {
+		create func(string) (ParameterValue, error)
+		arg    string
+	}{
+		{arg: "value", create: compileParameterValue},
+		{arg: "from-header", create: infallibleParameterValue(HeaderParameterValue)},
+		{arg: "from-attribute", create: infallibleParameterValue(AttributeParameterValue)},
+	}
+	var value ParameterValue
+	found := false
+	for _, source := range sources {
+		if args[source.arg] == nil {
+			continue
+		}
+		if found {
+			return ParameterValue{}, fmt.Errorf("%w: only one of 'value', 'from-header' or 'from-attribute' is allowed",
+				ErrInvalidRequestParameterFilter)
+		}
+		raw, err := shared.ConvertToString(args[source.arg])
+		if err != nil {
+			return ParameterValue{}, fmt.Errorf("failed to convert '%s' attribute: %w", source.arg, err)
+		}
+		if value, err = source.create(raw); err != nil {
+			return ParameterValue{}, fmt.Errorf("failed to convert '%s' attribute: %w", source.arg, err)
+		}
+		found = true
+	}
+	if !found {
+		return ParameterValue{}, fmt.Errorf("%w: one of 'value', 'from-header' or 'from-attribute' is required",
+			ErrInvalidRequestParameterFilter)
+	}
+	return value, nil
+}
+
+// compileParameterValue creates a ParameterValue from a literal value compiled into an Expression.
+func compileParameterValue(value string) (ParameterValue, error) {
+	expression, err := CompileExpression(value)
+	if err != nil {
+		return ParameterValue{}, err
+	}
+	return ExpressionParameterValue(expression), nil
+}
+
+func infallibleParameterValue(create func(string) ParameterValue) func(string) (ParameterValue, error) {
+	return func(name string) (ParameterValue, error) {
+		return create(name), nil
+	}
+}
+
+// AddRequestParameter is a filter that adds a parameter at the end of the request query.
+//
+// When the value comes from a missing header or attribute, the parameter is not added.
+type AddRequestParameter struct {
+	value ParameterValue
+	name  string
+}
+
+// NewAddRequestParameterFilter creates a new AddRequestParameterFilter.
+func NewAddRequestParameterFilter(name string, value ParameterValue) *AddRequestParameter {
+	return &AddRequestParameter{
+		name:  name,
+		value: value,
+	}
+}
+
+// NewAddRequestParameterBuilder creates a new AddRequestParameterBuilder.
+func NewAddRequestParameterBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		name, err := shared.ConvertToString(args["name"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'name' attribute: %w", err)
+		}
+		value, err := convertParameterValue(args)
+		if err != nil {
+			return nil, err
+		}
+		return NewAddRequestParameterFilter(name, value), nil
+	}
+}
+
+// PreProcess adds the parameter to the request query.
+func (f *AddRequestParameter) PreProcess(ctx *gateway.Context) error {
+	value, ok := f.value.resolve(ctx)
+	if !ok {
+		return nil
+	}
+	parts := splitQuery(ctx.Request.URL.RawQuery)
+	setRawQuery(ctx, append(parts, encodeQueryParameter(f.name, value)))
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *AddRequestParameter) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *AddRequestParameter) Name() string {
+	return AddRequestParameterFilterName
+}
+
+// SetRequestParameter is a filter that sets a parameter of the request query. The value of the first
+// occurrence of the parameter is replaced and the other occurrences are removed. When the parameter is
+// missing, it is added at the end of the query.
+//
+// When the value comes from a missing header or attribute, the query is left as is.
+type SetRequestParameter struct {
+	value ParameterValue
+	name  string
+}
+
+// NewSetRequestParameterFilter creates a new SetRequestParameterFilter.
+func NewSetRequestParameterFilter(name string, value ParameterValue) *SetRequestParameter {
+	return &SetRequestParameter{
+		name:  name,
+		value: value,
+	}
+}
+
+// NewSetRequestParameterBuilder creates a new SetRequestParameterBuilder.
+func NewSetRequestParameterBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		name, err := shared.ConvertToString(args["name"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'name' attribute: %w", err)
+		}
+		value, err := convertParameterValue(args)
+		if err != nil {
+			return nil, err
+		}
+		return NewSetRequestParameterFilter(name, value), nil
+	}
+}
+
+// PreProcess sets the parameter in the request query.
+func (f *SetRequestParameter) PreProcess(ctx *gateway.Context) error {
+	value, ok := f.value.resolve(ctx)
+	if !ok {
+		return nil
+	}
+	parts := splitQuery(ctx.Request.URL.RawQuery)
+	first := slices.IndexFunc(parts, f.matches)
+	if first == -1 {
+		setRawQuery(ctx, append(parts, encodeQueryParameter(f.name, value)))
+		return nil
+	}
+	parts = append(parts[:first+1], slices.DeleteFunc(parts[first+1:], f.matches)...)
+	parts[first] = encodeQueryParameter(f.name, value)
+	setRawQuery(ctx, parts)
+	return nil
+}
+
+func (f *SetRequestParameter) matches(part string) bool {
+	return queryParameterName(part) == f.name
+}
+
+// PostProcess does nothing.
+func (f *SetRequestParameter) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *SetRequestParameter) Name() string {
+	return SetRequestParameterFilterName
+}
+
+// RemoveRequestParameter is a filter that removes every occurrence of a parameter from the request query.
+type RemoveRequestParameter struct {
+	name string
+}
+
+// NewRemoveRequestParameterFilter creates a new RemoveRequestParameterFilter.
+func NewRemoveRequestParameterFilter(name string) *RemoveRequestParameter {
+	return &RemoveRequestParameter{
+		name: name,
+	}
+}
+
+// NewRemoveRequestParameterBuilder creates a new RemoveRequestParameterBuilder.
+func NewRemoveRequestParameterBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		name, err := shared.ConvertToString(args["name"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'name' attribute: %w", err)
+		}
+		return NewRemoveRequestParameterFilter(name), nil
+	}
+}
+
+// PreProcess removes the parameter from the request query.
+func (f *RemoveRequestParameter) PreProcess(ctx *gateway.Context) error {
+	parts := splitQuery(ctx.Request.URL.RawQuery)
+	setRawQuery(ctx, slices.DeleteFunc(parts, func(part string) bool {
+		return queryParameterName(part) == f.name
+	}))
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *RemoveRequestParameter) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RemoveRequestParameter) Name() string {
+	return RemoveRequestParameterFilterName
+}
+
+// RenameRequestParameter is a filter that renames every occurrence of a parameter of the request query,
+// keeping its position and its value as they are.
+type RenameRequestParameter struct {
+	from string
+	to   string
+}
+
+// NewRenameRequestParameterFilter creates a new RenameRequestParameterFilter.
+func NewRenameRequestParameterFilter(from, to string) *RenameRequestParameter {
+	return &RenameRequestParameter{
+		from: from,
+		to:   to,
+	}
+}
+
+// NewRenameRequestParameterBuilder creates a new RenameRequestParameterBuilder.
+func NewRenameRequestParameterBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		from, err := shared.ConvertToString(args["from"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'from' attribute: %w", err)
+		}
+		to, err := shared.ConvertToString(args["to"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'to' attribute: %w", err)
+		}
+		return NewRenameRequestParameterFilter(from, to), nil
+	}
+}
+
+// PreProcess renames the parameter in the request query.
+func (f *RenameRequestParameter) PreProcess(ctx *gateway.Context) error {
+	parts := splitQuery(ctx.Request.URL.RawQuery)
+	for i, part := range parts {
+		if queryParameterName(part) != f.from {
+			continue
+		}
+		parts[i] = url.QueryEscape(f.to)
+		if _, value, hasValue := strings.Cut(part, "="); hasValue {
+			parts[i] += "=" + value
+		}
+	}
+	setRawQuery(ctx, parts)
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *RenameRequestParameter) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RenameRequestParameter) Name() string {
+	return RenameRequestParameterFilterName
+}
+
+// splitQuery splits the raw query into its raw parts, like 'name=value'.
+func splitQuery(rawQuery string) []string {
+	if rawQuery == "" {
+		return nil
+	}
+	return strings.Split(rawQuery, "&")
+}
+
+// queryParameterName returns the decoded name of the raw query part.
+func queryParameterName(part string) string {
+	rawName, _, _ := strings.Cut(part, "=")
+	name, err := url.QueryUnescape(rawName)
+	if err != nil {
+		return rawName
+	}
+	return name
+}
+
+func encodeQueryParameter(name, value string) string {
+	return url.QueryEscape(name) + "=" + url.QueryEscape(value)
+}
+
+// setRawQuery sets the raw query of the request URL from its raw parts.
+//
+// The URL is shared with the inbound request: it is copied rather than modified.
+func setRawQuery(ctx *gateway.Context, parts []string) {
+	newURL := *ctx.Request.URL
+	newURL.RawQuery = strings.Join(parts, "&")
+	ctx.Request.URL = &newURL
+}
@@ -0,0 +1,257 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestRequestParameterBuilders(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		builder     gateway.FilterBuilderFunc
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "add build should succeed when value is present",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"name": "id", "value": "{id}"},
+			expectedErr: nil,
+		},
+		{
+			name:        "add build should succeed when value is from header",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"name": "user", "from-header": "X-User"},
+			expectedErr: nil,
+		},
+		{
+			name:        "set build should succeed when value is from attribute",
+			builder:     filter.NewSetRequestParameterBuilder(),
+			args:        map[string]any{"name": "tenant", "from-attribute": "TENANT"},
+			expectedErr: nil,
+		},
+		{
+			name:        "add build should fail when name argument is not valid",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"value": "1"},
+			expectedErr: errors.New("failed to convert 'name' attribute: value is required"),
+		},
+		{
+			name:        "set build should fail when name argument is not valid",
+			builder:     filter.NewSetRequestParameterBuilder(),
+			args:        map[string]any{"name": 1, "value": "1"},
+			expectedErr: errors.New("failed to convert 'name' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "add build should fail when value is missing",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"name": "id"},
+			expectedErr: errors.New("invalid request parameter filter: one of 'value', 'from-header' or 'from-attribute' is required"),
+		},
+		{
+			name:        "set build should fail when value is missing",
+			builder:     filter.NewSetRequestParameterBuilder(),
+			args:        map[string]any{"name": "id"},
+			expectedErr: errors.New("invalid request parameter filter: one of 'value', 'from-header' or 'from-attribute' is required"),
+		},
+		{
+			name:        "add build should fail when more than one value is present",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"name": "id", "value": "1", "from-header": "X-Id"},
+			expectedErr: errors.New("invalid request parameter filter: only one of 'value', 'from-header' or 'from-attribute' is allowed"),
+		},
+		{
+			name:        "add build should fail when value is not valid",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"name": "id", "from-attribute": 1},
+			expectedErr: errors.New("failed to convert 'from-attribute' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "add build should fail when value references unknown variable",
+			builder:     filter.NewAddRequestParameterBuilder(),
+			args:        map[string]any{"name": "id", "value": "${request.id}"},
+			expectedErr: errors.New("failed to convert 'value' attribute: unknown expression variable: request.id"),
+		},
+		{
+			name:        "remove build should succeed when name is present",
+			builder:     filter.NewRemoveRequestParameterBuilder(),
+			args:        map[string]any{"name": "debug"},
+			expectedErr: nil,
+		},
+		{
+			name:        "remove build should fail when name argument is not valid",
+			builder:     filter.NewRemoveRequestParameterBuilder(),
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'name' attribute: value is required"),
+		},
+		{
+			name:        "rename build should succeed when from and to are present",
+			builder:     filter.NewRenameRequestParameterBuilder(),
+			args:        map[string]any{"from": "q", "to": "query"},
+			expectedErr: nil,
+		},
+		{
+			name:        "rename build should fail when from argument is not valid",
+			builder:     filter.NewRenameRequestParameterBuilder(),
+			args:        map[string]any{"to": "query"},
+			expectedErr: errors.New("failed to convert 'from' attribute: value is required"),
+		},
+		{
+			name:        "rename build should fail when to argument is not valid",
+			builder:     filter.NewRenameRequestParameterBuilder(),
+			args:        map[string]any{"from": "q"},
+			expectedErr: errors.New("failed to convert 'to' attribute: value is required"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := tt.builder.Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRequestParameterFilters_PreProcess(t *testing.T) {
+	tests := []struct {
+		filter        gateway.Filter
+		name          string
+		query         string
+		expectedQuery string
+	}{
+		{
+			name:          "add should append parameter keeping order and encoding",
+			filter:        filter.NewAddRequestParameterFilter("id", filter.LiteralParameterValue("{id}")),
+			query:         "b=2&a=%2Fx&a=y+z",
+			expectedQuery: "b=2&a=%2Fx&a=y+z&id=42",
+		},
+		{
+			name:          "add should encode name and value",
+			filter:        filter.NewAddRequestParameterFilter("q s", filter.LiteralParameterValue("a&b=c")),
+			query:         "",
+			expectedQuery: "q+s=a%26b%3Dc",
+		},
+		{
+			name:          "add should take value from header",
+			filter:        filter.NewAddRequestParameterFilter("user", filter.HeaderParameterValue("X-User")),
+			query:         "a=1",
+			expectedQuery: "a=1&user=alice",
+		},
+		{
+			name:          "add should take value from attribute",
+			filter:        filter.NewAddRequestParameterFilter("tenant", filter.AttributeParameterValue("TENANT")),
+			query:         "a=1",
+			expectedQuery: "a=1&tenant=7",
+		},
+		{
+			name:          "add should do nothing when header is missing",
+			filter:        filter.NewAddRequestParameterFilter("user", filter.HeaderParameterValue("X-Missing")),
+			query:         "a=1",
+			expectedQuery: "a=1",
+		},
+		{
+			name:          "set should replace first occurrence and remove the others",
+			filter:        filter.NewSetRequestParameterFilter("a", filter.LiteralParameterValue("new")),
+			query:         "b=2&a=1&c=3&a=4",
+			expectedQuery: "b=2&a=new&c=3",
+		},
+		{
+			name:          "set should match encoded name",
+			filter:        filter.NewSetRequestParameterFilter("a b", filter.LiteralParameterValue("v")),
+			query:         "a%20b=1&c=%2F",
+			expectedQuery: "a+b=v&c=%2F",
+		},
+		{
+			name:          "set should append parameter when missing",
+			filter:        filter.NewSetRequestParameterFilter("a", filter.LiteralParameterValue("1")),
+			query:         "b=2",
+			expectedQuery: "b=2&a=1",
+		},
+		{
+			name:          "set should do nothing when attribute is missing",
+			filter:        filter.NewSetRequestParameterFilter("a", filter.AttributeParameterValue("MISSING")),
+			query:         "a=1",
+			expectedQuery: "a=1",
+		},
+		{
+			name:          "remove should remove every occurrence",
+			filter:        filter.NewRemoveRequestParameterFilter("debug"),
+			query:         "debug=1&a=%2F&debug&b=2",
+			expectedQuery: "a=%2F&b=2",
+		},
+		{
+			name:          "remove should do nothing when parameter is missing",
+			filter:        filter.NewRemoveRequestParameterFilter("debug"),
+			query:         "a=1&b=x+y",
+			expectedQuery: "a=1&b=x+y",
+		},
+		{
+			name:          "rename should rename every occurrence keeping raw values",
+			filter:        filter.NewRenameRequestParameterFilter("q", "query"),
+			query:         "q=a%20b&x=1&q&q=c+d",
+			expectedQuery: "query=a%20b&x=1&query&query=c+d",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "http://example.org/users/42?"+tt.query, nil)
+			req.Header.Set("X-User", "alice")
+			original := req.URL.String()
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			ctx.Attributes[gateway.PathVariablesAttr] = map[string]string{"id": "42"}
+			ctx.Attributes["TENANT"] = 7
+
+			if err := tt.filter.PreProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Request.URL.RawQuery != tt.expectedQuery {
+				t.Errorf("expected query %s actual %s", tt.expectedQuery, ctx.Request.URL.RawQuery)
+			}
+			if req.URL.String() != original {
+				t.Errorf("expected inbound url %s untouched actual %s", original, req.URL)
+			}
+		})
+	}
+}
+
+func TestRequestParameterFilters_PostProcess(t *testing.T) {
+	filters := []gateway.Filter{
+		filter.NewAddRequestParameterFilter("a", filter.LiteralParameterValue("1")),
+		filter.NewSetRequestParameterFilter("a", filter.LiteralParameterValue("1")),
+		filter.NewRemoveRequestParameterFilter("a"),
+		filter.NewRenameRequestParameterFilter("a", "b"),
+	}
+	for _, f := range filters {
+		if err := f.PostProcess(nil); err != nil {
+			t.Errorf("expected nil err actual %s", err)
+		}
+	}
+}
+
+func TestRequestParameterFilters_Name(t *testing.T) {
+	tests := []struct {
+		filter   gateway.Filter
+		expected string
+	}{
+		{filter: filter.NewAddRequestParameterFilter("a", filter.LiteralParameterValue("1")), expected: "AddRequestParameter"},
+		{filter: filter.NewSetRequestParameterFilter("a", filter.LiteralParameterValue("1")), expected: "SetRequestParameter"},
+		{filter: filter.NewRemoveRequestParameterFilter("a"), expected: "RemoveRequestParameter"},
+		{filter: filter.NewRenameRequestParameterFilter("a", "b"), expected: "RenameRequestParameter"},
+	}
+	for _, tt := range tests {
+		if tt.filter.Name() != tt.expected {
+			t.Errorf("expected %s actual %s", tt.expected, tt.filter.Name())
+		}
+	}
+}
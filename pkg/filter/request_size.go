@@ -0,0 +1,122 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrInvalidMaxSize is returned when a size limit filter is built with a max size that is not positive.
+var ErrInvalidMaxSize = errors.New("max size must be greater than zero")
+
+const (
+	// RequestSizeFilterName is the name of the request size filter.
+	RequestSizeFilterName = "RequestSize"
+
+	// ResponseSizeFilterName is the name of the response size filter.
+	ResponseSizeFilterName = "ResponseSize"
+)
+
+// RequestSize is a filter that limits the size of the request body.
+//
+// A request whose Content-Length is larger than the limit is rejected before it is sent to the backend. A
+// request of unknown length, like a chunked one, is stopped as soon as the streamed body goes over the limit.
+// Both fail with a gateway.ErrRequestTooLarge error.
+type RequestSize struct {
+	maxSize int64
+}
+
+// NewRequestSizeFilter creates a new RequestSizeFilter. The max size is in bytes.
+func NewRequestSizeFilter(maxSize int64) (*RequestSize, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("failed to build request size filter: %w: %d", ErrInvalidMaxSize, maxSize)
+	}
+	return &RequestSize{
+		maxSize: maxSize,
+	}, nil
+}
+
+// NewRequestSizeBuilder creates a new RequestSizeBuilder.
+//
+// The args are:
+// - max-size: the max size of the request body in bytes.
+func NewRequestSizeBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		maxSize, err := shared.ConvertToInt(args["max-size"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'max-size' attribute: %w", err)
+		}
+		return NewRequestSizeFilter(int64(maxSize))
+	}
+}
+
+// PreProcess limits the request body to the max size.
+func (f *RequestSize) PreProcess(ctx *gateway.Context) error {
+	if err := ctx.Request.BodyReader.Limit(f.maxSize, gateway.ErrRequestTooLarge); err != nil {
+		return fmt.Errorf("%w: content length %d is larger than %d", err, ctx.Request.BodyReader.Len(), f.maxSize)
+	}
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *RequestSize) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RequestSize) Name() string {
+	return RequestSizeFilterName
+}
+
+// ResponseSize is a filter that limits the size of the response body.
+//
+// A response whose Content-Length is larger than the limit fails with a gateway.ErrResponseTooLarge error
+// before anything is written to the client. A response of unknown length, like a chunked one, is aborted as
+// soon as the streamed body goes over the limit: the client gets a truncated response.
+type ResponseSize struct {
+	maxSize int64
+}
+
+// NewResponseSizeFilter creates a new ResponseSizeFilter. The max size is in bytes.
+func NewResponseSizeFilter(maxSize int64) (*ResponseSize, error) {
+	if maxSize <= 0 {
+		return nil, fmt.Errorf("failed to build response size filter: %w: %d", ErrInvalidMaxSize, maxSize)
+	}
+	return &ResponseSize{
+		maxSize: maxSize,
+	}, nil
+}
+
+// NewResponseSizeBuilder creates a new ResponseSizeBuilder.
+//
+// The args are:
+// - max-size: the max size of the response body in bytes.
+func NewResponseSizeBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		maxSize, err := shared.ConvertToInt(args["max-size"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'max-size' attribute: %w", err)
+		}
+		return NewResponseSizeFilter(int64(maxSize))
+	}
+}
+
+// PreProcess does nothing.
+func (f *ResponseSize) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess limits the response body to the max size.
+func (f *ResponseSize) PostProcess(ctx *gateway.Context) error {
+	if err := ctx.Response.BodyReader.Limit(f.maxSize, gateway.ErrResponseTooLarge); err != nil {
+		return fmt.Errorf("%w: content length %d is larger than %d", err, ctx.Response.BodyReader.Len(), f.maxSize)
+	}
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *ResponseSize) Name() string {
+	return ResponseSizeFilterName
+}
@@ -0,0 +1,194 @@
+package filter_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewRequestSizeBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"max-size": 1024},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when max size argument is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'max-size' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when max size argument is not positive",
+			args:        map[string]any{"max-size": 0},
+			expectedErr: errors.New("failed to build request size filter: max size must be greater than zero: 0"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewRequestSizeBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRequestSizeFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		body          io.Reader
+		expectedErr   error
+		expectedRead  error
+		name          string
+		contentLength int64
+	}{
+		{
+			name:          "pre process should succeed when content length is within the limit",
+			body:          strings.NewReader("0123"),
+			contentLength: 4,
+		},
+		{
+			name:          "pre process should fail when content length is over the limit",
+			body:          strings.NewReader("0123456789"),
+			contentLength: 10,
+			expectedErr:   errors.New("request body too large: content length 10 is larger than 4"),
+		},
+		{
+			name:          "pre process should succeed but body should fail when chunked body is over the limit",
+			body:          strings.NewReader("0123456789"),
+			contentLength: -1,
+			expectedRead:  gateway.ErrRequestTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodPost, "https://example.org/upload", tt.body)
+			req.ContentLength = tt.contentLength
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			f, _ := filter.NewRequestSizeFilter(4)
+
+			err := f.PreProcess(ctx)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err != nil {
+				if !errors.Is(err, gateway.ErrRequestTooLarge) {
+					t.Errorf("expected err to be %s", gateway.ErrRequestTooLarge)
+				}
+				return
+			}
+			if _, readErr := io.ReadAll(ctx.Request.BodyReader); !errors.Is(readErr, tt.expectedRead) {
+				t.Errorf("expected read err %s actual %s", tt.expectedRead, readErr)
+			}
+		})
+	}
+}
+
+func TestNewResponseSizeBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"max-size": 1024},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when max size argument is not valid",
+			args:        map[string]any{"max-size": "big"},
+			expectedErr: errors.New("failed to convert 'max-size' attribute: value is required to be a valid int"),
+		},
+		{
+			name:        "build should fail when max size argument is not positive",
+			args:        map[string]any{"max-size": -1},
+			expectedErr: errors.New("failed to build response size filter: max size must be greater than zero: -1"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewResponseSizeBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestResponseSizeFilter_PostProcess(t *testing.T) {
+	tests := []struct {
+		expectedErr   error
+		expectedWrite error
+		name          string
+		body          string
+		contentLength int64
+	}{
+		{
+			name:          "post process should succeed when content length is within the limit",
+			body:          "0123",
+			contentLength: 4,
+		},
+		{
+			name:          "post process should fail when content length is over the limit",
+			body:          "0123456789",
+			contentLength: 10,
+			expectedErr:   errors.New("response body too large: content length 10 is larger than 4"),
+		},
+		{
+			name:          "post process should succeed but body should fail when chunked body is over the limit",
+			body:          "0123456789",
+			contentLength: -1,
+			expectedWrite: gateway.ErrResponseTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.org/download", nil)
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			ctx.Response = gateway.NewGatewayResponse(&http.Response{
+				StatusCode:    http.StatusOK,
+				Header:        http.Header{},
+				Body:          io.NopCloser(strings.NewReader(tt.body)),
+				ContentLength: tt.contentLength,
+			})
+			f, _ := filter.NewResponseSizeFilter(4)
+
+			err := f.PostProcess(ctx)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err != nil {
+				if !errors.Is(err, gateway.ErrResponseTooLarge) {
+					t.Errorf("expected err to be %s", gateway.ErrResponseTooLarge)
+				}
+				return
+			}
+			var out bytes.Buffer
+			if _, writeErr := ctx.Response.BodyReader.WriteTo(&out); !errors.Is(writeErr, tt.expectedWrite) {
+				t.Errorf("expected write err %s actual %s", tt.expectedWrite, writeErr)
+			}
+		})
+	}
+}
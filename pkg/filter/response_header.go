@@ -1,7 +1,10 @@
 package filter
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
 
 	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -16,16 +19,46 @@ const (
 
 	// RemoveResponseHeaderFilterName is the name of the filter.
 	RemoveResponseHeaderFilterName = "RemoveResponseHeader"
+
+	// RewriteResponseHeaderFilterName is the name of the filter.
+	RewriteResponseHeaderFilterName = "RewriteResponseHeader"
+
+	// DedupeResponseHeaderFilterName is the name of the filter.
+	DedupeResponseHeaderFilterName = "DedupeResponseHeader"
+)
+
+// ErrInvalidDedupeStrategy is returned when a DedupeResponseHeader filter is built with an unknown strategy.
+var ErrInvalidDedupeStrategy = errors.New("invalid dedupe strategy")
+
+// DedupeStrategy is the strategy of the DedupeResponseHeader filter to choose the values of a header it keeps.
+type DedupeStrategy string
+
+const (
+	// RetainFirst keeps the first value of the header.
+	RetainFirst DedupeStrategy = "RETAIN_FIRST"
+
+	// RetainLast keeps the last value of the header.
+	RetainLast DedupeStrategy = "RETAIN_LAST"
+
+	// RetainUnique keeps the first occurrence of each value of the header, in order.
+	RetainUnique DedupeStrategy = "RETAIN_UNIQUE"
 )
 
 // AddResponseHeader is a filter that adds a header to the response.
+//
+// The value is an Expression, resolved for each request.
 type AddResponseHeader struct {
+	headerValue Expression
 	headerName  string
-	headerValue string
 }
 
-// NewAddResponseHeaderFilter creates a new AddResponseHeaderFilter.
+// NewAddResponseHeaderFilter creates a new AddResponseHeaderFilter. The value is a LiteralExpression.
 func NewAddResponseHeaderFilter(name, value string) *AddResponseHeader {
+	return NewAddResponseHeaderFilterWithExpression(name, LiteralExpression(value))
+}
+
+// NewAddResponseHeaderFilterWithExpression creates a new AddResponseHeaderFilter whose value is an Expression.
+func NewAddResponseHeaderFilterWithExpression(name string, value Expression) *AddResponseHeader {
 	return &AddResponseHeader{
 		headerName:  name,
 		headerValue: value,
@@ -43,7 +76,11 @@ func NewAddResponseHeaderBuilder() gateway.FilterBuilderFunc {
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert 'value' attribute: %w", err)
 		}
-		return NewAddResponseHeaderFilter(name, value), nil
+		expression, err := CompileExpression(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build add response header filter: %w", err)
+		}
+		return NewAddResponseHeaderFilterWithExpression(name, expression), nil
 	}
 }
 
@@ -54,7 +91,7 @@ func (f *AddResponseHeader) PreProcess(_ *gateway.Context) error {
 
 // PostProcess adds the header to the response.
 func (f *AddResponseHeader) PostProcess(ctx *gateway.Context) error {
-	ctx.Response.Headers.Add(f.headerName, f.headerValue)
+	ctx.Response.Headers.Add(f.headerName, f.headerValue.Expand(ctx))
 	return nil
 }
 
@@ -64,13 +101,20 @@ func (f *AddResponseHeader) Name() string {
 }
 
 // SetResponseHeader is a filter that sets a header in the response.
+//
+// The value is an Expression, resolved for each request.
 type SetResponseHeader struct {
+	headerValue Expression
 	headerName  string
-	headerValue string
 }
 
-// NewSetResponseHeaderFilter creates a new SetResponseHeaderFilter.
+// NewSetResponseHeaderFilter creates a new SetResponseHeaderFilter. The value is a LiteralExpression.
 func NewSetResponseHeaderFilter(name, value string) *SetResponseHeader {
+	return NewSetResponseHeaderFilterWithExpression(name, LiteralExpression(value))
+}
+
+// NewSetResponseHeaderFilterWithExpression creates a new SetResponseHeaderFilter whose value is an Expression.
+func NewSetResponseHeaderFilterWithExpression(name string, value Expression) *SetResponseHeader {
 	return &SetResponseHeader{
 		headerName:  name,
 		headerValue: value,
@@ -88,7 +132,11 @@ func NewSetResponseHeaderBuilder() gateway.FilterBuilderFunc {
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert 'value' attribute: %w", err)
 		}
-		return NewSetResponseHeaderFilter(name, value), nil
+		expression, err := CompileExpression(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build set response header filter: %w", err)
+		}
+		return NewSetResponseHeaderFilterWithExpression(name, expression), nil
 	}
 }
 
@@ -99,7 +147,7 @@ func (f *SetResponseHeader) PreProcess(_ *gateway.Context) error {
 
 // PostProcess sets the header in the response.
 func (f *SetResponseHeader) PostProcess(ctx *gateway.Context) error {
-	ctx.Response.Headers.Set(f.headerName, f.headerValue)
+	ctx.Response.Headers.Set(f.headerName, f.headerValue.Expand(ctx))
 	return nil
 }
 
@@ -146,3 +194,127 @@ func (f *RemoveResponseHeader) PostProcess(ctx *gateway.Context) error {
 func (f *RemoveResponseHeader) Name() string {
 	return RemoveResponseHeaderFilterName
 }
+
+// RewriteResponseHeader is a filter that replaces the matches of a regexp in the values of a response header.
+//
+// The replacement can reference the groups of the regexp as '$name' or '$\name'.
+type RewriteResponseHeader struct {
+	rewrite headerValueRewrite
+}
+
+// NewRewriteResponseHeaderFilter creates a new RewriteResponseHeaderFilter.
+func NewRewriteResponseHeaderFilter(name, regexpStr, replacement string) (*RewriteResponseHeader, error) {
+	rewrite, err := newHeaderValueRewrite(name, regexpStr, replacement)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rewrite response header filter: %w", err)
+	}
+	return &RewriteResponseHeader{
+		rewrite: rewrite,
+	}, nil
+}
+
+// NewRewriteResponseHeaderBuilder creates a new RewriteResponseHeaderBuilder.
+func NewRewriteResponseHeaderBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		name, regexpStr, replacement, err := convertHeaderRewriteArgs(args)
+		if err != nil {
+			return nil, err
+		}
+		return NewRewriteResponseHeaderFilter(name, regexpStr, replacement)
+	}
+}
+
+// PreProcess does nothing.
+func (f *RewriteResponseHeader) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess rewrites the values of the response header.
+func (f *RewriteResponseHeader) PostProcess(ctx *gateway.Context) error {
+	f.rewrite.apply(ctx.Response.Headers)
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RewriteResponseHeader) Name() string {
+	return RewriteResponseHeaderFilterName
+}
+
+// DedupeResponseHeader is a filter that removes the duplicated values of response headers, like the CORS or
+// cache headers both the gateway and the backend set.
+type DedupeResponseHeader struct {
+	strategy DedupeStrategy
+	names    []string
+}
+
+// NewDedupeResponseHeaderFilter creates a new DedupeResponseHeaderFilter.
+//
+// The strategy defaults to RetainFirst.
+func NewDedupeResponseHeaderFilter(strategy DedupeStrategy, names ...string) (*DedupeResponseHeader, error) {
+	switch strategy {
+	case "":
+		strategy = RetainFirst
+	case RetainFirst, RetainLast, RetainUnique:
+	default:
+		return nil, fmt.Errorf("failed to build dedupe response header filter: %w: %s is not one of %s, %s or %s",
+			ErrInvalidDedupeStrategy, strategy, RetainFirst, RetainLast, RetainUnique)
+	}
+	return &DedupeResponseHeader{
+		strategy: strategy,
+		names:    names,
+	}, nil
+}
+
+// NewDedupeResponseHeaderBuilder creates a new DedupeResponseHeaderBuilder.
+func NewDedupeResponseHeaderBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		names, err := shared.ConvertToStringSlice(args["names"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'names' attribute: %w", err)
+		}
+		strategy, err := convertOptionalString(args, "strategy")
+		if err != nil {
+			return nil, err
+		}
+		return NewDedupeResponseHeaderFilter(DedupeStrategy(strategy), names...)
+	}
+}
+
+// PreProcess does nothing.
+func (f *DedupeResponseHeader) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess removes the duplicated values of the response headers.
+func (f *DedupeResponseHeader) PostProcess(ctx *gateway.Context) error {
+	for _, name := range f.names {
+		values := ctx.Response.Headers.Values(name)
+		if len(values) < 2 { //nolint:mnd // a single value has no duplicates
+			continue
+		}
+		ctx.Response.Headers[http.CanonicalHeaderKey(name)] = f.dedupe(values)
+	}
+	return nil
+}
+
+func (f *DedupeResponseHeader) dedupe(values []string) []string {
+	switch f.strategy {
+	case RetainLast:
+		return []string{values[len(values)-1]}
+	case RetainUnique:
+		unique := make([]string, 0, len(values))
+		for _, value := range values {
+			if !slices.Contains(unique, value) {
+				unique = append(unique, value)
+			}
+		}
+		return unique
+	default:
+		return []string{values[0]}
+	}
+}
+
+// Name returns the name of the filter.
+func (f *DedupeResponseHeader) Name() string {
+	return DedupeResponseHeaderFilterName
+}
@@ -39,6 +39,15 @@ func TestNewAddResponseHeaderBuilder(t *testing.T) {
 			},
 			expectedErr: errors.New("failed to convert 'value' attribute: value is required"),
 		},
+		{
+			name: "build should fail when value references unknown variable",
+			args: map[string]any{
+				"name":  "First",
+				"value": "${request.unknown}",
+			},
+			expectedErr: errors.New(
+				"failed to build add response header filter: unknown expression variable: request.unknown"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -149,6 +158,15 @@ func TestNewSetResponseHeaderBuilder(t *testing.T) {
 			},
 			expectedErr: errors.New("failed to convert 'value' attribute: value is required"),
 		},
+		{
+			name: "build should fail when value references unknown variable",
+			args: map[string]any{
+				"name":  "First",
+				"value": "${request.unknown}",
+			},
+			expectedErr: errors.New(
+				"failed to build set response header filter: unknown expression variable: request.unknown"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -329,3 +347,196 @@ func TestRemoveResponseHeaderFilter_PreProcess(t *testing.T) {
 		t.Errorf("expected nil err actual %s", err)
 	}
 }
+
+func TestNewRewriteResponseHeaderBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"name": "Location", "regexp": "^http:", "replacement": "https:"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when name argument is not present",
+			args:        map[string]any{"regexp": "a", "replacement": "b"},
+			expectedErr: errors.New("failed to convert 'name' attribute: value is required"),
+		},
+		{
+			name: "build should fail when regexp argument is not valid",
+			args: map[string]any{"name": "Location", "regexp": "(", "replacement": "b"},
+			expectedErr: errors.New(
+				"failed to build rewrite response header filter: error parsing regexp: missing closing ): `(`"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewRewriteResponseHeaderBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRewriteResponseHeaderFilter_Name(t *testing.T) {
+	expected := "RewriteResponseHeader"
+
+	f, _ := filter.NewRewriteResponseHeaderFilter("Location", "a", "b")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
+
+func TestRewriteResponseHeaderFilter_PostProcess(t *testing.T) {
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+	ctx.Response = gateway.NewGatewayResponse(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     map[string][]string{"X-Backend": {"backend-1.internal:8080"}},
+	})
+	f, _ := filter.NewRewriteResponseHeaderFilter("x-backend", `^([^.]+)\.internal:\d+$`, "$1")
+
+	_ = f.PostProcess(ctx)
+
+	if actual := ctx.Response.Headers["X-Backend"]; !slices.Equal(actual, []string{"backend-1"}) {
+		t.Errorf("expected %v actual %v", []string{"backend-1"}, actual)
+	}
+}
+
+func TestRewriteResponseHeaderFilter_PreProcess(t *testing.T) {
+	f, _ := filter.NewRewriteResponseHeaderFilter("Location", "a", "b")
+	if err := f.PreProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestNewDedupeResponseHeaderBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when strategy argument is not present",
+			args:        map[string]any{"names": []any{"Access-Control-Allow-Origin"}},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"names": []any{"Vary"}, "strategy": "RETAIN_UNIQUE"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when names argument is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'names' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when strategy argument is not valid",
+			args:        map[string]any{"names": []any{"Vary"}, "strategy": 1},
+			expectedErr: errors.New("failed to convert 'strategy' attribute: value is required to be a valid string"),
+		},
+		{
+			name: "build should fail when strategy argument is unknown",
+			args: map[string]any{"names": []any{"Vary"}, "strategy": "RETAIN_ALL"},
+			expectedErr: errors.New("failed to build dedupe response header filter: invalid dedupe strategy: " +
+				"RETAIN_ALL is not one of RETAIN_FIRST, RETAIN_LAST or RETAIN_UNIQUE"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewDedupeResponseHeaderBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestDedupeResponseHeaderFilter_Name(t *testing.T) {
+	expected := "DedupeResponseHeader"
+
+	f, _ := filter.NewDedupeResponseHeaderFilter(filter.RetainFirst, "Vary")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
+
+func TestDedupeResponseHeaderFilter_PostProcess(t *testing.T) {
+	tests := []struct {
+		expectedHeaders http.Header
+		name            string
+		strategy        filter.DedupeStrategy
+	}{
+		{
+			name:     "dedupe response header should retain first value by default",
+			strategy: "",
+			expectedHeaders: map[string][]string{
+				"Access-Control-Allow-Origin": {"https://a.example.org"},
+				"Vary":                        {"Origin"},
+				"Cache-Control":               {"no-store"},
+			},
+		},
+		{
+			name:     "dedupe response header should retain last value",
+			strategy: filter.RetainLast,
+			expectedHeaders: map[string][]string{
+				"Access-Control-Allow-Origin": {"*"},
+				"Vary":                        {"Accept-Encoding"},
+				"Cache-Control":               {"no-store"},
+			},
+		},
+		{
+			name:     "dedupe response header should retain unique values",
+			strategy: filter.RetainUnique,
+			expectedHeaders: map[string][]string{
+				"Access-Control-Allow-Origin": {"https://a.example.org", "*"},
+				"Vary":                        {"Origin", "Accept-Encoding"},
+				"Cache-Control":               {"no-store"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			ctx.Response = gateway.NewGatewayResponse(&http.Response{
+				StatusCode: http.StatusOK,
+				Header: map[string][]string{
+					"Access-Control-Allow-Origin": {"https://a.example.org", "https://a.example.org", "*"},
+					"Vary":                        {"Origin", "Origin", "Accept-Encoding"},
+					"Cache-Control":               {"no-store"},
+				},
+			})
+			f, _ := filter.NewDedupeResponseHeaderFilter(tt.strategy, "access-control-allow-origin", "Vary")
+
+			_ = f.PostProcess(ctx)
+
+			for k, valueList := range tt.expectedHeaders {
+				if !slices.Equal(valueList, ctx.Response.Headers[k]) {
+					t.Errorf("expected %s %v actual %v", k, valueList, ctx.Response.Headers[k])
+				}
+			}
+		})
+	}
+}
+
+func TestDedupeResponseHeaderFilter_PreProcess(t *testing.T) {
+	f, _ := filter.NewDedupeResponseHeaderFilter(filter.RetainFirst, "Vary")
+	if err := f.PreProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
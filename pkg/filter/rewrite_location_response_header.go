@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+const (
+	// RewriteLocationResponseHeaderFilterName is the name of the filter.
+	RewriteLocationResponseHeaderFilterName = "RewriteLocationResponseHeader"
+
+	defaultLocationHeader = "Location"
+)
+
+// RewriteLocationResponseHeader is a filter that rewrites a location header of the response, like Location
+// or Content-Location, pointing to the backend, so that it points to the gateway.
+//
+// An absolute location whose host and port are the ones of the route URI gets the scheme and host the client
+// used, unless they are configured. The path of a location to the backend, absolute or relative to the root,
+// gets the prefix of the path the client requested back, when the path filters changed it: a location
+// '/users/42' of a request to '/api/users' whose '/api' prefix was stripped becomes '/api/users/42'.
+//
+// The locations pointing to other hosts are left as is.
+type RewriteLocationResponseHeader struct {
+	header string
+	host   string
+	scheme string
+}
+
+// NewRewriteLocationResponseHeaderFilter creates a new RewriteLocationResponseHeaderFilter.
+//
+// The header defaults to Location. An empty host or scheme is the one of the request the client sent.
+func NewRewriteLocationResponseHeaderFilter(header, host, scheme string) *RewriteLocationResponseHeader {
+	if header == "" {
+		header = defaultLocationHeader
+	}
+	return &RewriteLocationResponseHeader{
+		header: header,
+		host:   host,
+		scheme: scheme,
+	}
+}
+
+// NewRewriteLocationResponseHeaderBuilder creates a new RewriteLocationResponseHeaderBuilder.
+func NewRewriteLocationResponseHeaderBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		header, err := convertOptionalString(args, "header")
+		if err != nil {
+			return nil, err
+		}
+		host, err := convertOptionalString(args, "host")
+		if err != nil {
+			return nil, err
+		}
+		scheme, err := convertOptionalString(args, "scheme")
+		if err != nil {
+			return nil, err
+		}
+		return NewRewriteLocationResponseHeaderFilter(header, host, scheme), nil
+	}
+}
+
+// PreProcess does nothing.
+func (f *RewriteLocationResponseHeader) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess rewrites the location header of the response.
+//
+// A location that cannot be parsed is left as is.
+func (f *RewriteLocationResponseHeader) PostProcess(ctx *gateway.Context) error {
+	value := ctx.Response.Headers.Get(f.header)
+	if value == "" {
+		return nil
+	}
+	location, err := url.Parse(value)
+	if err != nil {
+		return nil //nolint:nilerr // a location the gateway does not understand is not its to rewrite
+	}
+	if location.Host != "" {
+		if !isBackendLocation(&ctx.Route.URI, location) {
+			return nil
+		}
+		location.Scheme = f.publicScheme(ctx)
+		location.Host = f.publicHost(ctx)
+	}
+	if location.Host != "" || strings.HasPrefix(location.Path, "/") {
+		backendPrefix, publicPrefix := pathPrefixes(ctx)
+		if path, ok := mapPathPrefix(location.EscapedPath(), backendPrefix, publicPrefix); ok {
+			// both prefixes come from escaped paths: the mapped path is always valid
+			_ = setURLEscapedPath(location, path)
+		}
+	}
+	ctx.Response.Headers.Set(f.header, location.String())
+	return nil
+}
+
+func (f *RewriteLocationResponseHeader) publicScheme(ctx *gateway.Context) string {
+	if f.scheme != "" {
+		return f.scheme
+	}
+	return ctx.Request.Scheme
+}
+
+func (f *RewriteLocationResponseHeader) publicHost(ctx *gateway.Context) string {
+	if f.host != "" {
+		return f.host
+	}
+	return ctx.Request.Host
+}
+
+// Name returns the name of the filter.
+func (f *RewriteLocationResponseHeader) Name() string {
+	return RewriteLocationResponseHeaderFilterName
+}
+
+// isBackendLocation returns whether the absolute location points to the backend of the route. A location
+// without a port uses the default port of its scheme, or of the backend scheme when it has none.
+func isBackendLocation(backend, location *url.URL) bool {
+	if !strings.EqualFold(backend.Hostname(), location.Hostname()) {
+		return false
+	}
+	scheme := location.Scheme
+	if scheme == "" {
+		scheme = backend.Scheme
+	}
+	return urlPort(backend.Scheme, backend) == urlPort(scheme, location)
+}
+
+func urlPort(scheme string, u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	switch strings.ToLower(scheme) {
+	case "http":
+		return "80"
+	case "https":
+		return "443"
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,213 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewRewriteLocationResponseHeaderBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are not present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"header": "Content-Location", "host": "api.example.org", "scheme": "https"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when header argument is not valid",
+			args:        map[string]any{"header": 1},
+			expectedErr: errors.New("failed to convert 'header' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when host argument is not valid",
+			args:        map[string]any{"host": 1},
+			expectedErr: errors.New("failed to convert 'host' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when scheme argument is not valid",
+			args:        map[string]any{"scheme": 1},
+			expectedErr: errors.New("failed to convert 'scheme' attribute: value is required to be a valid string"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewRewriteLocationResponseHeaderBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+// newBackendResponseContext returns the context of a request to the target proxied to the backend after
+// the path filters ran, with a response holding the headers.
+func newBackendResponseContext(
+	t *testing.T, target, backend string, headers http.Header, pathFilters ...gateway.Filter,
+) *gateway.Context {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, target, nil)
+	backendURL, _ := url.Parse(backend)
+	ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{URI: *backendURL}, gateway.NewGatewayRequest(req))
+	for _, pathFilter := range pathFilters {
+		if err := pathFilter.PreProcess(ctx); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	ctx.Response = gateway.NewStaticGatewayResponse(http.StatusFound, headers, "")
+	return ctx
+}
+
+func TestRewriteLocationResponseHeaderFilter_PostProcess(t *testing.T) {
+	stripPrefix, _ := filter.NewStripPrefixFilter(1)
+	prefixPath, _ := filter.NewPrefixPathFilter("/v1")
+	tests := []struct {
+		filter      *filter.RewriteLocationResponseHeader
+		name        string
+		target      string
+		location    string
+		expected    string
+		pathFilters []gateway.Filter
+	}{
+		{
+			name:     "post process should rewrite backend host to public host",
+			filter:   filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:   "http://public.example.org/users",
+			location: "http://backend:8080/users/42?tab=profile",
+			expected: "http://public.example.org/users/42?tab=profile",
+		},
+		{
+			name:        "post process should put stripped prefix back",
+			filter:      filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:      "http://public.example.org:8443/api/users",
+			location:    "http://backend:8080/users/42",
+			expected:    "http://public.example.org:8443/api/users/42",
+			pathFilters: []gateway.Filter{stripPrefix},
+		},
+		{
+			name:        "post process should remove added prefix",
+			filter:      filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:      "http://public.example.org/users",
+			location:    "/v1/users/42",
+			expected:    "/users/42",
+			pathFilters: []gateway.Filter{prefixPath},
+		},
+		{
+			name:        "post process should put stripped prefix back in relative location",
+			filter:      filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:      "http://public.example.org/api/users",
+			location:    "/users/42",
+			expected:    "/api/users/42",
+			pathFilters: []gateway.Filter{stripPrefix},
+		},
+		{
+			name:        "post process should keep path outside backend prefix",
+			filter:      filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:      "http://public.example.org/users",
+			location:    "/other/42",
+			expected:    "/other/42",
+			pathFilters: []gateway.Filter{prefixPath},
+		},
+		{
+			name:     "post process should match backend host in any case",
+			filter:   filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:   "http://public.example.org/users",
+			location: "http://BACKEND:8080/users",
+			expected: "http://public.example.org/users",
+		},
+		{
+			name:     "post process should keep other hosts",
+			filter:   filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:   "http://public.example.org/users",
+			location: "https://login.example.org/authorize",
+			expected: "https://login.example.org/authorize",
+		},
+		{
+			name:     "post process should keep backend host with other port",
+			filter:   filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:   "http://public.example.org/users",
+			location: "http://backend:9090/users",
+			expected: "http://backend:9090/users",
+		},
+		{
+			name:     "post process should use configured host and scheme",
+			filter:   filter.NewRewriteLocationResponseHeaderFilter("", "api.example.org", "https"),
+			target:   "http://public.example.org/users",
+			location: "http://backend:8080/users/42",
+			expected: "https://api.example.org/users/42",
+		},
+		{
+			name:        "post process should keep encoded slash",
+			filter:      filter.NewRewriteLocationResponseHeaderFilter("", "", ""),
+			target:      "http://public.example.org/api/files",
+			location:    "/files/a%2Fb",
+			expected:    "/api/files/a%2Fb",
+			pathFilters: []gateway.Filter{stripPrefix},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := newBackendResponseContext(t, tt.target, "http://backend:8080",
+				http.Header{"Location": {tt.location}}, tt.pathFilters...)
+
+			if err := tt.filter.PostProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if actual := ctx.Response.Headers.Get("Location"); actual != tt.expected {
+				t.Errorf("expected location %s actual %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRewriteLocationResponseHeaderFilter_PostProcessOtherHeader(t *testing.T) {
+	f := filter.NewRewriteLocationResponseHeaderFilter("Content-Location", "", "")
+	ctx := newBackendResponseContext(t, "http://public.example.org/users", "http://backend",
+		http.Header{"Content-Location": {"http://backend/users/42"}, "Location": {"http://backend/users/7"}})
+
+	if err := f.PostProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if actual := ctx.Response.Headers.Get("Content-Location"); actual != "http://public.example.org/users/42" {
+		t.Errorf("expected content location rewritten actual %s", actual)
+	}
+	if actual := ctx.Response.Headers.Get("Location"); actual != "http://backend/users/7" {
+		t.Errorf("expected location untouched actual %s", actual)
+	}
+}
+
+func TestRewriteLocationResponseHeaderFilter_PreProcess(t *testing.T) {
+	f := filter.NewRewriteLocationResponseHeaderFilter("", "", "")
+	if err := f.PreProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestRewriteLocationResponseHeaderFilter_Name(t *testing.T) {
+	expected := "RewriteLocationResponseHeader"
+
+	f := filter.NewRewriteLocationResponseHeaderFilter("", "", "")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
@@ -13,10 +13,11 @@ import (
 // RewritePathFilterName is the name of the filter.
 const RewritePathFilterName = "RewritePath"
 
-// GatewayOriginalRequestAttr is the name of the attribute that contains the original request URL.
-const GatewayOriginalRequestAttr = "GATEWAY_ORIGINAL_REQUEST_URL"
-
 // RewritePath is a filter that rewrites the path of the request.
+//
+// The regexp is matched against the decoded path. When the request path holds encoded characters the decoded
+// path cannot tell apart, like an encoded slash, the same rewrite is applied to the escaped path, which is kept
+// as long as it still encodes the rewritten path.
 type RewritePath struct {
 	pattern     *regexp.Regexp
 	Regexp      string
@@ -55,9 +56,10 @@ func NewRewritePathBuilder() gateway.FilterBuilderFunc {
 // PreProcess rewrites the path of the request.
 // If the path does not match the regexp, the filter will do nothing.
 // If the path matches the regexp, the filter will rewrite the path.
-// The original request URL is stored in the context as an attribute with the name GatewayOriginalRequestAttr.
+// The original request URL is stored in the context as an attribute with the name GatewayOriginalRequestAttr,
+// unless a previous filter already stored it.
 func (f *RewritePath) PreProcess(ctx *gateway.Context) error {
-	ctx.Attributes[GatewayOriginalRequestAttr] = ctx.Request.URL
+	recordOriginalURL(ctx)
 	currentPath := ctx.Request.URL.Path
 	if !f.pattern.MatchString(currentPath) {
 		return nil
@@ -66,14 +68,17 @@ func (f *RewritePath) PreProcess(ctx *gateway.Context) error {
 	if currentPath == newPath {
 		return nil
 	}
-	newURL := &url.URL{
-		Scheme:   ctx.Request.URL.Scheme,
-		Host:     ctx.Request.URL.Host,
-		Path:     newPath,
-		RawQuery: ctx.Request.URL.RawQuery,
-		Fragment: ctx.Request.URL.Fragment,
+	// The URL is shared with the inbound request: it is copied rather than modified.
+	newURL := *ctx.Request.URL
+	newURL.Path = newPath
+	newURL.RawPath = ""
+	if ctx.Request.URL.RawPath != "" {
+		newRawPath := f.pattern.ReplaceAllString(ctx.Request.URL.EscapedPath(), f.Replacement)
+		if unescaped, err := url.PathUnescape(newRawPath); err == nil && unescaped == newPath {
+			newURL.RawPath = newRawPath
+		}
 	}
-	ctx.Request.URL = newURL
+	ctx.Request.URL = &newURL
 	return nil
 }
 
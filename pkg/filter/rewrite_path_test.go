@@ -72,6 +72,7 @@ func TestRewritePathFilter_PreProcess(t *testing.T) {
 		pattern     string
 		replacement string
 		expected    string
+		expectedRaw string
 	}{
 		{
 			name:        "rewrite should succeed when pattern matches",
@@ -87,6 +88,42 @@ func TestRewritePathFilter_PreProcess(t *testing.T) {
 			replacement: "/api/$\\{segment}",
 			expected:    "/v2/customer/person1",
 		},
+		{
+			name:        "rewrite should keep encoded slash",
+			path:        "/v1/files/a%2Fb",
+			pattern:     "/v1/files/(?<segment>.*)",
+			replacement: "/api/$\\{segment}",
+			expected:    "/api/a/b",
+			expectedRaw: "/api/a%2Fb",
+		},
+		{
+			name:        "rewrite should match decoded characters",
+			path:        "/v1/caf%C3%A9%20bar",
+			pattern:     "/v1/café bar",
+			replacement: "/api/cafe",
+			expected:    "/api/cafe",
+		},
+		{
+			name:        "rewrite should capture decoded characters",
+			path:        "/v1/customer/john%20doe",
+			pattern:     "/v1/customer/(?<segment>.*)",
+			replacement: "/api/$\\{segment}",
+			expected:    "/api/john doe",
+		},
+		{
+			name:        "rewrite should allow a percent sign in the replacement",
+			path:        "/v1/discount",
+			pattern:     "/v1/(?<segment>.*)",
+			replacement: "/api/100%/$\\{segment}",
+			expected:    "/api/100%/discount",
+		},
+		{
+			name:        "rewrite should drop the escaped path when it no longer encodes the path",
+			path:        "/v1/files/a%2Fb",
+			pattern:     "/v1/files/a/b",
+			replacement: "/api/file",
+			expected:    "/api/file",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -97,8 +134,9 @@ func TestRewritePathFilter_PreProcess(t *testing.T) {
 
 			_ = f.PreProcess(ctx)
 
-			if tt.expected != ctx.Request.URL.Path {
-				t.Errorf("expected %s actual %s", tt.expected, ctx.Request.URL.Path)
+			if tt.expected != ctx.Request.URL.Path || tt.expectedRaw != ctx.Request.URL.RawPath {
+				t.Errorf("expected %s %s actual %s %s", tt.expected, tt.expectedRaw,
+					ctx.Request.URL.Path, ctx.Request.URL.RawPath)
 			}
 			if ctx.Attributes[filter.GatewayOriginalRequestAttr] != req.URL {
 				t.Errorf("expected cached original URL %s actual %s",
@@ -0,0 +1,208 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrInvalidSameSite is returned when a RewriteSetCookie filter is built with an unknown SameSite value.
+var ErrInvalidSameSite = errors.New("invalid same site")
+
+// RewriteSetCookieFilterName is the name of the filter.
+const RewriteSetCookieFilterName = "RewriteSetCookie"
+
+const setCookieHeader = "Set-Cookie"
+
+// CookieRewrite holds the attributes the RewriteSetCookie filter sets on the response cookies.
+//
+// A nil Domain rewrites a domain that is the host of the route URI to the host the client used, and an empty
+// one removes the attribute. A nil Path gives a path to the backend the prefix of the path the client
+// requested back, when the path filters changed it, like the RewriteLocationResponseHeader filter does. A nil
+// Secure leaves the attribute as is. An empty SameSite leaves the attribute as is, otherwise it is one of
+// Strict, Lax or None.
+type CookieRewrite struct {
+	Domain   *string
+	Path     *string
+	Secure   *bool
+	SameSite string
+}
+
+// RewriteSetCookie is a filter that rewrites the Domain, Path, Secure and SameSite attributes of the cookies
+// the backend sets, so that the browser sends them back to the gateway. The other attributes are left as
+// they are.
+type RewriteSetCookie struct {
+	rewrite CookieRewrite
+}
+
+// NewRewriteSetCookieFilter creates a new RewriteSetCookieFilter.
+func NewRewriteSetCookieFilter(rewrite CookieRewrite) (*RewriteSetCookie, error) {
+	if rewrite.SameSite != "" {
+		sameSite, err := canonicalSameSite(rewrite.SameSite)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build rewrite set cookie filter: %w", err)
+		}
+		rewrite.SameSite = sameSite
+	}
+	return &RewriteSetCookie{
+		rewrite: rewrite,
+	}, nil
+}
+
+// NewRewriteSetCookieBuilder creates a new RewriteSetCookieBuilder.
+func NewRewriteSetCookieBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		var rewrite CookieRewrite
+		if args["domain"] != nil {
+			domain, err := convertOptionalString(args, "domain")
+			if err != nil {
+				return nil, err
+			}
+			rewrite.Domain = &domain
+		}
+		if args["path"] != nil {
+			path, err := convertOptionalString(args, "path")
+			if err != nil {
+				return nil, err
+			}
+			rewrite.Path = &path
+		}
+		if args["secure"] != nil {
+			secure, err := convertOptionalBool(args, "secure")
+			if err != nil {
+				return nil, err
+			}
+			rewrite.Secure = &secure
+		}
+		sameSite, err := convertOptionalString(args, "same-site")
+		if err != nil {
+			return nil, err
+		}
+		rewrite.SameSite = sameSite
+		return NewRewriteSetCookieFilter(rewrite)
+	}
+}
+
+// PreProcess does nothing.
+func (f *RewriteSetCookie) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess rewrites the Set-Cookie headers of the response.
+func (f *RewriteSetCookie) PostProcess(ctx *gateway.Context) error {
+	cookies := ctx.Response.Headers.Values(setCookieHeader)
+	if len(cookies) == 0 {
+		return nil
+	}
+	backendPrefix, publicPrefix := pathPrefixes(ctx)
+	rewriter := cookieRewriter{
+		rewrite:       f.rewrite,
+		backendHost:   ctx.Route.URI.Hostname(),
+		publicHost:    hostname(ctx.Request.Host),
+		backendPrefix: backendPrefix,
+		publicPrefix:  publicPrefix,
+	}
+	rewritten := make([]string, 0, len(cookies))
+	for _, cookie := range cookies {
+		rewritten = append(rewritten, rewriter.rewriteCookie(cookie))
+	}
+	ctx.Response.Headers[setCookieHeader] = rewritten
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *RewriteSetCookie) Name() string {
+	return RewriteSetCookieFilterName
+}
+
+type cookieRewriter struct {
+	rewrite       CookieRewrite
+	backendHost   string
+	publicHost    string
+	backendPrefix string
+	publicPrefix  string
+}
+
+// rewriteCookie rewrites the attributes of the Set-Cookie value, keeping the name and value, the other
+// attributes and their order.
+func (r cookieRewriter) rewriteCookie(cookie string) string {
+	parts := strings.Split(cookie, ";")
+	attributes := make([]string, 0, len(parts)+3)
+	attributes = append(attributes, strings.TrimSpace(parts[0]))
+	seen := make(map[string]bool, len(parts))
+	for _, part := range parts[1:] {
+		attribute := strings.TrimSpace(part)
+		if attribute == "" {
+			continue
+		}
+		rawName, value, _ := strings.Cut(attribute, "=")
+		name := strings.ToLower(strings.TrimSpace(rawName))
+		seen[name] = true
+		if rewritten, keep := r.rewriteAttribute(name, strings.TrimSpace(value), attribute); keep {
+			attributes = append(attributes, rewritten)
+		}
+	}
+	if r.rewrite.Domain != nil && *r.rewrite.Domain != "" && !seen["domain"] {
+		attributes = append(attributes, "Domain="+*r.rewrite.Domain)
+	}
+	if r.rewrite.Path != nil && *r.rewrite.Path != "" && !seen["path"] {
+		attributes = append(attributes, "Path="+*r.rewrite.Path)
+	}
+	if r.rewrite.Secure != nil && *r.rewrite.Secure && !seen["secure"] {
+		attributes = append(attributes, "Secure")
+	}
+	if r.rewrite.SameSite != "" {
+		attributes = append(attributes, "SameSite="+r.rewrite.SameSite)
+	}
+	return strings.Join(attributes, "; ")
+}
+
+// rewriteAttribute returns the rewritten attribute, and false when the attribute is removed.
+func (r cookieRewriter) rewriteAttribute(name, value, attribute string) (string, bool) {
+	switch name {
+	case "domain":
+		if r.rewrite.Domain != nil {
+			return "Domain=" + *r.rewrite.Domain, *r.rewrite.Domain != ""
+		}
+		if strings.EqualFold(strings.TrimPrefix(value, "."), r.backendHost) && r.publicHost != "" {
+			return "Domain=" + r.publicHost, true
+		}
+		return attribute, true
+	case "path":
+		if r.rewrite.Path != nil {
+			return "Path=" + *r.rewrite.Path, *r.rewrite.Path != ""
+		}
+		if path, ok := mapPathPrefix(value, r.backendPrefix, r.publicPrefix); ok && strings.HasPrefix(path, "/") {
+			return "Path=" + path, true
+		}
+		return attribute, true
+	case "secure":
+		return attribute, r.rewrite.Secure == nil || *r.rewrite.Secure
+	case "samesite":
+		// the configured value is added at the end
+		return attribute, r.rewrite.SameSite == ""
+	default:
+		return attribute, true
+	}
+}
+
+// canonicalSameSite returns the SameSite value with its canonical case.
+func canonicalSameSite(value string) (string, error) {
+	for _, sameSite := range []string{"Strict", "Lax", "None"} {
+		if strings.EqualFold(value, sameSite) {
+			return sameSite, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s is not one of Strict, Lax or None", ErrInvalidSameSite, value)
+}
+
+// hostname returns the host without its port.
+func hostname(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+	return host
+}
@@ -0,0 +1,154 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewRewriteSetCookieBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are not present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"domain": "", "path": "/api", "secure": true, "same-site": "lax"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when domain argument is not valid",
+			args:        map[string]any{"domain": 1},
+			expectedErr: errors.New("failed to convert 'domain' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when path argument is not valid",
+			args:        map[string]any{"path": 1},
+			expectedErr: errors.New("failed to convert 'path' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when secure argument is not valid",
+			args:        map[string]any{"secure": "yes"},
+			expectedErr: errors.New("failed to convert 'secure' attribute: value is required to be a valid bool"),
+		},
+		{
+			name: "build should fail when same site argument is not valid",
+			args: map[string]any{"same-site": "Sometimes"},
+			expectedErr: errors.New(
+				"failed to build rewrite set cookie filter: invalid same site: Sometimes is not one of Strict, Lax or None"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewRewriteSetCookieBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRewriteSetCookieFilter_PostProcess(t *testing.T) {
+	stripPrefix, _ := filter.NewStripPrefixFilter(1)
+	tests := []struct {
+		name        string
+		target      string
+		cookies     []string
+		expected    []string
+		pathFilters []gateway.Filter
+		rewrite     filter.CookieRewrite
+	}{
+		{
+			name:     "post process should rewrite backend domain to public host",
+			target:   "http://public.example.org:8443/users",
+			cookies:  []string{"session=abc; Domain=.backend; Path=/; HttpOnly"},
+			expected: []string{"session=abc; Domain=public.example.org; Path=/; HttpOnly"},
+		},
+		{
+			name:     "post process should keep other domains",
+			target:   "http://public.example.org/users",
+			cookies:  []string{"session=abc; Domain=example.org"},
+			expected: []string{"session=abc; Domain=example.org"},
+		},
+		{
+			name:        "post process should put stripped prefix back in path",
+			target:      "http://public.example.org/api/users",
+			cookies:     []string{"session=abc; Path=/users", "theme=dark; Path=/"},
+			expected:    []string{"session=abc; Path=/api/users", "theme=dark; Path=/api/"},
+			pathFilters: []gateway.Filter{stripPrefix},
+		},
+		{
+			name:     "post process should set configured attributes",
+			target:   "http://public.example.org/users",
+			cookies:  []string{"session=abc; Domain=backend; path=/users; SameSite=None; Max-Age=60"},
+			expected: []string{"session=abc; Domain=example.org; Path=/; Max-Age=60; Secure; SameSite=Lax"},
+			rewrite: filter.CookieRewrite{
+				Domain: new("example.org"), Path: new("/"), Secure: new(true), SameSite: "lax",
+			},
+		},
+		{
+			name:     "post process should add configured attributes when missing",
+			target:   "http://public.example.org/users",
+			cookies:  []string{"session=abc"},
+			expected: []string{"session=abc; Domain=example.org; Path=/"},
+			rewrite:  filter.CookieRewrite{Domain: new("example.org"), Path: new("/")},
+		},
+		{
+			name:     "post process should remove attributes configured empty or not secure",
+			target:   "http://public.example.org/users",
+			cookies:  []string{`session="a b"; Domain=backend; Secure; HttpOnly`},
+			expected: []string{`session="a b"; HttpOnly`},
+			rewrite:  filter.CookieRewrite{Domain: new(""), Secure: new(false)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := filter.NewRewriteSetCookieFilter(tt.rewrite)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			ctx := newBackendResponseContext(t, tt.target, "http://backend:8080",
+				http.Header{"Set-Cookie": tt.cookies}, tt.pathFilters...)
+
+			if err = f.PostProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if actual := ctx.Response.Headers.Values("Set-Cookie"); !reflect.DeepEqual(actual, tt.expected) {
+				t.Errorf("expected cookies %v actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRewriteSetCookieFilter_PreProcess(t *testing.T) {
+	f, _ := filter.NewRewriteSetCookieFilter(filter.CookieRewrite{})
+	if err := f.PreProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestRewriteSetCookieFilter_Name(t *testing.T) {
+	expected := "RewriteSetCookie"
+
+	f, _ := filter.NewRewriteSetCookieFilter(filter.CookieRewrite{})
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
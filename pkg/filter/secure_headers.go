@@ -0,0 +1,134 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrUnknownSecureHeader is returned when a SecureHeaders filter is built with a header it does not set.
+var ErrUnknownSecureHeader = errors.New("unknown secure header")
+
+// SecureHeadersFilterName is the name of the filter.
+const SecureHeadersFilterName = "SecureHeaders"
+
+type secureHeader struct {
+	name  string
+	value string
+}
+
+// defaultSecureHeaders returns the headers the SecureHeaders filter sets, with their default values.
+func defaultSecureHeaders() []secureHeader {
+	return []secureHeader{
+		{name: "Strict-Transport-Security", value: "max-age=31536000; includeSubDomains"},
+		{name: "Content-Security-Policy", value: "default-src 'self'; frame-ancestors 'none'; object-src 'none'"},
+		{name: "X-Content-Type-Options", value: "nosniff"},
+		{name: "X-Frame-Options", value: "DENY"},
+		{name: "Referrer-Policy", value: "no-referrer"},
+		{name: "Permissions-Policy", value: "camera=(), geolocation=(), microphone=(), payment=(), usb=()"},
+		{name: "Cross-Origin-Opener-Policy", value: "same-origin"},
+		{name: "Cross-Origin-Embedder-Policy", value: "require-corp"},
+		{name: "Cross-Origin-Resource-Policy", value: "same-origin"},
+	}
+}
+
+// SecureHeaders is a filter that sets security headers in the response:
+//   - Strict-Transport-Security: max-age=31536000; includeSubDomains
+//   - Content-Security-Policy: default-src 'self'; frame-ancestors 'none'; object-src 'none'
+//   - X-Content-Type-Options: nosniff
+//   - X-Frame-Options: DENY
+//   - Referrer-Policy: no-referrer
+//   - Permissions-Policy: camera=(), geolocation=(), microphone=(), payment=(), usb=()
+//   - Cross-Origin-Opener-Policy: same-origin
+//   - Cross-Origin-Embedder-Policy: require-corp
+//   - Cross-Origin-Resource-Policy: same-origin
+//
+// The value of each header can be replaced, and each header can be disabled. The headers the backend
+// already set are left as they are, unless the filter overrides them.
+type SecureHeaders struct {
+	headers  []secureHeader
+	override bool
+}
+
+// NewSecureHeadersFilter creates a new SecureHeadersFilter.
+//
+// The values replace the default values of the headers, and the disabled headers are not set. It returns an
+// error when they name a header the filter does not set.
+func NewSecureHeadersFilter(values map[string]string, disabled []string, override bool) (*SecureHeaders, error) {
+	headers := defaultSecureHeaders()
+	for name, value := range values {
+		index := secureHeaderIndex(headers, name)
+		if index == -1 {
+			return nil, fmt.Errorf("failed to build secure headers filter: %w: %s", ErrUnknownSecureHeader, name)
+		}
+		headers[index].value = value
+	}
+	for _, name := range disabled {
+		if secureHeaderIndex(headers, name) == -1 {
+			return nil, fmt.Errorf("failed to build secure headers filter: %w: %s", ErrUnknownSecureHeader, name)
+		}
+	}
+	headers = slices.DeleteFunc(headers, func(header secureHeader) bool {
+		return slices.ContainsFunc(disabled, func(name string) bool {
+			return http.CanonicalHeaderKey(name) == header.name
+		})
+	})
+	return &SecureHeaders{
+		headers:  headers,
+		override: override,
+	}, nil
+}
+
+func secureHeaderIndex(headers []secureHeader, name string) int {
+	return slices.IndexFunc(headers, func(header secureHeader) bool {
+		return header.name == http.CanonicalHeaderKey(name)
+	})
+}
+
+// NewSecureHeadersBuilder creates a new SecureHeadersBuilder.
+//
+// The args are:
+// - headers: optional, the values replacing the default values, by header name.
+// - disable: optional, the names of the headers not to set.
+// - override: optional, whether the headers the backend set are replaced (default false).
+func NewSecureHeadersBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		values, err := convertOptionalStringMap(args, "headers")
+		if err != nil {
+			return nil, err
+		}
+		disabled, err := convertOptionalStringSlice(args, "disable")
+		if err != nil {
+			return nil, err
+		}
+		override, err := convertOptionalBool(args, "override")
+		if err != nil {
+			return nil, err
+		}
+		return NewSecureHeadersFilter(values, disabled, override)
+	}
+}
+
+// PreProcess does nothing.
+func (f *SecureHeaders) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess sets the security headers in the response.
+func (f *SecureHeaders) PostProcess(ctx *gateway.Context) error {
+	for _, header := range f.headers {
+		if !f.override && len(ctx.Response.Headers.Values(header.name)) != 0 {
+			continue
+		}
+		ctx.Response.Headers.Set(header.name, header.value)
+	}
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *SecureHeaders) Name() string {
+	return SecureHeadersFilterName
+}
@@ -0,0 +1,167 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewSecureHeadersBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are not present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name: "build should succeed when args are present and are valid",
+			args: map[string]any{
+				"headers":  map[string]any{"x-frame-options": "SAMEORIGIN"},
+				"disable":  []any{"Cross-Origin-Embedder-Policy"},
+				"override": true,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when headers argument is not valid",
+			args:        map[string]any{"headers": map[string]any{"X-Frame-Options": 1}},
+			expectedErr: errors.New("failed to convert 'headers' attribute: X-Frame-Options: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when disable argument is not valid",
+			args:        map[string]any{"disable": "X-Frame-Options"},
+			expectedErr: errors.New("failed to convert 'disable' attribute: value is required to be a valid slice"),
+		},
+		{
+			name:        "build should fail when override argument is not valid",
+			args:        map[string]any{"override": "yes"},
+			expectedErr: errors.New("failed to convert 'override' attribute: value is required to be a valid bool"),
+		},
+		{
+			name:        "build should fail when headers argument names unknown header",
+			args:        map[string]any{"headers": map[string]any{"X-Powered-By": "gateway"}},
+			expectedErr: errors.New("failed to build secure headers filter: unknown secure header: X-Powered-By"),
+		},
+		{
+			name:        "build should fail when disable argument names unknown header",
+			args:        map[string]any{"disable": []any{"X-XSS-Protection"}},
+			expectedErr: errors.New("failed to build secure headers filter: unknown secure header: X-XSS-Protection"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewSecureHeadersBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestSecureHeadersFilter_PostProcess(t *testing.T) {
+	defaults := http.Header{
+		"Strict-Transport-Security":    {"max-age=31536000; includeSubDomains"},
+		"Content-Security-Policy":      {"default-src 'self'; frame-ancestors 'none'; object-src 'none'"},
+		"X-Content-Type-Options":       {"nosniff"},
+		"X-Frame-Options":              {"DENY"},
+		"Referrer-Policy":              {"no-referrer"},
+		"Permissions-Policy":           {"camera=(), geolocation=(), microphone=(), payment=(), usb=()"},
+		"Cross-Origin-Opener-Policy":   {"same-origin"},
+		"Cross-Origin-Embedder-Policy": {"require-corp"},
+		"Cross-Origin-Resource-Policy": {"same-origin"},
+	}
+	withHeaders := func(changes http.Header, removed ...string) http.Header {
+		headers := defaults.Clone()
+		for name, values := range changes {
+			headers[name] = values
+		}
+		for _, name := range removed {
+			delete(headers, name)
+		}
+		return headers
+	}
+	tests := []struct {
+		values          map[string]string
+		backendHeaders  http.Header
+		expectedHeaders http.Header
+		name            string
+		disabled        []string
+		override        bool
+	}{
+		{
+			name:            "post process should set default headers",
+			backendHeaders:  http.Header{},
+			expectedHeaders: defaults,
+		},
+		{
+			name:            "post process should keep headers set by backend",
+			backendHeaders:  http.Header{"X-Frame-Options": {"SAMEORIGIN"}, "Content-Type": {"text/html"}},
+			expectedHeaders: withHeaders(http.Header{"X-Frame-Options": {"SAMEORIGIN"}, "Content-Type": {"text/html"}}),
+		},
+		{
+			name:            "post process should override headers set by backend when told to",
+			backendHeaders:  http.Header{"X-Frame-Options": {"SAMEORIGIN"}},
+			expectedHeaders: defaults,
+			override:        true,
+		},
+		{
+			name:            "post process should set configured values",
+			values:          map[string]string{"referrer-policy": "strict-origin-when-cross-origin"},
+			backendHeaders:  http.Header{},
+			expectedHeaders: withHeaders(http.Header{"Referrer-Policy": {"strict-origin-when-cross-origin"}}),
+		},
+		{
+			name:            "post process should not set disabled headers",
+			disabled:        []string{"cross-origin-embedder-policy", "Strict-Transport-Security"},
+			backendHeaders:  http.Header{},
+			expectedHeaders: withHeaders(nil, "Cross-Origin-Embedder-Policy", "Strict-Transport-Security"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, "/", nil)
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			ctx.Response = gateway.NewGatewayResponse(&http.Response{StatusCode: http.StatusOK, Header: tt.backendHeaders})
+			f, err := filter.NewSecureHeadersFilter(tt.values, tt.disabled, tt.override)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			_ = f.PostProcess(ctx)
+
+			if !reflect.DeepEqual(ctx.Response.Headers, tt.expectedHeaders) {
+				t.Errorf("expected headers %v actual %v", tt.expectedHeaders, ctx.Response.Headers)
+			}
+		})
+	}
+}
+
+func TestSecureHeadersFilter_PreProcess(t *testing.T) {
+	f, _ := filter.NewSecureHeadersFilter(nil, nil, false)
+	if err := f.PreProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestSecureHeadersFilter_Name(t *testing.T) {
+	expected := "SecureHeaders"
+
+	f, _ := filter.NewSecureHeadersFilter(nil, nil, false)
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
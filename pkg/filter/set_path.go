@@ -0,0 +1,66 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// SetPathFilterName is the name of the filter.
+const SetPathFilterName = "SetPath"
+
+// SetPath is a filter that sets the path of the request from a template. The template is an Expression, so
+// it can reference the path variables captured by the route predicates as '{name}'.
+//
+// For example, with the path predicate '/api/users/{id}' and the template '/users/{id}/profile',
+// the request path '/api/users/42' is set to '/users/42/profile'.
+//
+// The template is an escaped path, like '/files%2Fv1/{name}', so it can contain encoded characters. The values
+// of the variables are escaped, except their slashes.
+type SetPath struct {
+	template Expression
+}
+
+// NewSetPathFilter creates a new SetPathFilter.
+func NewSetPathFilter(template string) (*SetPath, error) {
+	if err := validateEscapedPath(template); err != nil {
+		return nil, fmt.Errorf("failed to build set path filter: %w", err)
+	}
+	expression, err := CompileExpression(template)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build set path filter: %w", err)
+	}
+	return &SetPath{
+		template: expression,
+	}, nil
+}
+
+// NewSetPathBuilder creates a new SetPathBuilder.
+func NewSetPathBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		template, err := shared.ConvertToString(args["template"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'template' attribute: %w", err)
+		}
+		return NewSetPathFilter(template)
+	}
+}
+
+// PreProcess sets the path of the request.
+// The original request URL is stored in the context as an attribute with the name GatewayOriginalRequestAttr,
+// unless a previous filter already stored it.
+func (f *SetPath) PreProcess(ctx *gateway.Context) error {
+	recordOriginalURL(ctx)
+	return setEscapedPath(ctx, f.template.ExpandEscaped(ctx, escapePathValue))
+}
+
+// PostProcess does nothing.
+func (f *SetPath) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *SetPath) Name() string {
+	return SetPathFilterName
+}
@@ -0,0 +1,151 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewSetPathBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"template": "/users/{id}"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when template argument is not valid",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'template' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when template is not a valid escaped path",
+			args:        map[string]any{"template": "/users/%zz"},
+			expectedErr: errors.New("failed to build set path filter: invalid path filter: invalid URL escape \"%zz\""),
+		},
+		{
+			name:        "build should fail when template references unknown variable",
+			args:        map[string]any{"template": "/users/${user.id}"},
+			expectedErr: errors.New("failed to build set path filter: unknown expression variable: user.id"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewSetPathBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestSetPathFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		variables    map[string]string
+		name         string
+		template     string
+		target       string
+		expectedPath string
+		expectedURL  string
+		expectedRaw  string
+	}{
+		{
+			name:         "pre process should set literal path",
+			template:     "/health",
+			target:       "http://example.org/api/health?verbose=true",
+			expectedPath: "/health",
+			expectedURL:  "http://example.org/health?verbose=true",
+		},
+		{
+			name:         "pre process should set path with path variables",
+			template:     "/users/{id}/profile",
+			variables:    map[string]string{"id": "42"},
+			target:       "http://example.org/api/users/42",
+			expectedPath: "/users/42/profile",
+			expectedURL:  "http://example.org/users/42/profile",
+		},
+		{
+			name:         "pre process should escape path variables",
+			template:     "/files/{name}",
+			variables:    map[string]string{"name": "a b"},
+			target:       "http://example.org/api/files/a%20b",
+			expectedPath: "/files/a b",
+			expectedURL:  "http://example.org/files/a%20b",
+		},
+		{
+			name:         "pre process should keep slashes of path variables",
+			template:     "/static/{path}",
+			variables:    map[string]string{"path": "css/site.css"},
+			target:       "http://example.org/assets/css/site.css",
+			expectedPath: "/static/css/site.css",
+			expectedURL:  "http://example.org/static/css/site.css",
+		},
+		{
+			name:         "pre process should keep encoded characters of template",
+			template:     "/files%2Fv1/{id}",
+			variables:    map[string]string{"id": "42"},
+			target:       "http://example.org/api/42",
+			expectedPath: "/files/v1/42",
+			expectedURL:  "http://example.org/files%2Fv1/42",
+			expectedRaw:  "/files%2Fv1/42",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.target, nil)
+			originalURL := req.URL
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			if tt.variables != nil {
+				ctx.Attributes[gateway.PathVariablesAttr] = tt.variables
+			}
+
+			f, _ := filter.NewSetPathFilter(tt.template)
+
+			if err := f.PreProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Request.URL.Path != tt.expectedPath || ctx.Request.URL.String() != tt.expectedURL ||
+				ctx.Request.URL.RawPath != tt.expectedRaw {
+				t.Errorf("expected %s %s %s actual %s %s %s", tt.expectedPath, tt.expectedURL, tt.expectedRaw,
+					ctx.Request.URL.Path, ctx.Request.URL, ctx.Request.URL.RawPath)
+			}
+			if original, _ := ctx.Attributes[filter.GatewayOriginalRequestAttr].(*url.URL); original != originalURL {
+				t.Errorf("expected original url %s actual %s", originalURL, original)
+			}
+			if req.URL != originalURL || req.URL.String() != tt.target {
+				t.Errorf("expected inbound url %s untouched actual %s", tt.target, req.URL)
+			}
+		})
+	}
+}
+
+func TestSetPathFilter_PostProcess(t *testing.T) {
+	f, _ := filter.NewSetPathFilter("/")
+	if err := f.PostProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestSetPathFilter_Name(t *testing.T) {
+	expected := "SetPath"
+
+	f, _ := filter.NewSetPathFilter("/")
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
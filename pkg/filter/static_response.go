@@ -0,0 +1,174 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// ErrInvalidStatus is returned when a filter is built with a status code it does not allow.
+var ErrInvalidStatus = errors.New("invalid status")
+
+const (
+	// SetStatusFilterName is the name of the filter.
+	SetStatusFilterName = "SetStatus"
+
+	// StaticResponseFilterName is the name of the filter.
+	StaticResponseFilterName = "StaticResponse"
+)
+
+const (
+	minStatus = 100
+	maxStatus = 599
+)
+
+func validateStatus(status int) error {
+	if status < minStatus || status > maxStatus {
+		return fmt.Errorf("%w: %d is not between %d and %d", ErrInvalidStatus, status, minStatus, maxStatus)
+	}
+	return nil
+}
+
+// SetStatus is a filter that sets the status of the response, from the backend or not.
+type SetStatus struct {
+	status int
+}
+
+// NewSetStatusFilter creates a new SetStatusFilter.
+func NewSetStatusFilter(status int) (*SetStatus, error) {
+	if err := validateStatus(status); err != nil {
+		return nil, fmt.Errorf("failed to build set status filter: %w", err)
+	}
+	return &SetStatus{
+		status: status,
+	}, nil
+}
+
+// NewSetStatusBuilder creates a new SetStatusBuilder.
+func NewSetStatusBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		status, err := shared.ConvertToInt(args["status"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'status' attribute: %w", err)
+		}
+		return NewSetStatusFilter(status)
+	}
+}
+
+// PreProcess does nothing.
+func (f *SetStatus) PreProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// PostProcess sets the status of the response.
+func (f *SetStatus) PostProcess(ctx *gateway.Context) error {
+	ctx.Response.Status = f.status
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *SetStatus) Name() string {
+	return SetStatusFilterName
+}
+
+// StaticResponse is a filter that answers the request with a fixed response, without calling the backend,
+// like a maintenance payload.
+type StaticResponse struct {
+	headers http.Header
+	body    string
+	status  int
+}
+
+// NewStaticResponseFilter creates a new StaticResponseFilter.
+func NewStaticResponseFilter(status int, headers http.Header, body string) (*StaticResponse, error) {
+	if err := validateStatus(status); err != nil {
+		return nil, fmt.Errorf("failed to build static response filter: %w", err)
+	}
+	return &StaticResponse{
+		status:  status,
+		headers: headers,
+		body:    body,
+	}, nil
+}
+
+// NewStaticResponseBuilder creates a new StaticResponseBuilder.
+//
+// The args are expected to contain the following keys:
+// - status: optional, the status of the response (default 200).
+// - headers: optional, the headers of the response, by name.
+// - body: optional, the body of the response (default empty).
+func NewStaticResponseBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		status := http.StatusOK
+		if args["status"] != nil {
+			var err error
+			if status, err = shared.ConvertToInt(args["status"]); err != nil {
+				return nil, fmt.Errorf("failed to convert 'status' attribute: %w", err)
+			}
+		}
+		values, err := convertOptionalStringMap(args, "headers")
+		if err != nil {
+			return nil, err
+		}
+		headers := http.Header{}
+		for name, value := range values {
+			headers.Set(name, value)
+		}
+		body, err := convertOptionalString(args, "body")
+		if err != nil {
+			return nil, err
+		}
+		return NewStaticResponseFilter(status, headers, body)
+	}
+}
+
+// PreProcess answers the request with the static response.
+func (f *StaticResponse) PreProcess(ctx *gateway.Context) error {
+	ctx.Response = gateway.NewStaticGatewayResponse(f.status, f.headers.Clone(), f.body)
+	return nil
+}
+
+// PostProcess does nothing.
+func (f *StaticResponse) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *StaticResponse) Name() string {
+	return StaticResponseFilterName
+}
+
+// convertOptionalString converts the named arg to a string, returning an empty string when it is not present.
+func convertOptionalString(args map[string]any, name string) (string, error) {
+	if args[name] == nil {
+		return "", nil
+	}
+	value, err := shared.ConvertToString(args[name])
+	if err != nil {
+		return "", fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
+
+// convertOptionalStringMap converts the optional map arg whose values are strings.
+func convertOptionalStringMap(args map[string]any, name string) (map[string]string, error) {
+	if args[name] == nil {
+		return nil, nil
+	}
+	rawValues, err := shared.ConvertToMap(args[name])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	values := make(map[string]string, len(rawValues))
+	for key, rawValue := range rawValues {
+		value, err := shared.ConvertToString(rawValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert '%s' attribute: %s: %w", name, key, err)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,150 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewSetStatusBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"status": 418},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when status argument is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'status' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when status is out of range",
+			args:        map[string]any{"status": 600},
+			expectedErr: errors.New("failed to build set status filter: invalid status: 600 is not between 100 and 599"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewSetStatusBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestSetStatusFilter(t *testing.T) {
+	ctx := &gateway.Context{Response: gateway.NewStaticGatewayResponse(http.StatusOK, nil, "")}
+	f, _ := filter.NewSetStatusFilter(http.StatusAccepted)
+
+	if err := f.PreProcess(ctx); err != nil || ctx.Response.Status != http.StatusOK {
+		t.Errorf("expected pre process to do nothing actual %v %d", err, ctx.Response.Status)
+	}
+	if err := f.PostProcess(ctx); err != nil || ctx.Response.Status != http.StatusAccepted {
+		t.Errorf("expected status %d actual %v %d", http.StatusAccepted, err, ctx.Response.Status)
+	}
+	if f.Name() != "SetStatus" {
+		t.Errorf("expected SetStatus actual %s", f.Name())
+	}
+}
+
+func TestNewStaticResponseBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name: "build should succeed when args are present and are valid",
+			args: map[string]any{
+				"status":  503,
+				"headers": map[string]any{"Content-Type": "application/json", "Retry-After": "120"},
+				"body":    `{"status":"maintenance"}`,
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when args are not present",
+			args:        map[string]any{},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when status argument is not valid",
+			args:        map[string]any{"status": "unavailable"},
+			expectedErr: errors.New("failed to convert 'status' attribute: value is required to be a valid int"),
+		},
+		{
+			name:        "build should fail when status is out of range",
+			args:        map[string]any{"status": 99},
+			expectedErr: errors.New("failed to build static response filter: invalid status: 99 is not between 100 and 599"),
+		},
+		{
+			name:        "build should fail when headers argument is not valid",
+			args:        map[string]any{"headers": "Content-Type"},
+			expectedErr: errors.New("failed to convert 'headers' attribute: value is required to be a valid map"),
+		},
+		{
+			name:        "build should fail when header value is not valid",
+			args:        map[string]any{"headers": map[string]any{"Retry-After": 120}},
+			expectedErr: errors.New("failed to convert 'headers' attribute: Retry-After: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when body argument is not valid",
+			args:        map[string]any{"body": 1},
+			expectedErr: errors.New("failed to convert 'body' attribute: value is required to be a valid string"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewStaticResponseBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestStaticResponseFilter(t *testing.T) {
+	headers := http.Header{"Content-Type": {"application/json"}}
+	f, _ := filter.NewStaticResponseFilter(http.StatusServiceUnavailable, headers, `{"status":"maintenance"}`)
+	ctx := &gateway.Context{}
+
+	if err := f.PreProcess(ctx); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	body, _ := io.ReadAll(ctx.Response.BodyReader)
+	if ctx.Response.Status != http.StatusServiceUnavailable || string(body) != `{"status":"maintenance"}` ||
+		!reflect.DeepEqual(ctx.Response.Headers, headers) {
+		t.Errorf("expected static response actual %d %v %s", ctx.Response.Status, ctx.Response.Headers, body)
+	}
+	ctx.Response.Headers.Set("X-Mutated", "true")
+	if headers.Get("X-Mutated") != "" {
+		t.Errorf("expected filter headers untouched actual %v", headers)
+	}
+	if err := f.PostProcess(ctx); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+	if f.Name() != "StaticResponse" {
+		t.Errorf("expected StaticResponse actual %s", f.Name())
+	}
+}
@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"context"
+	"sync"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// streamSlot is a resource a request holds from PreProcess until its response has been
+// streamed to the client, such as a concurrency slot.
+//
+// The release is tied to the gateway context as well, which covers every path where
+// PostProcess never runs: backend errors, a failing filter, or a panic.
+type streamSlot struct {
+	release func()
+	stop    func() bool
+}
+
+// newStreamSlot returns a slot calling release once, when the response stream ends or when
+// ctx is done, whichever happens first. ctx must be the inner context of the gateway context,
+// never the pooled gateway context itself.
+//
+// onContextDone, when not nil, is called with the context error right before the release
+// when ctx ends first.
+func newStreamSlot(ctx context.Context, release func(), onContextDone func(err error)) *streamSlot {
+	release = sync.OnceFunc(release)
+	return &streamSlot{
+		release: release,
+		stop: context.AfterFunc(ctx, func() {
+			if onContextDone != nil {
+				onContextDone(ctx.Err())
+			}
+			release()
+		}),
+	}
+}
+
+// detach unties the slot from the context, so only the end of the response stream releases
+// it. It returns false when the context already ended and released the slot.
+func (s *streamSlot) detach() bool {
+	return s.stop()
+}
+
+// releaseOnStreamEnd releases the slot once the body has been fully read or closed.
+func (s *streamSlot) releaseOnStreamEnd(body *gateway.ReplayableBody) {
+	body.ObserveStream(nil, func(_ int64, _ error) {
+		s.stop()
+		s.release()
+	})
+}
@@ -0,0 +1,69 @@
+package filter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// StripPrefixFilterName is the name of the filter.
+const StripPrefixFilterName = "StripPrefix"
+
+// StripPrefix is a filter that removes the first parts of the request path, segment by segment.
+//
+// For example, with 2 parts, the request path '/api/v1/users/42' is set to '/users/42'. When the path has
+// fewer segments, it is set to '/'. The encoded characters of the remaining segments are kept as is.
+type StripPrefix struct {
+	parts int
+}
+
+// NewStripPrefixFilter creates a new StripPrefixFilter. The parts must not be negative.
+func NewStripPrefixFilter(parts int) (*StripPrefix, error) {
+	if parts < 0 {
+		return nil, fmt.Errorf("failed to build strip prefix filter: %w: parts %d is negative",
+			ErrInvalidPathFilter, parts)
+	}
+	return &StripPrefix{
+		parts: parts,
+	}, nil
+}
+
+// NewStripPrefixBuilder creates a new StripPrefixBuilder.
+func NewStripPrefixBuilder() gateway.FilterBuilderFunc {
+	return func(args map[string]any) (gateway.Filter, error) {
+		parts, err := shared.ConvertToInt(args["parts"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'parts' attribute: %w", err)
+		}
+		return NewStripPrefixFilter(parts)
+	}
+}
+
+// PreProcess removes the first parts of the request path.
+// The original request URL is stored in the context as an attribute with the name GatewayOriginalRequestAttr,
+// unless a previous filter already stored it.
+func (f *StripPrefix) PreProcess(ctx *gateway.Context) error {
+	recordOriginalURL(ctx)
+	rest := strings.TrimPrefix(ctx.Request.URL.EscapedPath(), "/")
+	for range f.parts {
+		_, next, found := strings.Cut(rest, "/")
+		if !found {
+			rest = ""
+			break
+		}
+		rest = next
+	}
+	return setEscapedPath(ctx, "/"+rest)
+}
+
+// PostProcess does nothing.
+func (f *StripPrefix) PostProcess(_ *gateway.Context) error {
+	return nil
+}
+
+// Name returns the name of the filter.
+func (f *StripPrefix) Name() string {
+	return StripPrefixFilterName
+}
@@ -0,0 +1,134 @@
+package filter_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func TestNewStripPrefixBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"parts": 2},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when parts argument is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'parts' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when parts argument is not valid",
+			args:        map[string]any{"parts": "two"},
+			expectedErr: errors.New("failed to convert 'parts' attribute: value is required to be a valid int"),
+		},
+		{
+			name:        "build should fail when parts argument is negative",
+			args:        map[string]any{"parts": -1},
+			expectedErr: errors.New("failed to build strip prefix filter: invalid path filter: parts -1 is negative"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := filter.NewStripPrefixBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestStripPrefixFilter_PreProcess(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		expectedURL string
+		expectedRaw string
+		parts       int
+	}{
+		{
+			name:        "pre process should strip the first parts",
+			parts:       2,
+			target:      "http://example.org/api/v1/users/42?verbose=true",
+			expectedURL: "http://example.org/users/42?verbose=true",
+		},
+		{
+			name:        "pre process should keep trailing slash",
+			parts:       1,
+			target:      "http://example.org/api/users/",
+			expectedURL: "http://example.org/users/",
+		},
+		{
+			name:        "pre process should set root when path has fewer segments",
+			parts:       3,
+			target:      "http://example.org/api/users",
+			expectedURL: "http://example.org/",
+		},
+		{
+			name:        "pre process should keep path when parts is zero",
+			parts:       0,
+			target:      "http://example.org/api/users",
+			expectedURL: "http://example.org/api/users",
+		},
+		{
+			name:        "pre process should keep encoded slash",
+			parts:       1,
+			target:      "http://example.org/api/files/a%2Fb",
+			expectedURL: "http://example.org/files/a%2Fb",
+			expectedRaw: "/files/a%2Fb",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.target, nil)
+			ctx, _ := gateway.NewGatewayContext(t.Context(), &gateway.Route{}, gateway.NewGatewayRequest(req))
+			f, _ := filter.NewStripPrefixFilter(tt.parts)
+
+			if err := f.PreProcess(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Request.URL.String() != tt.expectedURL || ctx.Request.URL.RawPath != tt.expectedRaw {
+				t.Errorf("expected %s %s actual %s %s", tt.expectedURL, tt.expectedRaw,
+					ctx.Request.URL, ctx.Request.URL.RawPath)
+			}
+			if original, _ := ctx.Attributes[filter.GatewayOriginalRequestAttr].(*url.URL); original != req.URL {
+				t.Errorf("expected original url %s actual %s", req.URL, original)
+			}
+			if req.URL.String() != tt.target {
+				t.Errorf("expected inbound url %s untouched actual %s", tt.target, req.URL)
+			}
+		})
+	}
+}
+
+func TestStripPrefixFilter_PostProcess(t *testing.T) {
+	f, _ := filter.NewStripPrefixFilter(1)
+	if err := f.PostProcess(nil); err != nil {
+		t.Errorf("expected nil err actual %s", err)
+	}
+}
+
+func TestStripPrefixFilter_Name(t *testing.T) {
+	expected := "StripPrefix"
+
+	f, _ := filter.NewStripPrefixFilter(1)
+
+	if f.Name() != expected {
+		t.Errorf("expected %s actual %s", expected, f.Name())
+	}
+}
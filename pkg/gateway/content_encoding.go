@@ -0,0 +1,195 @@
+package gateway
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// ErrUnsupportedContentEncoding represents the error when a body is encoded with a content coding that has no
+// registered decoder.
+var ErrUnsupportedContentEncoding = errors.New("unsupported content encoding")
+
+// ErrContentDecoding represents the error when an encoded body cannot be decoded.
+var ErrContentDecoding = errors.New("content decoding failed")
+
+// ErrDecodedLimitExceeded represents the error when a decoded body goes over its decode limits.
+var ErrDecodedLimitExceeded = errors.New("decoded body limit exceeded")
+
+// DecodeRatioThreshold is the decoded size, in bytes, from which the max ratio of the decode limits is checked,
+// so that small and very compressible bodies are not rejected.
+const DecodeRatioThreshold = 1024 * 1024
+
+// ContentDecoderFunc creates a reader of src decoded from a content coding.
+type ContentDecoderFunc func(src io.Reader) (io.ReadCloser, error)
+
+// ContentDecoderRegistry is a content decoder registry.
+//
+// The key is the content coding, as sent in the Content-Encoding header.
+// The value is the decoder func.
+//
+// Only gzip, deflate and br are registered by default. Other codings, like zstd, can be registered with the
+// decoder of any library.
+//
+//nolint:gochecknoglobals
+var ContentDecoderRegistry = map[string]ContentDecoderFunc{
+	"gzip":    newGzipDecoder,
+	"x-gzip":  newGzipDecoder,
+	"deflate": newDeflateDecoder,
+	"br":      newBrotliDecoder,
+}
+
+// DecodeLimits bounds the size of a decoded body, protecting the gateway from decompression bombs.
+type DecodeLimits struct {
+	// MaxBytes is the max size of the decoded body, in bytes. Zero means no limit.
+	MaxBytes int64
+	// MaxRatio is the max ratio between the decoded and the encoded sizes, checked once the decoded body is
+	// larger than DecodeRatioThreshold. Zero means no limit.
+	MaxRatio int64
+}
+
+// ContentCodings returns the content codings of the Content-Encoding header value, in the order they were
+// applied, without the identity ones.
+func ContentCodings(contentEncoding string) []string {
+	var codings []string
+	for coding := range strings.SplitSeq(contentEncoding, ",") {
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "" && coding != "identity" {
+			codings = append(codings, coding)
+		}
+	}
+	return codings
+}
+
+// NewContentDecoder returns a reader of src decoded from the content codings of the Content-Encoding header
+// value, undone in reverse order.
+//
+// It returns an ErrUnsupportedContentEncoding error when a coding has no registered decoder, and an
+// ErrContentDecoding error when src is not validly encoded, now or while it is read. The reader fails with an
+// ErrDecodedLimitExceeded error as soon as the decoded body goes over the limits. The errors of src itself are
+// returned as they are. Closing the reader closes src, when it is an io.Closer.
+func NewContentDecoder(contentEncoding string, src io.Reader, limits DecodeLimits) (io.ReadCloser, error) {
+	codings := ContentCodings(contentEncoding)
+	for _, coding := range codings {
+		if ContentDecoderRegistry[coding] == nil {
+			return nil, fmt.Errorf("%w: %s", ErrUnsupportedContentEncoding, coding)
+		}
+	}
+	source := &countingReader{reader: src}
+	body := &decodedBody{source: source, reader: source, limits: limits}
+	if closer, ok := src.(io.Closer); ok {
+		body.closers = append(body.closers, closer)
+	}
+	for _, coding := range slices.Backward(codings) {
+		decoder, err := ContentDecoderRegistry[coding](body.reader)
+		if err != nil {
+			_ = body.Close()
+			return nil, body.wrapError(err)
+		}
+		body.reader = decoder
+		body.closers = append(body.closers, decoder)
+	}
+	return body, nil
+}
+
+// countingReader counts the bytes read from the reader, and keeps its last error.
+type countingReader struct {
+	reader io.Reader
+	err    error
+	count  int64
+}
+
+func (c *countingReader) Read(output []byte) (int, error) {
+	read, err := c.reader.Read(output)
+	c.count += int64(read)
+	c.err = err
+	return read, err //nolint:wrapcheck
+}
+
+// decodedBody reads the decoded body, enforcing the decode limits.
+type decodedBody struct {
+	reader   io.Reader
+	source   *countingReader
+	exceeded error
+	closers  []io.Closer
+	limits   DecodeLimits
+	decoded  int64
+}
+
+func (d *decodedBody) Read(output []byte) (int, error) {
+	if d.exceeded != nil {
+		return 0, d.exceeded
+	}
+	read, err := d.reader.Read(output)
+	d.decoded += int64(read)
+	if d.limits.MaxBytes > 0 && d.decoded > d.limits.MaxBytes {
+		over := d.decoded - d.limits.MaxBytes
+		d.decoded = d.limits.MaxBytes
+		d.exceeded = fmt.Errorf("%w: decoded size is larger than %d", ErrDecodedLimitExceeded, d.limits.MaxBytes)
+		return read - int(over), d.exceeded
+	}
+	if d.limits.MaxRatio > 0 && d.decoded > DecodeRatioThreshold && d.decoded > d.limits.MaxRatio*d.source.count {
+		d.exceeded = fmt.Errorf("%w: decoded size is more than %d times the encoded size",
+			ErrDecodedLimitExceeded, d.limits.MaxRatio)
+		return read, d.exceeded
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return read, d.wrapError(err)
+	}
+	return read, err //nolint:wrapcheck
+}
+
+// wrapError wraps the decoding errors, keeping the errors of the source as they are.
+func (d *decodedBody) wrapError(err error) error {
+	if d.source.err != nil && !errors.Is(d.source.err, io.EOF) && errors.Is(err, d.source.err) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrContentDecoding, err)
+}
+
+// Close closes the decoders and the source.
+func (d *decodedBody) Close() error {
+	var errs []error
+	for _, closer := range slices.Backward(d.closers) {
+		errs = append(errs, closer.Close())
+	}
+	d.closers = nil
+	return errors.Join(errs...)
+}
+
+func newGzipDecoder(src io.Reader) (io.ReadCloser, error) {
+	return gzip.NewReader(src) //nolint:wrapcheck
+}
+
+func newBrotliDecoder(src io.Reader) (io.ReadCloser, error) {
+	return io.NopCloser(brotli.NewReader(src)), nil
+}
+
+// newDeflateDecoder decodes the deflate coding, which is the zlib format. The raw deflate streams some
+// clients send are decoded too.
+func newDeflateDecoder(src io.Reader) (io.ReadCloser, error) {
+	header := make([]byte, 2) //nolint:mnd // the zlib header is two bytes long
+	read, err := io.ReadFull(src, header)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err //nolint:wrapcheck
+	}
+	src = io.MultiReader(bytes.NewReader(header[:read]), src)
+	if read == len(header) && isZlibHeader(header) {
+		return zlib.NewReader(src) //nolint:wrapcheck
+	}
+	return flate.NewReader(src), nil
+}
+
+// isZlibHeader returns whether the two bytes are a zlib header of a deflate stream.
+func isZlibHeader(header []byte) bool {
+	const methodMask, deflateMethod, checkDivisor = 0x0f, 8, 31
+	return header[0]&methodMask == deflateMethod && (uint16(header[0])<<8|uint16(header[1]))%checkDivisor == 0
+}
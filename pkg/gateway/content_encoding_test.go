@@ -0,0 +1,233 @@
+package gateway_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return buf.Bytes()
+}
+
+func zlibBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zlib.NewWriter(&buf)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buf.Bytes()
+}
+
+func flateBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, _ := flate.NewWriter(&buf, flate.DefaultCompression)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buf.Bytes()
+}
+
+func TestContentCodings(t *testing.T) {
+	tests := []struct {
+		name            string
+		contentEncoding string
+		expected        []string
+	}{
+		{
+			name:            "codings should be empty when header is empty",
+			contentEncoding: "",
+			expected:        nil,
+		},
+		{
+			name:            "codings should skip identity",
+			contentEncoding: "identity",
+			expected:        nil,
+		},
+		{
+			name:            "codings should be lower case and in applied order",
+			contentEncoding: "Deflate, GZIP",
+			expected:        []string{"deflate", "gzip"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := gateway.ContentCodings(tt.contentEncoding)
+
+			if !reflect.DeepEqual(tt.expected, actual) {
+				t.Errorf("expected %v actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func brotliBytes(t *testing.T, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := brotli.NewWriter(&buf)
+	_, _ = writer.Write(data)
+	_ = writer.Close()
+	return buf.Bytes()
+}
+
+func TestNewContentDecoder(t *testing.T) {
+	payload := []byte(strings.Repeat("decoded body ", 100))
+	tests := []struct {
+		expectedErr     error
+		expectedReadErr error
+		src             io.Reader
+		name            string
+		contentEncoding string
+		expected        []byte
+		limits          gateway.DecodeLimits
+	}{
+		{
+			name:            "decoder should decode gzip",
+			contentEncoding: "gzip",
+			src:             bytes.NewReader(gzipBytes(t, payload)),
+			expected:        payload,
+		},
+		{
+			name:            "decoder should decode zlib deflate",
+			contentEncoding: "deflate",
+			src:             bytes.NewReader(zlibBytes(t, payload)),
+			expected:        payload,
+		},
+		{
+			name:            "decoder should decode raw deflate",
+			contentEncoding: "deflate",
+			src:             bytes.NewReader(flateBytes(t, payload)),
+			expected:        payload,
+		},
+		{
+			name:            "decoder should decode br",
+			contentEncoding: "br",
+			src:             bytes.NewReader(brotliBytes(t, payload)),
+			expected:        payload,
+		},
+		{
+			name:            "decoder should fail when br body is not validly encoded",
+			contentEncoding: "br",
+			src:             bytes.NewReader(payload),
+			expectedReadErr: gateway.ErrContentDecoding,
+		},
+		{
+			name:            "decoder should undo codings in reverse order",
+			contentEncoding: "deflate, gzip",
+			src:             bytes.NewReader(gzipBytes(t, zlibBytes(t, payload))),
+			expected:        payload,
+		},
+		{
+			name:            "decoder should return identity body as is",
+			contentEncoding: "identity",
+			src:             bytes.NewReader(payload),
+			expected:        payload,
+		},
+		{
+			name:            "decoder should fail when coding is not supported",
+			contentEncoding: "zstd",
+			src:             bytes.NewReader(payload),
+			expectedErr:     gateway.ErrUnsupportedContentEncoding,
+		},
+		{
+			name:            "decoder should fail when body is not encoded",
+			contentEncoding: "gzip",
+			src:             bytes.NewReader(payload),
+			expectedErr:     gateway.ErrContentDecoding,
+		},
+		{
+			name:            "decoder should fail when body is truncated",
+			contentEncoding: "gzip",
+			src:             bytes.NewReader(gzipBytes(t, payload)[:40]),
+			expectedReadErr: gateway.ErrContentDecoding,
+		},
+		{
+			name:            "decoder should fail when decoded body is larger than max bytes",
+			contentEncoding: "gzip",
+			src:             bytes.NewReader(gzipBytes(t, payload)),
+			limits:          gateway.DecodeLimits{MaxBytes: 100},
+			expected:        payload[:100],
+			expectedReadErr: gateway.ErrDecodedLimitExceeded,
+		},
+		{
+			name:            "decoder should fail when decoded body is larger than max ratio",
+			contentEncoding: "gzip",
+			src:             bytes.NewReader(gzipBytes(t, make([]byte, 4*gateway.DecodeRatioThreshold))),
+			limits:          gateway.DecodeLimits{MaxRatio: 100},
+			expectedReadErr: gateway.ErrDecodedLimitExceeded,
+		},
+		{
+			name:            "decoder should keep source errors as they are",
+			contentEncoding: "gzip",
+			src: io.MultiReader(
+				bytes.NewReader(gzipBytes(t, payload)[:40]), &erroringReader{err: gateway.ErrRequestTooLarge}),
+			expectedReadErr: gateway.ErrRequestTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decoder, err := gateway.NewContentDecoder(tt.contentEncoding, tt.src, tt.limits)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Fatalf("expected err %v actual %v", tt.expectedErr, err)
+			}
+			if err != nil {
+				return
+			}
+			defer decoder.Close() //nolint:errcheck
+			actual, err := io.ReadAll(decoder)
+			if !errors.Is(err, tt.expectedReadErr) {
+				t.Fatalf("expected read err %v actual %v", tt.expectedReadErr, err)
+			}
+			if errors.Is(tt.expectedReadErr, gateway.ErrRequestTooLarge) && errors.Is(err, gateway.ErrContentDecoding) {
+				t.Errorf("expected source err not to be a decoding err, actual %v", err)
+			}
+			if tt.expected != nil && !bytes.Equal(tt.expected, actual) {
+				t.Errorf("expected %q actual %q", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestNewContentDecoder_ClosesSource(t *testing.T) {
+	src := &closeCountingBody{Reader: bytes.NewReader(gzipBytes(t, []byte("body")))}
+	decoder, err := gateway.NewContentDecoder("gzip", src, gateway.DecodeLimits{})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err = decoder.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if src.closes != 1 {
+		t.Errorf("expected source closed once, actual %d", src.closes)
+	}
+}
+
+type erroringReader struct {
+	err error
+}
+
+func (r *erroringReader) Read(_ []byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,190 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FallbackCauseAttr is the name of the attribute that contains the error that made the route
+// answer through its fallback. Filters can read it to tell fallback responses apart.
+const FallbackCauseAttr = "GATEWAY_FALLBACK_CAUSE"
+
+// FallbackReasonHeader is the request header telling a backend or forward fallback why it was
+// called: FallbackReasonCircuitBreaker or FallbackReasonBackendError.
+const FallbackReasonHeader = "X-Gateway-Fallback-Reason"
+
+// FallbackErrorHeader is the request header carrying the message of the original error to a
+// backend or forward fallback, when the fallback exposes it.
+const FallbackErrorHeader = "X-Gateway-Fallback-Error"
+
+// These constants are the fallback reasons.
+const (
+	FallbackReasonCircuitBreaker = "circuit-breaker"
+	FallbackReasonBackendError   = "backend-error"
+)
+
+// Fallback is the response source of a route whose backend call was rejected by the circuit
+// breaker or failed. Exactly one of the fields is expected to be set:
+//
+// URI is another backend. The scheme and host replace the ones of the route, and the path too
+// when not empty. The request is sent without going through the circuit breaker.
+//
+// Route is another gateway route the request is forwarded to, running its filters and backend.
+// The URL and headers of the request are restored to the ones the failing route received, so the
+// changes of its filters are not applied twice. The response is the one of the forwarded route:
+// the failing route does not post-process it.
+//
+// Static is a response built by the gateway itself.
+//
+// ExposeError sends the message of the original error to the fallback, as ${error} and in the
+// FallbackErrorHeader. It is off by default, since the message may carry internal details like
+// backend hosts: ${error} is then the fallback reason and the header is not sent.
+//
+// A request body already streamed to the failing backend cannot be sent again, so backend errors
+// only fall back to URI and Route when the body is empty or captured. Circuit breaker rejections
+// never touch the body.
+type Fallback struct {
+	URI         *url.URL
+	Route       *Route
+	Static      *StaticResponse
+	ExposeError bool
+}
+
+// StaticResponse is a response built by the gateway.
+//
+// The body is a template where ${route}, ${reason} and ${error} are replaced with the route ID,
+// the fallback reason and the original error message, or the reason when the fallback does not
+// expose it.
+type StaticResponse struct {
+	Headers http.Header
+	Body    string
+	Status  int
+}
+
+func (s *StaticResponse) render(ctx *Context, reason, message string) *Response {
+	body := strings.NewReplacer(
+		"${route}", ctx.Route.ID,
+		"${reason}", reason,
+		"${error}", message,
+	).Replace(s.Body)
+	return NewStaticGatewayResponse(s.Status, s.Headers.Clone(), body)
+}
+
+// fallbackReason returns the fallback reason of a backend error, or false when the error
+// does not trigger the fallback: timeouts and client cancellations leave no time to answer.
+func fallbackReason(err error) (string, bool) {
+	switch {
+	case errors.Is(err, ErrCircuitBreaker):
+		return FallbackReasonCircuitBreaker, true
+	case errors.Is(err, ErrHTTP):
+		return FallbackReasonBackendError, true
+	default:
+		return "", false
+	}
+}
+
+// doFallback answers the request through the route fallback. cause is the backend error,
+// returned untouched when the fallback does not apply. snapshot is the request before the route
+// filters ran, restored before forwarding it to another route.
+func (g *Gateway) doFallback(ctx *Context, cause error, snapshot *requestSnapshot) error {
+	fallback := ctx.Route.Fallback
+	reason, ok := fallbackReason(cause)
+	if !ok || ctx.Attributes[FallbackCauseAttr] != nil {
+		// A forwarded request never falls back again: fallback chains would loop.
+		return cause
+	}
+	if reason == FallbackReasonBackendError && fallback.Static == nil && !isBodyReplayable(ctx.Request.BodyReader) {
+		return cause
+	}
+	ctx.Attributes[FallbackCauseAttr] = cause
+	ctx.Logger.Warn("answering through route fallback", "reason", reason, "error", cause)
+	message := reason
+	if fallback.ExposeError {
+		message = cause.Error()
+	}
+	switch {
+	case fallback.Static != nil:
+		ctx.Response = fallback.Static.render(ctx, reason, message)
+		return g.postProcess(ctx)
+	case fallback.Route != nil:
+		snapshot.restore(ctx.Request)
+		setFallbackHeaders(ctx.Request.Headers, reason, message, fallback.ExposeError)
+		// The forwarded route is copied like FindMatching does, so its filters see a
+		// request-scoped route.
+		route := *fallback.Route
+		ctx.Route = &route
+		return g.Do(ctx)
+	case fallback.URI != nil:
+		setFallbackHeaders(ctx.Request.Headers, reason, message, fallback.ExposeError)
+		return g.doFallbackURI(ctx, fallback.URI)
+	default:
+		return cause
+	}
+}
+
+func (g *Gateway) doFallbackURI(ctx *Context, fallbackURI *url.URL) error {
+	backendReq := g.buildProxyRequest(ctx)
+	backendReq.URL.Scheme = fallbackURI.Scheme
+	backendReq.URL.Host = fallbackURI.Host
+	if fallbackURI.Path != "" {
+		backendReq.URL.Path = fallbackURI.Path
+		backendReq.URL.RawPath = fallbackURI.RawPath
+	}
+	// The inner context does not carry the route: the fallback call bypasses the circuit
+	// breaker that rejected the original one.
+	backendReq = backendReq.WithContext(ctx.Context)
+	backendRes, err := g.httpClient.Do(backendReq) //nolint:bodyclose
+	if err != nil {
+		return g.handleBackendError(ctx, fmt.Errorf("fallback %s: %w", fallbackURI.Redacted(), err))
+	}
+	ctx.Response = NewGatewayResponse(backendRes)
+	return g.postProcess(ctx)
+}
+
+// requestSnapshot is the URL and headers of a request before the route filters changed them.
+type requestSnapshot struct {
+	url     *url.URL
+	headers http.Header
+}
+
+// snapshotRequest returns the URL and headers of the request when the route forwards to another
+// route on failure, or nil otherwise: only forwarding needs them, and cloning the headers of every
+// request would be wasted.
+func snapshotRequest(ctx *Context) *requestSnapshot {
+	if ctx.Route.Fallback == nil || ctx.Route.Fallback.Route == nil {
+		return nil
+	}
+	requestURL := *ctx.Request.URL
+	return &requestSnapshot{url: &requestURL, headers: ctx.Request.Headers.Clone()}
+}
+
+// restore puts the URL and headers of the snapshot back in the request.
+func (s *requestSnapshot) restore(req *Request) {
+	req.URL = s.url
+	req.Headers = s.headers
+}
+
+func setFallbackHeaders(headers http.Header, reason, message string, exposeError bool) {
+	headers.Set(FallbackReasonHeader, reason)
+	if exposeError {
+		headers.Set(FallbackErrorHeader, sanitizeHeaderValue(message))
+	}
+}
+
+// sanitizeHeaderValue drops the characters not allowed in header values, since error messages
+// may carry them from the backend.
+func sanitizeHeaderValue(value string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '\r' || r == '\n' || r == 0 {
+			return ' '
+		}
+		return r
+	}, value)
+}
+
+func isBodyReplayable(body *ReplayableBody) bool {
+	return body == nil || body.Len() == 0 || body.Bytes() != nil
+}
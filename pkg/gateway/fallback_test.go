@@ -0,0 +1,337 @@
+package gateway_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+type sequenceHTTPClient struct {
+	errs      []error
+	responses []*http.Response
+	requests  []*http.Request
+}
+
+func (c *sequenceHTTPClient) Do(r *http.Request) (*http.Response, error) {
+	idx := len(c.requests)
+	c.requests = append(c.requests, r)
+	if idx < len(c.errs) && c.errs[idx] != nil {
+		return nil, c.errs[idx]
+	}
+	if idx < len(c.responses) {
+		return c.responses[idx], nil
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+}
+
+func newFallbackRoute(fallback *gateway.Fallback) *gateway.Route {
+	return &gateway.Route{
+		ID:       "r1",
+		URI:      url.URL{Scheme: "https", Host: "example.org"},
+		Timeout:  time.Minute,
+		Logger:   slog.New(slog.DiscardHandler),
+		Fallback: fallback,
+	}
+}
+
+func newFallbackRequest(body *gateway.ReplayableBody) *gateway.Request {
+	return &gateway.Request{
+		URL:        &url.URL{Scheme: "https", Host: "example.org", Path: "/test"},
+		Method:     http.MethodPost,
+		Headers:    http.Header{},
+		BodyReader: body,
+	}
+}
+
+func TestGateway_Do_StaticFallback(t *testing.T) {
+	tests := []struct {
+		backendErr     error
+		expectedCause  error
+		name           string
+		expectedBody   string
+		expectedStatus int
+		exposeError    bool
+	}{
+		{
+			name:           "circuit breaker rejection answers with the static response",
+			backendErr:     circuitbreaker.ErrOpenState,
+			expectedCause:  gateway.ErrCircuitBreaker,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"route":"r1","reason":"circuit-breaker","error":"circuit-breaker"}`,
+		},
+		{
+			name:           "backend error answers with the static response",
+			backendErr:     io.EOF,
+			expectedCause:  gateway.ErrHTTP,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody:   `{"route":"r1","reason":"backend-error","error":"backend-error"}`,
+		},
+		{
+			name:           "backend error answers with the error message when the fallback exposes it",
+			backendErr:     io.EOF,
+			expectedCause:  gateway.ErrHTTP,
+			expectedStatus: http.StatusServiceUnavailable,
+			expectedBody: `{"route":"r1","reason":"backend-error",` +
+				`"error":"gateway request for route r1 failed: gateway http request to backend failed: EOF"}`,
+			exposeError: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := newFallbackRoute(&gateway.Fallback{
+				Static: &gateway.StaticResponse{
+					Status:  http.StatusServiceUnavailable,
+					Headers: http.Header{"Content-Type": {"application/json"}},
+					Body:    `{"route":"${route}","reason":"${reason}","error":"${error}"}`,
+				},
+				ExposeError: tt.exposeError,
+			})
+			gw := gateway.NewGateway(&MockHTTPClient{Err: tt.backendErr})
+			ctx, cancel := gateway.NewGatewayContext(t.Context(), route, newFallbackRequest(gateway.NewReplayableBody(nil, 0)))
+			defer cancel()
+
+			if err := gw.Do(ctx); err != nil {
+				t.Fatalf("expected no error, actual %v", err)
+			}
+			if ctx.Response.Status != tt.expectedStatus {
+				t.Errorf("expected status %d, actual %d", tt.expectedStatus, ctx.Response.Status)
+			}
+			if got := ctx.Response.Headers.Get("Content-Type"); got != "application/json" {
+				t.Errorf("expected content type application/json, actual %s", got)
+			}
+			body, _ := io.ReadAll(ctx.Response.BodyReader)
+			if string(body) != tt.expectedBody {
+				t.Errorf("expected body %s, actual %s", tt.expectedBody, body)
+			}
+			cause, _ := ctx.Attributes[gateway.FallbackCauseAttr].(error)
+			if !errors.Is(cause, tt.expectedCause) {
+				t.Errorf("expected fallback cause attribute set, actual %v", cause)
+			}
+		})
+	}
+}
+
+func TestGateway_Do_URIFallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		expectedError string
+		exposeError   bool
+	}{
+		{
+			name:          "fallback request carries the reason without the error message",
+			expectedError: "",
+		},
+		{
+			name:          "fallback request carries the error message when the fallback exposes it",
+			expectedError: "gateway request for route r1 failed: circuit breaker failed: circuit breaker is open",
+			exposeError:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &sequenceHTTPClient{
+				errs: []error{circuitbreaker.ErrOpenState},
+				responses: []*http.Response{
+					nil,
+					{StatusCode: http.StatusOK, Body: http.NoBody},
+				},
+			}
+			route := newFallbackRoute(&gateway.Fallback{
+				URI:         &url.URL{Scheme: "http", Host: "fallback:8080", Path: "/degraded"},
+				ExposeError: tt.exposeError,
+			})
+			gw := gateway.NewGateway(client)
+			ctx, cancel := gateway.NewGatewayContext(t.Context(), route, newFallbackRequest(gateway.NewReplayableBody(nil, 0)))
+			defer cancel()
+
+			if err := gw.Do(ctx); err != nil {
+				t.Fatalf("expected no error, actual %v", err)
+			}
+			if len(client.requests) != 2 {
+				t.Fatalf("expected 2 backend requests, actual %d", len(client.requests))
+			}
+			fallbackReq := client.requests[1]
+			if got := fallbackReq.URL.String(); got != "http://fallback:8080/degraded" {
+				t.Errorf("expected fallback url http://fallback:8080/degraded, actual %s", got)
+			}
+			if got := fallbackReq.Header.Get(gateway.FallbackReasonHeader); got != gateway.FallbackReasonCircuitBreaker {
+				t.Errorf("expected fallback reason %s, actual %s", gateway.FallbackReasonCircuitBreaker, got)
+			}
+			if got := fallbackReq.Header.Get(gateway.FallbackErrorHeader); got != tt.expectedError {
+				t.Errorf("expected fallback error header %q, actual %q", tt.expectedError, got)
+			}
+			if got := gateway.RouteFromContext(fallbackReq.Context()); got != nil {
+				t.Errorf("expected fallback request to bypass the circuit breaker, actual route %v", got.ID)
+			}
+			if ctx.Response.Status != http.StatusOK {
+				t.Errorf("expected status %d, actual %d", http.StatusOK, ctx.Response.Status)
+			}
+		})
+	}
+}
+
+func TestGateway_Do_ForwardFallback(t *testing.T) {
+	client := &sequenceHTTPClient{errs: []error{circuitbreaker.ErrOpenState}}
+	target := &gateway.Route{
+		ID:      "r2",
+		URI:     url.URL{Scheme: "https", Host: "secondary.org"},
+		Timeout: time.Minute,
+		Filters: gateway.Filters{&DummyFilter{ID: "F2"}},
+	}
+	route := newFallbackRoute(&gateway.Fallback{Route: target})
+	gw := gateway.NewGateway(client)
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), route, newFallbackRequest(gateway.NewReplayableBody(nil, 0)))
+	defer cancel()
+
+	if err := gw.Do(ctx); err != nil {
+		t.Fatalf("expected no error, actual %v", err)
+	}
+	if len(client.requests) != 2 {
+		t.Fatalf("expected 2 backend requests, actual %d", len(client.requests))
+	}
+	if got := client.requests[1].URL.Host; got != "secondary.org" {
+		t.Errorf("expected forwarded request to secondary.org, actual %s", got)
+	}
+	if got := gateway.RouteFromContext(client.requests[1].Context()); got == nil || got.ID != "r2" {
+		t.Errorf("expected forwarded request to carry route r2, actual %v", got)
+	}
+	if ctx.Route == target {
+		t.Error("expected the forwarded route to be copied")
+	}
+}
+
+// prefixFilter adds a header to the request and a prefix to its path, like the filters that must not be
+// applied twice.
+type prefixFilter struct {
+	postProcessed int
+}
+
+func (f *prefixFilter) PreProcess(ctx *gateway.Context) error {
+	ctx.Request.Headers.Add("X-Prefix", "added")
+	requestURL := *ctx.Request.URL
+	requestURL.Path = "/prefix" + requestURL.Path
+	ctx.Request.URL = &requestURL
+	return nil
+}
+
+func (f *prefixFilter) PostProcess(_ *gateway.Context) error {
+	f.postProcessed++
+	return nil
+}
+
+func (f *prefixFilter) Name() string {
+	return "Prefix"
+}
+
+func TestGateway_Do_ForwardFallbackRestoresRequest(t *testing.T) {
+	client := &sequenceHTTPClient{errs: []error{io.EOF}}
+	failing := &prefixFilter{}
+	forwarded := &prefixFilter{}
+	target := &gateway.Route{
+		ID:      "r2",
+		URI:     url.URL{Scheme: "https", Host: "secondary.org"},
+		Timeout: time.Minute,
+		Filters: gateway.Filters{forwarded},
+	}
+	route := newFallbackRoute(&gateway.Fallback{Route: target})
+	route.Filters = gateway.Filters{failing}
+	gw := gateway.NewGateway(client)
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), route, newFallbackRequest(gateway.NewReplayableBody(nil, 0)))
+	defer cancel()
+
+	if err := gw.Do(ctx); err != nil {
+		t.Fatalf("expected no error, actual %v", err)
+	}
+	if len(client.requests) != 2 {
+		t.Fatalf("expected 2 backend requests, actual %d", len(client.requests))
+	}
+	forwardedReq := client.requests[1]
+	if got := forwardedReq.URL.Path; got != "/prefix/test" {
+		t.Errorf("expected forwarded path /prefix/test, actual %s", got)
+	}
+	if got := forwardedReq.Header.Values("X-Prefix"); len(got) != 1 {
+		t.Errorf("expected forwarded header added once, actual %v", got)
+	}
+	if got := forwardedReq.Header.Get(gateway.FallbackReasonHeader); got != gateway.FallbackReasonBackendError {
+		t.Errorf("expected fallback reason %s, actual %s", gateway.FallbackReasonBackendError, got)
+	}
+	if failing.postProcessed != 0 || forwarded.postProcessed != 1 {
+		t.Errorf("expected only the forwarded route to post-process, actual %d and %d",
+			failing.postProcessed, forwarded.postProcessed)
+	}
+}
+
+func TestGateway_Do_FallbackNotApplied(t *testing.T) {
+	tests := []struct {
+		backendErr  error
+		body        *gateway.ReplayableBody
+		fallback    *gateway.Fallback
+		name        string
+		expectedErr error
+		requests    int
+	}{
+		{
+			name:        "timeout does not fall back",
+			backendErr:  context.DeadlineExceeded,
+			body:        gateway.NewReplayableBody(nil, 0),
+			fallback:    &gateway.Fallback{URI: &url.URL{Scheme: "http", Host: "fallback"}},
+			expectedErr: context.DeadlineExceeded,
+			requests:    1,
+		},
+		{
+			name:        "client cancellation does not fall back",
+			backendErr:  context.Canceled,
+			body:        gateway.NewReplayableBody(nil, 0),
+			fallback:    &gateway.Fallback{URI: &url.URL{Scheme: "http", Host: "fallback"}},
+			expectedErr: context.Canceled,
+			requests:    1,
+		},
+		{
+			name:       "backend error with a streamed body does not fall back to another backend",
+			backendErr: io.EOF,
+			body: gateway.NewReplayableBody(
+				io.NopCloser(bytes.NewReader([]byte("someBody"))), int64(len("someBody"))),
+			fallback:    &gateway.Fallback{URI: &url.URL{Scheme: "http", Host: "fallback"}},
+			expectedErr: gateway.ErrHTTP,
+			requests:    1,
+		},
+		{
+			name:        "failing forward route does not fall back again",
+			backendErr:  io.EOF,
+			body:        gateway.NewReplayableBody(nil, 0),
+			fallback:    &gateway.Fallback{Route: newFallbackRoute(nil)},
+			expectedErr: gateway.ErrHTTP,
+			requests:    2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.fallback.Route != nil {
+				// The forward target falls back to the original route: only the loop guard stops it.
+				tt.fallback.Route.Fallback = tt.fallback
+			}
+			client := &sequenceHTTPClient{errs: []error{tt.backendErr, tt.backendErr, tt.backendErr}}
+			gw := gateway.NewGateway(client)
+			ctx, cancel := gateway.NewGatewayContext(t.Context(), newFallbackRoute(tt.fallback), newFallbackRequest(tt.body))
+			defer cancel()
+
+			err := gw.Do(ctx)
+
+			if !errors.Is(err, tt.expectedErr) {
+				t.Errorf("expected error %v, actual %v", tt.expectedErr, err)
+			}
+			if len(client.requests) != tt.requests {
+				t.Errorf("expected %d backend requests, actual %d", tt.requests, len(client.requests))
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
 	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
@@ -22,6 +23,19 @@ type HTTPClient interface {
 	Do(r *http.Request) (*http.Response, error)
 }
 
+// BackendLatencyAttr is the name of the attribute that contains the time.Duration the backend took to answer
+// with the response headers, or to fail. The body streaming time is not included.
+const BackendLatencyAttr = "GATEWAY_BACKEND_LATENCY"
+
+// RequestStartAttr is the name of the attribute that contains the time.Time the gateway handler received the
+// request at.
+const RequestStartAttr = "GATEWAY_REQUEST_START"
+
+// QueueDelayAttr is the name of the attribute that contains the time.Duration the request spent inside the
+// gateway, from the RequestStartAttr time, before the backend was called. It is only set when the
+// RequestStartAttr attribute is.
+const QueueDelayAttr = "GATEWAY_QUEUE_DELAY"
+
 const gatewayErrMsg = "gateway request for route %s failed: %w"
 
 // Gateway is the gateway struct. It holds the gateway configuration and the http client.
@@ -37,19 +51,44 @@ func NewGateway(client HTTPClient) *Gateway {
 }
 
 // Do process the gateway request. It will call all pre-process filters, the backend and the post-process filters.
+// When a pre-process filter sets the response, like a redirect, or the route has no backend, the backend
+// is not called: the response of the route without backend is an empty 200 OK unless a filter sets it.
+// The backend latency is recorded in the BackendLatencyAttr attribute, and the queue delay in the QueueDelayAttr
+// attribute, so post-process filters can read them.
+// When the circuit breaker rejects the request or the backend fails, the route fallback answers instead, if any.
 // It will return an error if the gateway request failed.
 // If the gateway request and filters are successful, it will return nil.
 func (g *Gateway) Do(ctx *Context) error {
+	snapshot := snapshotRequest(ctx)
 	if err := ctx.Route.Filters.PreProcessAll(ctx); err != nil {
 		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, err)
 	}
+	if ctx.Response == nil && !ctx.Route.HasBackend() {
+		ctx.Response = NewStaticGatewayResponse(http.StatusOK, nil, "")
+	}
+	if ctx.Response != nil {
+		return g.postProcess(ctx)
+	}
 	backendReq := g.buildProxyRequest(ctx)
+	start := time.Now()
+	if requestStart, ok := ctx.Attributes[RequestStartAttr].(time.Time); ok {
+		ctx.Attributes[QueueDelayAttr] = start.Sub(requestStart)
+	}
 	backendRes, err := g.httpClient.Do(backendReq) //nolint:bodyclose
+	ctx.Attributes[BackendLatencyAttr] = time.Since(start)
 	if err != nil {
-		return g.handleBackendError(ctx, err)
+		err = g.handleBackendError(ctx, err)
+		if ctx.Route.Fallback != nil {
+			return g.doFallback(ctx, err, snapshot)
+		}
+		return err
 	}
 	ctx.Response = NewGatewayResponse(backendRes)
-	if err = ctx.Route.Filters.PostProcessAll(ctx); err != nil {
+	return g.postProcess(ctx)
+}
+
+func (g *Gateway) postProcess(ctx *Context) error {
+	if err := ctx.Route.Filters.PostProcessAll(ctx); err != nil {
 		_ = ctx.Response.BodyReader.Close()
 		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, err)
 	}
@@ -77,6 +116,15 @@ func (g *Gateway) handleBackendError(ctx *Context, err error) error {
 		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, context.DeadlineExceeded)
 	case errors.Is(err, context.Canceled):
 		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, context.Canceled)
+	case errors.Is(err, ErrRequestTooLarge):
+		// The request body went over its size limit while it was sent to the backend.
+		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, ErrRequestTooLarge)
+	case errors.Is(err, ErrDecodedLimitExceeded):
+		// The request body went over its decode limits while it was decompressed for the backend.
+		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, ErrDecodedLimitExceeded)
+	case errors.Is(err, ErrContentDecoding):
+		// The request body could not be decompressed for the backend.
+		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, ErrContentDecoding)
 	case errors.Is(err, circuitbreaker.ErrOpenState) || errors.Is(err, circuitbreaker.ErrHalfOpenRequestExceeded):
 		return fmt.Errorf(gatewayErrMsg, ctx.Route.ID, fmt.Errorf("%w: %s", ErrCircuitBreaker, err.Error()))
 	default:
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"testing"
@@ -639,3 +640,200 @@ func TestGateway_Do_ClosesBackendBodyOnPostProcessError(t *testing.T) {
 		t.Errorf("expected backend body closed once, actual %d", backendBody.closes)
 	}
 }
+
+func TestGateway_Do_RecordsBackendLatency(t *testing.T) {
+	route := &gateway.Route{
+		ID:      "r1",
+		URI:     url.URL{Scheme: "https", Host: "example.org"},
+		Timeout: time.Minute,
+	}
+	request := &gateway.Request{
+		URL:        &url.URL{Scheme: "https", Host: "example.org", Path: "/test"},
+		Method:     http.MethodGet,
+		Headers:    http.Header{},
+		BodyReader: gateway.NewReplayableBody(nil, 0),
+	}
+	gw := gateway.NewGateway(&MockHTTPClient{Err: io.EOF})
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), route, request)
+	defer cancel()
+
+	_ = gw.Do(ctx)
+
+	if _, ok := ctx.Attributes[gateway.BackendLatencyAttr].(time.Duration); !ok {
+		t.Errorf("expected backend latency recorded even when the backend failed, actual %v",
+			ctx.Attributes[gateway.BackendLatencyAttr])
+	}
+}
+
+func TestGateway_Do_RecordsQueueDelay(t *testing.T) {
+	route := &gateway.Route{
+		ID:      "r1",
+		URI:     url.URL{Scheme: "https", Host: "example.org"},
+		Timeout: time.Minute,
+	}
+	request := &gateway.Request{
+		URL:        &url.URL{Scheme: "https", Host: "example.org", Path: "/test"},
+		Method:     http.MethodGet,
+		Headers:    http.Header{},
+		BodyReader: gateway.NewReplayableBody(nil, 0),
+	}
+	gw := gateway.NewGateway(&MockHTTPClient{Err: io.EOF})
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), route, request)
+	defer cancel()
+	ctx.Attributes[gateway.RequestStartAttr] = time.Now().Add(-time.Second)
+
+	_ = gw.Do(ctx)
+
+	if delay, ok := ctx.Attributes[gateway.QueueDelayAttr].(time.Duration); !ok || delay < time.Second {
+		t.Errorf("expected queue delay from the request start recorded, actual %v",
+			ctx.Attributes[gateway.QueueDelayAttr])
+	}
+}
+
+type respondingFilter struct {
+	response   *gateway.Response
+	postStatus int
+}
+
+func (f *respondingFilter) PreProcess(ctx *gateway.Context) error {
+	ctx.Response = f.response
+	return nil
+}
+
+func (f *respondingFilter) PostProcess(ctx *gateway.Context) error {
+	f.postStatus = ctx.Response.Status
+	return nil
+}
+
+func (f *respondingFilter) Name() string {
+	return "Responding"
+}
+
+func TestGateway_Do_WithoutBackend(t *testing.T) {
+	tests := []struct {
+		response       *gateway.Response
+		name           string
+		uri            string
+		expectedStatus int
+		expectedCalled bool
+	}{
+		{
+			name:           "do should not call backend when a filter sets the response",
+			uri:            "https://example.org",
+			response:       gateway.NewStaticGatewayResponse(http.StatusFound, nil, ""),
+			expectedStatus: http.StatusFound,
+		},
+		{
+			name:           "do should answer 200 for a static route",
+			uri:            "static:",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "do should answer 200 for a no op route",
+			uri:            "no://op",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "do should answer filter response for a static route",
+			uri:            "static:",
+			response:       gateway.NewStaticGatewayResponse(http.StatusServiceUnavailable, nil, "maintenance"),
+			expectedStatus: http.StatusServiceUnavailable,
+		},
+		{
+			name:           "do should call backend when no filter sets the response",
+			uri:            "https://example.org",
+			expectedStatus: http.StatusAccepted,
+			expectedCalled: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter := &respondingFilter{response: tt.response}
+			route, _ := gateway.NewRoute("r1", tt.uri, nil, nil, gateway.Filters{filter}, time.Minute, nil, nil)
+			request := &gateway.Request{
+				URL:        &url.URL{Scheme: "https", Host: "example.org", Path: "/test"},
+				Method:     http.MethodGet,
+				Headers:    http.Header{},
+				BodyReader: gateway.NewReplayableBody(nil, 0),
+			}
+			client := &captureHTTPClient{response: &http.Response{StatusCode: http.StatusAccepted, Header: http.Header{}}}
+			ctx, cancel := gateway.NewGatewayContext(t.Context(), route, request)
+			defer cancel()
+
+			if err := gateway.NewGateway(client).Do(ctx); err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			if ctx.Response.Status != tt.expectedStatus || filter.postStatus != tt.expectedStatus {
+				t.Errorf("expected status %d actual %d post processed %d",
+					tt.expectedStatus, ctx.Response.Status, filter.postStatus)
+			}
+			if (client.captured != nil) != tt.expectedCalled {
+				t.Errorf("expected backend called %v actual %v", tt.expectedCalled, client.captured != nil)
+			}
+		})
+	}
+}
+
+func TestGateway_Do_RequestBodyOverLimit(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+	route := &gateway.Route{ID: "r1", URI: *backendURL, Timeout: time.Minute}
+	body := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(bytes.Repeat([]byte("a"), 64*1024))), -1)
+	if err := body.Limit(1024, gateway.ErrRequestTooLarge); err != nil {
+		t.Fatalf("limit failed: %v", err)
+	}
+	request := &gateway.Request{
+		URL:        &url.URL{Path: "/upload"},
+		Method:     http.MethodPost,
+		Headers:    http.Header{},
+		BodyReader: body,
+	}
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), route, request)
+	defer cancel()
+
+	err := gateway.NewGateway(backend.Client()).Do(ctx)
+
+	if !errors.Is(err, gateway.ErrRequestTooLarge) {
+		t.Fatalf("expected ErrRequestTooLarge, actual %v", err)
+	}
+	if errors.Is(err, gateway.ErrHTTP) {
+		t.Errorf("expected the error not to be a backend failure, actual %v", err)
+	}
+}
+
+func TestGateway_Do_RequestBodyOverDecodeLimits(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.Copy(io.Discard, r.Body)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+	backendURL, _ := url.Parse(backend.URL)
+	route := &gateway.Route{ID: "r1", URI: *backendURL, Timeout: time.Minute}
+	encoded := gzipBytes(t, bytes.Repeat([]byte("a"), 64*1024))
+	decoder, err := gateway.NewContentDecoder("gzip", bytes.NewReader(encoded), gateway.DecodeLimits{MaxBytes: 1024})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	request := &gateway.Request{
+		URL:        &url.URL{Path: "/upload"},
+		Method:     http.MethodPost,
+		Headers:    http.Header{},
+		BodyReader: gateway.NewReplayableBody(decoder, -1),
+	}
+	ctx, cancel := gateway.NewGatewayContext(t.Context(), route, request)
+	defer cancel()
+
+	err = gateway.NewGateway(backend.Client()).Do(ctx)
+
+	if !errors.Is(err, gateway.ErrDecodedLimitExceeded) {
+		t.Fatalf("expected ErrDecodedLimitExceeded, actual %v", err)
+	}
+	if errors.Is(err, gateway.ErrHTTP) {
+		t.Errorf("expected the error not to be a backend failure, actual %v", err)
+	}
+}
@@ -1,9 +1,14 @@
 package gateway
 
 import (
+	"maps"
 	"net/http"
 )
 
+// PathVariablesAttr is the name of the attribute that contains the path variables captured by the
+// predicates of the route, as a map[string]string.
+const PathVariablesAttr = "GATEWAY_PATH_VARIABLES"
+
 // Predicate represents a gateway predicate.
 type Predicate interface {
 	// Test returns true if the request should be forwarded to the backend.
@@ -11,6 +16,78 @@ type Predicate interface {
 	Name() string
 }
 
+// StatefulPredicate is a predicate whose decision depends on the other predicates tested for the same
+// request, such as the routes of a weighted group. They share a MatchState, so they decide once per request.
+type StatefulPredicate interface {
+	Predicate
+	// TestWithState returns true if the request should be forwarded to the backend. The state is shared
+	// by all the predicates tested for the request.
+	TestWithState(request *http.Request, state *MatchState) bool
+}
+
+// MatchState is the state the predicates share while a request is matched against the routes.
+type MatchState struct {
+	values    map[any]any
+	variables map[string]string
+}
+
+// Load returns the value stored under the key, if any.
+func (s *MatchState) Load(key any) (any, bool) {
+	value, ok := s.values[key]
+	return value, ok
+}
+
+// Store stores the value under the key.
+func (s *MatchState) Store(key, value any) {
+	if s.values == nil {
+		s.values = make(map[any]any)
+	}
+	s.values[key] = value
+}
+
+// SetPathVariables adds the path variables captured by a predicate for the route being tested. They
+// are dropped when the route does not match.
+func (s *MatchState) SetPathVariables(variables map[string]string) {
+	if s.variables == nil {
+		s.variables = variables
+		return
+	}
+	maps.Copy(s.variables, variables)
+}
+
+// SnapshotPathVariables returns a copy of the path variables captured so far, to be given back to
+// RestorePathVariables.
+func (s *MatchState) SnapshotPathVariables() map[string]string {
+	if s == nil {
+		return nil
+	}
+	return maps.Clone(s.variables)
+}
+
+// RestorePathVariables replaces the path variables with a snapshot taken by SnapshotPathVariables,
+// dropping the ones captured since, like the ones of a branch of an Or predicate that did not match.
+func (s *MatchState) RestorePathVariables(snapshot map[string]string) {
+	if s == nil {
+		return
+	}
+	s.variables = snapshot
+}
+
+// PathVariables returns the path variables captured for the route being tested, if any.
+func (s *MatchState) PathVariables() map[string]string {
+	if s == nil {
+		return nil
+	}
+	return s.variables
+}
+
+// PathVariables returns the path variables captured by the predicates of the route of the context,
+// if any.
+func PathVariables(ctx *Context) map[string]string {
+	variables, _ := ctx.Attributes[PathVariablesAttr].(map[string]string)
+	return variables
+}
+
 // PredicateBuilder represents a predicate builder.
 type PredicateBuilder interface {
 	// The Build method is called to build a predicate with the given arguments. The arguments are passed from the
@@ -50,11 +127,33 @@ type Predicates []Predicate
 //
 // The order of the predicates in the list is important. The first predicate in the list is called first. The last
 // predicate in the list is called last.
+//
+// The stateful predicates share a MatchState for the call only. TestAllWithState shares it across calls.
 func (p Predicates) TestAll(req *http.Request) bool {
+	var state *MatchState
+	return p.TestAllWithState(req, &state)
+}
+
+// TestAllWithState is TestAll with a MatchState shared with other calls for the same request. The state
+// is only allocated, into *state, once a stateful predicate is tested.
+func (p Predicates) TestAllWithState(req *http.Request, state **MatchState) bool {
 	for _, predicate := range p {
-		if !predicate.Test(req) {
+		if !TestWithState(predicate, req, state) {
 			return false
 		}
 	}
 	return true
 }
+
+// TestWithState tests the predicate, with the MatchState if it is a stateful one. The state is only
+// allocated, into *state, once a stateful predicate is tested.
+func TestWithState(predicate Predicate, req *http.Request, state **MatchState) bool {
+	stateful, ok := predicate.(StatefulPredicate)
+	if !ok {
+		return predicate.Test(req)
+	}
+	if *state == nil {
+		*state = &MatchState{}
+	}
+	return stateful.TestWithState(req, *state)
+}
@@ -2,6 +2,7 @@ package gateway_test
 
 import (
 	"net/http"
+	"reflect"
 	"testing"
 
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -83,3 +84,121 @@ func TestPredicateBuilderRegistry_Register(t *testing.T) {
 		t.Errorf("expected 1 actual %d", len(registry))
 	}
 }
+
+// countingPredicate matches the requests of the first route it is tested for, counting the
+// decisions it shares through the match state.
+type countingPredicate struct {
+	decisions *int
+	id        string
+}
+
+func (c countingPredicate) Test(req *http.Request) bool {
+	return c.TestWithState(req, &gateway.MatchState{})
+}
+
+func (c countingPredicate) TestWithState(_ *http.Request, state *gateway.MatchState) bool {
+	chosen, ok := state.Load("group")
+	if !ok {
+		*c.decisions++
+		chosen = c.id
+		state.Store("group", chosen)
+	}
+	return chosen == c.id
+}
+
+func (c countingPredicate) Name() string {
+	return "counting"
+}
+
+func TestRoutes_FindMatching_SharesMatchState(t *testing.T) {
+	decisions := 0
+	routes := gateway.Routes{
+		{ID: "r1", Predicates: gateway.Predicates{DummyPredicate{false}, countingPredicate{&decisions, "r1"}}},
+		{ID: "r2", Predicates: gateway.Predicates{countingPredicate{&decisions, "r2"}}},
+		{ID: "r3", Predicates: gateway.Predicates{countingPredicate{&decisions, "r3"}}},
+	}
+
+	route := routes.FindMatching(&http.Request{})
+
+	if route == nil || route.ID != "r2" {
+		t.Fatalf("expected route r2, actual %v", route)
+	}
+	if decisions != 1 {
+		t.Errorf("expected a single decision per request, actual %d", decisions)
+	}
+	if !routes[2].Predicates.TestAll(&http.Request{}) {
+		t.Error("expected test all to decide on its own state")
+	}
+}
+
+// capturingPredicate captures the given path variables for every request.
+type capturingPredicate map[string]string
+
+func (c capturingPredicate) Test(_ *http.Request) bool {
+	return true
+}
+
+func (c capturingPredicate) TestWithState(_ *http.Request, state *gateway.MatchState) bool {
+	state.SetPathVariables(map[string]string(c))
+	return true
+}
+
+func (c capturingPredicate) Name() string {
+	return "capturing"
+}
+
+func TestRoutes_FindMatchingWithVariables(t *testing.T) {
+	routes := gateway.Routes{
+		{ID: "r1", Predicates: gateway.Predicates{capturingPredicate{"id": "1"}, DummyPredicate{false}}},
+		{ID: "r2", Predicates: gateway.Predicates{capturingPredicate{"user": "2"}, capturingPredicate{"order": "3"}}},
+		{ID: "r3", Predicates: gateway.Predicates{DummyPredicate{true}}},
+	}
+	tests := []struct {
+		expected      map[string]string
+		name          string
+		expectedRoute string
+		routes        gateway.Routes
+	}{
+		{
+			name:          "find matching should return the variables of the matching route only",
+			routes:        routes,
+			expectedRoute: "r2",
+			expected:      map[string]string{"user": "2", "order": "3"},
+		},
+		{
+			name:          "find matching should drop the variables of the routes not matching",
+			routes:        gateway.Routes{routes[0], routes[2]},
+			expectedRoute: "r3",
+			expected:      nil,
+		},
+		{
+			name:          "find matching should return no variables when no route matches",
+			routes:        gateway.Routes{routes[0]},
+			expectedRoute: "",
+			expected:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route, variables := tt.routes.FindMatchingWithVariables(&http.Request{})
+
+			if (route == nil && tt.expectedRoute != "") || (route != nil && route.ID != tt.expectedRoute) {
+				t.Errorf("expected route %s actual %v", tt.expectedRoute, route)
+			}
+			if !reflect.DeepEqual(tt.expected, variables) {
+				t.Errorf("expected variables %v actual %v", tt.expected, variables)
+			}
+		})
+	}
+}
+
+func TestPathVariables(t *testing.T) {
+	ctx := &gateway.Context{Attributes: map[string]any{}}
+	if variables := gateway.PathVariables(ctx); variables != nil {
+		t.Errorf("expected no variables actual %v", variables)
+	}
+	ctx.Attributes[gateway.PathVariablesAttr] = map[string]string{"id": "42"}
+	if variables := gateway.PathVariables(ctx); variables["id"] != "42" {
+		t.Errorf("expected variable id 42 actual %v", variables)
+	}
+}
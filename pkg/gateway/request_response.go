@@ -2,11 +2,13 @@ package gateway
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"sync"
 
 	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
@@ -18,6 +20,12 @@ var ErrCapture = errors.New("capture body failed")
 // ErrCaptureLimitExceeded represents the error when the body is larger than the capture limit.
 var ErrCaptureLimitExceeded = errors.New("capture limit exceeded")
 
+// ErrRequestTooLarge represents the error when the request body is larger than its size limit.
+var ErrRequestTooLarge = errors.New("request body too large")
+
+// ErrResponseTooLarge represents the error when the response body is larger than its size limit.
+var ErrResponseTooLarge = errors.New("response body too large")
+
 //nolint:gochecknoglobals
 var bytesBufferPool = sync.Pool{
 	New: func() any {
@@ -41,25 +49,51 @@ var copyBufPool = sync.Pool{
 // The body of the request is read into memory and stored in the body field.
 //
 // The body field is nil if the original request body is empty.
+//
+// Host is the host the client addressed and Scheme the scheme it used, http or https, which the URL of a
+// server request does not carry.
+//
+// RemoteAddr is the client IP address resolved by shared.GetRemoteAddr, which trusts the forwarded
+// headers. PeerAddr is the address of the connection peer, as host:port, which the client cannot choose.
 type Request struct {
 	URL        *url.URL
 	Headers    http.Header
 	BodyReader *ReplayableBody
 	Method     string
+	Host       string
+	Scheme     string
 	RemoteAddr string
+	PeerAddr   string
 }
 
 // NewGatewayRequest creates a new gateway request from an http request.
 func NewGatewayRequest(request *http.Request) *Request {
 	return &Request{
 		RemoteAddr: shared.GetRemoteAddr(request),
+		PeerAddr:   request.RemoteAddr,
 		URL:        request.URL,
 		Method:     request.Method,
+		Host:       request.Host,
+		Scheme:     shared.RequestScheme(request),
 		Headers:    request.Header,
 		BodyReader: NewReplayableBody(request.Body, request.ContentLength),
 	}
 }
 
+// AsHTTPRequest returns an http.Request view of the gateway request, sharing its URL and
+// headers, so that predicates can be evaluated from filters. The body is not carried: a
+// predicate must never consume it.
+func (r *Request) AsHTTPRequest(ctx context.Context) *http.Request {
+	request := &http.Request{
+		Method:     r.Method,
+		URL:        r.URL,
+		Host:       r.Host,
+		Header:     r.Headers,
+		RemoteAddr: r.RemoteAddr,
+	}
+	return request.WithContext(ctx)
+}
+
 // Response represents a gateway response.
 //
 // The body of the response is read into memory and stored in the body field.
@@ -82,14 +116,30 @@ func NewGatewayResponse(response *http.Response) *Response {
 	}
 }
 
+// NewStaticGatewayResponse creates a new gateway response built by the gateway itself, with the given status,
+// headers and body. Nil headers are replaced by empty ones.
+func NewStaticGatewayResponse(status int, headers http.Header, body string) *Response {
+	if headers == nil {
+		headers = http.Header{}
+	}
+	return &Response{
+		Status:     status,
+		Headers:    headers,
+		BodyReader: NewReplayableBody(io.NopCloser(strings.NewReader(body)), int64(len(body))),
+	}
+}
+
 // ReplayableBody creates a new representation of the body that can be read multiple times.
 type ReplayableBody struct {
 	original io.ReadCloser
 	reader   *bytes.Reader
 	data     []byte
+	decoded  []byte
 	length   int64
 	captured bool
 	closed   bool
+	// sized is set on the bodies of unknown length rewritten from a body of known length.
+	sized bool
 }
 
 // NewReplayableBody initializes a ReplayableBody allowing multiple reads from the same body by buffering the content.
@@ -104,6 +154,15 @@ func NewReplayableBody(original io.ReadCloser, length int64) *ReplayableBody {
 	}
 }
 
+// NewRewrittenReplayableBody creates the ReplayableBody of a filter rewriting the source body as it is read,
+// like a compressing one, whose length is not known in advance. It is a stream, see IsStream, only when the
+// source is one.
+func NewRewrittenReplayableBody(original io.ReadCloser, source *ReplayableBody) *ReplayableBody {
+	body := NewReplayableBody(original, -1)
+	body.sized = !source.IsStream()
+	return body
+}
+
 // Read reads data into the provided byte slice p and captures it into an internal buffer if not already captured.
 // Returns the number of bytes read and any error encountered during the read operation.
 func (rb *ReplayableBody) Read(output []byte) (int, error) {
@@ -164,6 +223,42 @@ func (rb *ReplayableBody) CaptureWithLimit(maxBytes int64) error {
 	return nil
 }
 
+// CaptureDecoded captures at most maxBytes of body content like CaptureWithLimit, and decodes it from the
+// content codings of the Content-Encoding header value into a separate view, returned by DecodedBytes, for
+// the filters that inspect the body. The body is still forwarded as it was received.
+//
+// When the decoded body goes over the decode limits, the view keeps the decoded prefix up to the limit. When
+// the body cannot be decoded, it returns an error wrapping the decoding error, but the body remains captured.
+func (rb *ReplayableBody) CaptureDecoded(maxBytes int64, contentEncoding string, limits DecodeLimits) error {
+	if err := rb.CaptureWithLimit(maxBytes); err != nil {
+		return err
+	}
+	if rb.decoded != nil {
+		return nil
+	}
+	if len(ContentCodings(contentEncoding)) == 0 {
+		rb.decoded = rb.data
+		return nil
+	}
+	decoder, err := NewContentDecoder(contentEncoding, bytes.NewReader(rb.data), limits)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrCapture, err)
+	}
+	defer decoder.Close() //nolint:errcheck
+	decoded, err := io.ReadAll(decoder)
+	if err != nil && !errors.Is(err, ErrDecodedLimitExceeded) {
+		return fmt.Errorf("%w: %w", ErrCapture, err)
+	}
+	rb.decoded = decoded
+	return nil
+}
+
+// DecodedBytes returns the decoded view of the body made by CaptureDecoded, or nil when the body has not been
+// decoded. Callers must treat it as read-only.
+func (rb *ReplayableBody) DecodedBytes() []byte {
+	return rb.decoded
+}
+
 // Bytes returns the captured body content, or nil when the body has not been
 // captured. The slice is the backing array of the replay reader: callers must
 // treat it as read-only.
@@ -215,11 +310,57 @@ func (p *prefixedReadCloser) Close() error {
 	return p.closer.Close() //nolint:wrapcheck
 }
 
+// Limit bounds the body to maxBytes. It returns tooLarge when the declared or captured length of the body
+// is already larger. Otherwise, the body fails with tooLarge once more than maxBytes are read from it, so the
+// limit also holds for bodies of unknown length, like chunked ones.
+func (rb *ReplayableBody) Limit(maxBytes int64, tooLarge error) error {
+	if rb.length > maxBytes || int64(len(rb.data)) > maxBytes {
+		return tooLarge
+	}
+	if !rb.captured {
+		rb.original = &limitedReadCloser{ReadCloser: rb.original, remaining: maxBytes, tooLarge: tooLarge}
+	}
+	return nil
+}
+
+// limitedReadCloser fails with tooLarge once more than the remaining bytes are read.
+type limitedReadCloser struct {
+	io.ReadCloser
+
+	tooLarge  error
+	remaining int64
+	exceeded  bool
+}
+
+func (l *limitedReadCloser) Read(output []byte) (int, error) {
+	if l.exceeded {
+		return 0, l.tooLarge
+	}
+	// One extra byte to distinguish a body of exactly the limit from a larger one.
+	if int64(len(output)) > l.remaining+1 {
+		output = output[:l.remaining+1]
+	}
+	read, err := l.ReadCloser.Read(output)
+	if int64(read) > l.remaining {
+		l.exceeded = true
+		return int(l.remaining), l.tooLarge
+	}
+	l.remaining -= int64(read)
+	return read, err //nolint:wrapcheck
+}
+
 // Len returns the body length.
 func (rb *ReplayableBody) Len() int64 {
 	return rb.length
 }
 
+// IsStream reports whether the body is a stream of unknown length, like a chunked backend response, that
+// must reach the client as it is produced. A body rewritten from a body of known length is not one, even
+// if its own length is unknown.
+func (rb *ReplayableBody) IsStream() bool {
+	return rb.length == -1 && !rb.sized
+}
+
 // Close releases any resources associated with the ReplayableBody and closes the underlying source.
 // It is idempotent: only the first call closes the underlying source.
 // A captured body remains replayable after Close.
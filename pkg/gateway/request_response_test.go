@@ -25,6 +25,7 @@ func TestNewGatewayRequest(t *testing.T) {
 			name: "new gateway should succeed",
 			request: &http.Request{
 				Method: http.MethodGet,
+				Host:   "example.org",
 				URL: &url.URL{
 					Scheme:   "https",
 					Host:     "example.org",
@@ -34,6 +35,7 @@ func TestNewGatewayRequest(t *testing.T) {
 				Header: map[string][]string{
 					"h1": {"value1"},
 				},
+				RemoteAddr:    "203.0.113.7:4321",
 				ContentLength: int64(len(`{"p1":"v1"}`)),
 				Body:          io.NopCloser(bytes.NewBuffer([]byte("{\"p1\":\"v1\"}"))),
 			},
@@ -44,7 +46,11 @@ func TestNewGatewayRequest(t *testing.T) {
 					Path:     "/server/test",
 					RawQuery: "key=value",
 				},
-				Method: http.MethodGet,
+				Method:     http.MethodGet,
+				Host:       "example.org",
+				Scheme:     "http",
+				RemoteAddr: "203.0.113.7",
+				PeerAddr:   "203.0.113.7:4321",
 				Headers: map[string][]string{
 					"h1": {"value1"},
 				},
@@ -75,6 +81,7 @@ func TestNewGatewayRequest(t *testing.T) {
 					RawQuery: "key=value",
 				},
 				Method: http.MethodGet,
+				Scheme: "http",
 				Headers: map[string][]string{
 					"h1": {"value1"},
 				},
@@ -380,6 +387,142 @@ func TestReplayableBody_CaptureWithLimit(t *testing.T) {
 	})
 }
 
+func TestReplayableBody_CaptureDecoded(t *testing.T) {
+	payload := []byte("decoded body")
+	encoded := gzipBytes(t, payload)
+
+	t.Run("encoded body is decoded into a separate view and forwarded as received", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(encoded)), int64(len(encoded)))
+
+		if err := rb.CaptureDecoded(-1, "gzip", gateway.DecodeLimits{}); err != nil {
+			t.Fatalf("capture failed: %v", err)
+		}
+		if !bytes.Equal(rb.DecodedBytes(), payload) {
+			t.Errorf("expected decoded view %q, actual %q", payload, rb.DecodedBytes())
+		}
+		got, err := io.ReadAll(rb)
+		if err != nil || !bytes.Equal(got, encoded) {
+			t.Errorf("expected forwarded body to be the encoded one, actual %q (err %v)", got, err)
+		}
+	})
+
+	t.Run("body without encoding is its own decoded view", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), int64(len(payload)))
+
+		if err := rb.CaptureDecoded(-1, "", gateway.DecodeLimits{}); err != nil {
+			t.Fatalf("capture failed: %v", err)
+		}
+		if !bytes.Equal(rb.DecodedBytes(), payload) {
+			t.Errorf("expected decoded view %q, actual %q", payload, rb.DecodedBytes())
+		}
+	})
+
+	t.Run("body that cannot be decoded remains captured", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), int64(len(payload)))
+
+		err := rb.CaptureDecoded(-1, "gzip", gateway.DecodeLimits{})
+		if !errors.Is(err, gateway.ErrContentDecoding) {
+			t.Fatalf("expected ErrContentDecoding, actual %v", err)
+		}
+		if rb.DecodedBytes() != nil || !bytes.Equal(rb.Bytes(), payload) {
+			t.Errorf("expected captured body %q without decoded view, actual %q and %q",
+				payload, rb.Bytes(), rb.DecodedBytes())
+		}
+	})
+
+	t.Run("decoded view over the limits keeps the decoded prefix", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(encoded)), int64(len(encoded)))
+
+		if err := rb.CaptureDecoded(-1, "gzip", gateway.DecodeLimits{MaxBytes: 4}); err != nil {
+			t.Fatalf("capture failed: %v", err)
+		}
+		if !bytes.Equal(rb.DecodedBytes(), payload[:4]) {
+			t.Errorf("expected decoded view %q, actual %q", payload[:4], rb.DecodedBytes())
+		}
+	})
+
+	t.Run("body over the capture limit is not captured", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(encoded)), -1)
+
+		err := rb.CaptureDecoded(4, "gzip", gateway.DecodeLimits{})
+		if !errors.Is(err, gateway.ErrCaptureLimitExceeded) {
+			t.Fatalf("expected ErrCaptureLimitExceeded, actual %v", err)
+		}
+		got, readErr := io.ReadAll(rb)
+		if readErr != nil || !bytes.Equal(got, encoded) {
+			t.Errorf("expected body fully forwardable after rejection, actual %q (err %v)", got, readErr)
+		}
+	})
+}
+
+func TestReplayableBody_Limit(t *testing.T) {
+	payload := []byte("0123456789")
+
+	t.Run("declared length over the limit is rejected", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), int64(len(payload)))
+
+		if err := rb.Limit(int64(len(payload))-1, gateway.ErrRequestTooLarge); !errors.Is(err, gateway.ErrRequestTooLarge) {
+			t.Fatalf("expected ErrRequestTooLarge, actual %v", err)
+		}
+	})
+
+	t.Run("captured body over the limit is rejected", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), -1)
+		if err := rb.Capture(); err != nil {
+			t.Fatalf("capture failed: %v", err)
+		}
+
+		if err := rb.Limit(4, gateway.ErrResponseTooLarge); !errors.Is(err, gateway.ErrResponseTooLarge) {
+			t.Fatalf("expected ErrResponseTooLarge, actual %v", err)
+		}
+	})
+
+	t.Run("unknown length within the limit is fully readable", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), -1)
+
+		if err := rb.Limit(int64(len(payload)), gateway.ErrRequestTooLarge); err != nil {
+			t.Fatalf("limit failed: %v", err)
+		}
+		got, err := io.ReadAll(rb)
+		if err != nil || !bytes.Equal(got, payload) {
+			t.Errorf("expected body %q, actual %q (err %v)", payload, got, err)
+		}
+	})
+
+	t.Run("unknown length over the limit fails while streaming", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), -1)
+
+		if err := rb.Limit(4, gateway.ErrRequestTooLarge); err != nil {
+			t.Fatalf("limit failed: %v", err)
+		}
+		got, err := io.ReadAll(rb)
+		if !errors.Is(err, gateway.ErrRequestTooLarge) {
+			t.Fatalf("expected ErrRequestTooLarge, actual %v", err)
+		}
+		if !bytes.Equal(got, payload[:4]) {
+			t.Errorf("expected only the bytes within the limit %q, actual %q", payload[:4], got)
+		}
+		if _, err = rb.Read(make([]byte, 1)); !errors.Is(err, gateway.ErrRequestTooLarge) {
+			t.Errorf("expected later reads to keep failing, actual %v", err)
+		}
+	})
+
+	t.Run("unknown length over the limit fails while writing", func(t *testing.T) {
+		rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(payload)), -1)
+
+		if err := rb.Limit(4, gateway.ErrResponseTooLarge); err != nil {
+			t.Fatalf("limit failed: %v", err)
+		}
+		var out bytes.Buffer
+		if _, err := rb.WriteTo(&out); !errors.Is(err, gateway.ErrResponseTooLarge) {
+			t.Fatalf("expected ErrResponseTooLarge, actual %v", err)
+		}
+		if out.String() != "0123" {
+			t.Errorf("expected written body %q, actual %q", "0123", out.String())
+		}
+	})
+}
+
 func TestReplayableBody_Capture_DoesNotAliasPooledBuffer(t *testing.T) {
 	first := bytes.Repeat([]byte("A"), 1024)
 	second := bytes.Repeat([]byte("B"), 1024)
@@ -468,6 +611,42 @@ func TestReplayableBody_Len(t *testing.T) {
 	}
 }
 
+func TestReplayableBody_IsStream(t *testing.T) {
+	tests := []struct {
+		body     *gateway.ReplayableBody
+		name     string
+		expected bool
+	}{
+		{
+			name:     "body of known length should not be a stream",
+			body:     gateway.NewReplayableBody(nil, 10),
+			expected: false,
+		},
+		{
+			name:     "body of unknown length should be a stream",
+			body:     gateway.NewReplayableBody(nil, -1),
+			expected: true,
+		},
+		{
+			name:     "body rewritten from a body of known length should not be a stream",
+			body:     gateway.NewRewrittenReplayableBody(nil, gateway.NewReplayableBody(nil, 10)),
+			expected: false,
+		},
+		{
+			name:     "body rewritten from a stream should be a stream",
+			body:     gateway.NewRewrittenReplayableBody(nil, gateway.NewReplayableBody(nil, -1)),
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.body.IsStream() != tt.expected {
+				t.Errorf("expected stream %t actual %t", tt.expected, tt.body.IsStream())
+			}
+		})
+	}
+}
+
 func TestReplayableBody_Bytes(t *testing.T) {
 	content := []byte("hello world")
 	rb := gateway.NewReplayableBody(io.NopCloser(bytes.NewReader(content)), int64(len(content)))
@@ -525,3 +704,25 @@ func TestReplayableBody_WriteTo(t *testing.T) {
 		}
 	})
 }
+
+func TestRequest_AsHTTPRequest(t *testing.T) {
+	gwReq := &gateway.Request{
+		URL:        &url.URL{Path: "/server/test"},
+		Headers:    http.Header{"H1": {"value1"}},
+		Method:     http.MethodPost,
+		Host:       "example.org",
+		RemoteAddr: "10.0.0.1",
+	}
+
+	req := gwReq.AsHTTPRequest(t.Context())
+
+	if req.Method != http.MethodPost || req.Host != "example.org" || req.RemoteAddr != "10.0.0.1" {
+		t.Errorf("expected method, host and remote addr carried, actual %s %s %s", req.Method, req.Host, req.RemoteAddr)
+	}
+	if req.URL != gwReq.URL || req.Header.Get("H1") != "value1" {
+		t.Errorf("expected url and headers shared, actual %v %v", req.URL, req.Header)
+	}
+	if req.Body != nil {
+		t.Errorf("expected no body, actual %v", req.Body)
+	}
+}
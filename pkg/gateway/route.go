@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
 	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
 )
 
@@ -21,18 +22,33 @@ type CircuitBreaker[T any] interface {
 	Execute(req func() (T, error)) (T, error)
 }
 
+// These are the route URIs of the routes without a backend, like 'static:' and 'no://op'. The response of
+// such routes is built by their filters, like a redirect, or is an empty 200 OK.
+const (
+	StaticRouteScheme = "static"
+	NoOpRouteScheme   = "no"
+	NoOpRouteHost     = "op"
+)
+
 // Route represents a gateway route.
 //
 // URI is a value on purpose: the shallow copy FindMatching hands to each request
 // covers it, so a filter mutating it cannot corrupt the shared route table.
+//
+// Metadata is the arbitrary data of the route config, like the owning team or the SLA tier. Filters and
+// error handlers read it from the Route of the gateway context, and loggers and metrics from any context
+// descending from it, with RouteFromContext.
 type Route struct {
 	CircuitBreaker CircuitBreaker[*http.Response]
+	Fallback       *Fallback
+	Metadata       map[string]any
 	URI            url.URL
 	Logger         *slog.Logger
 	ID             string
 	Predicates     Predicates
 	Filters        Filters
 	Timeout        time.Duration
+	Order          int
 }
 
 // NewRoute creates a new route.
@@ -60,6 +76,17 @@ func NewRoute(
 	}, nil
 }
 
+// HasBackend checks if the route calls a backend, that is, if its URI is neither 'static:' nor 'no://op'.
+func (r *Route) HasBackend() bool {
+	return r.URI.Scheme != StaticRouteScheme && (r.URI.Scheme != NoOpRouteScheme || r.URI.Host != NoOpRouteHost)
+}
+
+// MetadataValue returns the metadata value of the route under the given key. The nested values are looked up
+// with dotted keys, like 'sla.tier'.
+func (r *Route) MetadataValue(key string) (any, bool) {
+	return shared.LookupKey(r.Metadata, key)
+}
+
 // GetDestinationURL returns the destination url for the given request url combining scheme and host from the route
 // uri and the rest of elements from the request url.
 //
@@ -89,15 +116,30 @@ type Routes []Route
 //
 // The returned route is a shallow copy: a filter that mutates its value fields
 // (ID, Timeout, URI) by mistake corrupts only its own request, never the shared
-// route table. Pointer fields (Logger, CircuitBreaker, the predicate and filter
-// slices) are still shared across all requests and must be treated as read-only.
+// route table. Pointer fields (Logger, CircuitBreaker, Fallback, Metadata, the predicate
+// and filter slices) are still shared across all requests and must be treated as read-only.
+//
+// The stateful predicates of all the routes tested share a single MatchState.
+//
+// Every route is tested in turn: a RouteIndex finds the same route testing fewer routes.
 func (r Routes) FindMatching(req *http.Request) *Route {
+	route, _ := r.FindMatchingWithVariables(req)
+	return route
+}
+
+// FindMatchingWithVariables is FindMatching also returning the path variables captured by the
+// predicates of the matching route, if any.
+func (r Routes) FindMatchingWithVariables(req *http.Request) (*Route, map[string]string) {
+	var state *MatchState
 	for i := range r {
-		if r[i].Predicates.TestAll(req) {
+		if r[i].Predicates.TestAllWithState(req, &state) {
 			// only the matched route is copied, not every scanned candidate
 			route := r[i]
-			return new(route)
+			return new(route), state.PathVariables()
+		}
+		if state != nil {
+			state.variables = nil
 		}
 	}
-	return nil
+	return nil, nil
 }
@@ -2,11 +2,13 @@ package gateway_test
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"strconv"
 	"testing"
 
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
 )
 
 func BenchmarkRoutesFindMatching(b *testing.B) {
@@ -45,3 +47,51 @@ func BenchmarkGetDestinationURL_Optimized(b *testing.B) {
 		_ = r.GetDestinationURL(u)
 	}
 }
+
+// newLargeRouteTable returns routes like a gateway fronting many services: a method and a path
+// prefix per route, and a catch-all route last.
+func newLargeRouteTable(numRoutes int) gateway.Routes {
+	routes := make(gateway.Routes, 0, numRoutes+1)
+	for i := range numRoutes {
+		method := http.MethodGet
+		if i%2 == 1 {
+			method = http.MethodPost
+		}
+		routes = append(routes, gateway.Route{
+			ID: "r" + strconv.Itoa(i),
+			Predicates: gateway.Predicates{
+				predicate.NewMethodPredicate(method),
+				predicate.NewPathPredicate("/api/service-" + strconv.Itoa(i/2) + "/**"),
+			},
+		})
+	}
+	return append(routes, gateway.Route{
+		ID:         "catch-all",
+		Predicates: gateway.Predicates{predicate.NewPathPredicate("/**")},
+	})
+}
+
+func BenchmarkRoutesFindMatching_LargeTable(b *testing.B) {
+	for _, numRoutes := range []int{50, 500} {
+		routes := newLargeRouteTable(numRoutes)
+		index := gateway.NewRouteIndex(routes)
+		// the route near the end of the table: worst case scan
+		req := httptest.NewRequest(http.MethodPost, "/api/service-"+strconv.Itoa(numRoutes/2-1)+"/orders/42", nil)
+		b.Run("scan/"+strconv.Itoa(numRoutes), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if routes.FindMatching(req) == nil {
+					b.Fatal("expected a match")
+				}
+			}
+		})
+		b.Run("index/"+strconv.Itoa(numRoutes), func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				if index.FindMatching(req) == nil {
+					b.Fatal("expected a match")
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package gateway
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// IndexKind is the request attribute an IndexablePredicate restricts.
+type IndexKind int
+
+const (
+	// IndexPath restricts the request path. The keys are literal paths, like '/users', matching the path
+	// and nothing else, or literal path prefixes ending with '/**', like '/users/**', matching the prefix and
+	// any path under it.
+	IndexPath IndexKind = iota
+	// IndexHost restricts the request host. The keys are literal hosts, compared with the Host of the request.
+	IndexHost
+	// IndexMethod restricts the request method. The keys are methods.
+	IndexMethod
+)
+
+// IndexablePredicate is a predicate restricting the requests by path, host or method. The RouteIndex uses
+// its keys to narrow the candidate routes before their predicates are tested.
+type IndexablePredicate interface {
+	Predicate
+	// IndexKeys returns the attribute the predicate restricts and its keys. The predicate must not match any
+	// request the keys do not match. No keys means the predicate cannot be indexed.
+	IndexKeys() (IndexKind, []string)
+}
+
+// RouteIndex finds the matching route of a request like Routes.FindMatching, only testing the predicates
+// of the candidate routes.
+//
+// The candidates are narrowed by host, method and path, from the IndexablePredicate of each route among its
+// top-level predicates: the paths are stored in a radix tree of path segments, and the hosts and methods in
+// sets. The candidates are tested in route order, so the first matching route is the one FindMatching
+// would return. The routes without an indexable predicate are candidates for every request.
+type RouteIndex struct {
+	root    *indexNode
+	routes  Routes
+	hosts   []map[string]struct{}
+	methods []map[string]struct{}
+}
+
+// indexNode is a node of the radix tree of path segments. The candidates of a node are the routes, in
+// route order, that may match a path ending at the node or passing through it.
+type indexNode struct {
+	children         map[string]*indexNode
+	prefixRoutes     []int
+	exactRoutes      []int
+	prefixCandidates []int
+	exactCandidates  []int
+}
+
+// NewRouteIndex creates a new RouteIndex of the given routes. The routes must not change afterward.
+func NewRouteIndex(routes Routes) *RouteIndex {
+	index := &RouteIndex{
+		root:    &indexNode{},
+		routes:  routes,
+		hosts:   make([]map[string]struct{}, len(routes)),
+		methods: make([]map[string]struct{}, len(routes)),
+	}
+	for i := range routes {
+		paths := index.indexRoute(i, routes[i].Predicates)
+		if len(paths) == 0 {
+			paths = []string{"/**"}
+		}
+		for _, path := range paths {
+			index.insert(i, path)
+		}
+	}
+	index.root.computeCandidates(nil)
+	return index
+}
+
+// indexRoute stores the host and method sets of the route, returning its path keys.
+func (x *RouteIndex) indexRoute(route int, predicates Predicates) []string {
+	var paths []string
+	for _, predicate := range predicates {
+		indexable, ok := predicate.(IndexablePredicate)
+		if !ok {
+			continue
+		}
+		kind, keys := indexable.IndexKeys()
+		if len(keys) == 0 {
+			continue
+		}
+		switch kind {
+		case IndexPath:
+			if paths == nil {
+				paths = keys
+			}
+		case IndexHost:
+			if x.hosts[route] == nil {
+				x.hosts[route] = toSet(keys)
+			}
+		case IndexMethod:
+			if x.methods[route] == nil {
+				x.methods[route] = toSet(keys)
+			}
+		default:
+		}
+	}
+	return paths
+}
+
+func (x *RouteIndex) insert(route int, path string) {
+	path, isPrefix := strings.CutSuffix(path, "/**")
+	node := x.root
+	if path != "" || !isPrefix {
+		for segment := range strings.SplitSeq(strings.Trim(path, "/"), "/") {
+			child := node.children[segment]
+			if child == nil {
+				if node.children == nil {
+					node.children = make(map[string]*indexNode)
+				}
+				child = &indexNode{}
+				node.children[segment] = child
+			}
+			node = child
+		}
+	}
+	if isPrefix {
+		node.prefixRoutes = append(node.prefixRoutes, route)
+	} else {
+		node.exactRoutes = append(node.exactRoutes, route)
+	}
+}
+
+// computeCandidates computes the candidates of the node and its children, given the prefix candidates of
+// its parent.
+func (n *indexNode) computeCandidates(parent []int) {
+	n.prefixCandidates = mergeRoutes(parent, n.prefixRoutes)
+	n.exactCandidates = mergeRoutes(n.prefixCandidates, n.exactRoutes)
+	for _, child := range n.children {
+		child.computeCandidates(n.prefixCandidates)
+	}
+}
+
+func mergeRoutes(a, b []int) []int {
+	merged := slices.Concat(a, b)
+	slices.Sort(merged)
+	return slices.Compact(merged)
+}
+
+func toSet(keys []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		set[key] = struct{}{}
+	}
+	return set
+}
+
+// FindMatching finds the first matching route for the given request, like Routes.FindMatching.
+func (x *RouteIndex) FindMatching(req *http.Request) *Route {
+	route, _ := x.FindMatchingWithVariables(req)
+	return route
+}
+
+// FindMatchingWithVariables finds the first matching route for the given request, like
+// Routes.FindMatchingWithVariables.
+func (x *RouteIndex) FindMatchingWithVariables(req *http.Request) (*Route, map[string]string) {
+	var state *MatchState
+	for _, i := range x.candidates(req.URL.Path) {
+		if !inSet(x.hosts[i], req.Host) || !inSet(x.methods[i], req.Method) {
+			continue
+		}
+		if x.routes[i].Predicates.TestAllWithState(req, &state) {
+			route := x.routes[i]
+			return new(route), state.PathVariables()
+		}
+		if state != nil {
+			state.variables = nil
+		}
+	}
+	return nil, nil
+}
+
+// candidates walks the radix tree along the segments of the path, returning the candidates of the
+// deepest node reached.
+func (x *RouteIndex) candidates(path string) []int {
+	node := x.root
+	rest := strings.Trim(path, "/")
+	for {
+		segment, next, more := strings.Cut(rest, "/")
+		child := node.children[segment]
+		if child == nil {
+			return node.prefixCandidates
+		}
+		node = child
+		if !more {
+			return node.exactCandidates
+		}
+		rest = next
+	}
+}
+
+func inSet(set map[string]struct{}, key string) bool {
+	if set == nil {
+		return true
+	}
+	_, ok := set[key]
+	return ok
+}
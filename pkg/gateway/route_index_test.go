@@ -0,0 +1,191 @@
+package gateway_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+func newIndexTestRoutes(t *testing.T) gateway.Routes {
+	t.Helper()
+	host := func(patterns ...string) gateway.Predicate {
+		p, err := predicate.NewHostPredicate(patterns...)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return p
+	}
+	template := func(patterns ...string) gateway.Predicate {
+		p, err := predicate.NewPathTemplatePredicate(patterns...)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		return p
+	}
+	return gateway.Routes{
+		{ID: "users-get", Predicates: gateway.Predicates{predicate.NewMethodPredicate(http.MethodGet), predicate.NewPathPredicate("/api/users/**")}},
+		{ID: "users-exact", Predicates: gateway.Predicates{predicate.NewPathPredicate("/api/users")}},
+		{ID: "user-id", Predicates: gateway.Predicates{template("/api/users/{id:[0-9]+}")}},
+		{ID: "orders", Predicates: gateway.Predicates{predicate.NewPathPredicate("/api/orders/*/items", "/api/orders")}},
+		{ID: "admin-host", Predicates: gateway.Predicates{host("admin.example.org"), predicate.NewPathPredicate("/api/**")}},
+		{ID: "wildcard-host", Predicates: gateway.Predicates{host("*.example.org"), predicate.NewPathPredicate("/static/**")}},
+		{ID: "header", Predicates: gateway.Predicates{predicate.NewPathPredicate("/api/**"), mustHeader(t, "X-Beta", "")}},
+		{ID: "root", Predicates: gateway.Predicates{predicate.NewPathPredicate("/")}},
+		{ID: "glob-first", Predicates: gateway.Predicates{predicate.NewPathPredicate("/*/health")}},
+		{ID: "catch-all-post", Predicates: gateway.Predicates{predicate.NewMethodPredicate(http.MethodPost)}},
+		{ID: "catch-all", Predicates: gateway.Predicates{predicate.NewPathPredicate("/**")}},
+	}
+}
+
+func mustHeader(t *testing.T, name, regexp string) gateway.Predicate {
+	t.Helper()
+	p, err := predicate.NewHeaderPredicate(name, regexp)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return p
+}
+
+func TestRouteIndex_FindMatching(t *testing.T) {
+	routes := newIndexTestRoutes(t)
+	index := gateway.NewRouteIndex(routes)
+	tests := []struct {
+		header        http.Header
+		name          string
+		method        string
+		target        string
+		expectedRoute string
+	}{
+		{name: "prefix route by method", method: http.MethodGet, target: "http://x.org/api/users/42", expectedRoute: "users-get"},
+		{name: "prefix route matches its prefix", method: http.MethodGet, target: "http://x.org/api/users", expectedRoute: "users-get"},
+		{name: "exact route", method: http.MethodPut, target: "http://x.org/api/users/", expectedRoute: "users-exact"},
+		{name: "template route", method: http.MethodPut, target: "http://x.org/api/users/42", expectedRoute: "user-id"},
+		{name: "template route not matching falls through", method: http.MethodPut, target: "http://x.org/api/users/me", expectedRoute: "catch-all"},
+		{name: "glob pattern", method: http.MethodPut, target: "http://x.org/api/orders/7/items", expectedRoute: "orders"},
+		{name: "second pattern of a route", method: http.MethodPut, target: "http://x.org/api/orders", expectedRoute: "orders"},
+		{name: "literal host", method: http.MethodPut, target: "http://admin.example.org/api/orders/7", expectedRoute: "admin-host"},
+		{name: "wildcard host", method: http.MethodGet, target: "http://cdn.example.org/static/site.css", expectedRoute: "wildcard-host"},
+		{name: "not indexed predicate", method: http.MethodPut, target: "http://x.org/api/x", header: http.Header{"X-Beta": {"1"}}, expectedRoute: "header"},
+		{name: "root", method: http.MethodGet, target: "http://x.org/", expectedRoute: "root"},
+		{name: "glob first segment", method: http.MethodGet, target: "http://x.org/orders/health", expectedRoute: "glob-first"},
+		{name: "method only route", method: http.MethodPost, target: "http://x.org/other", expectedRoute: "catch-all-post"},
+		{name: "catch all", method: http.MethodGet, target: "http://x.org/other/path", expectedRoute: "catch-all"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.target, nil)
+			if tt.header != nil {
+				req.Header = tt.header
+			}
+
+			actual := index.FindMatching(req)
+			expected := routes.FindMatching(req)
+
+			if actual == nil || actual.ID != tt.expectedRoute || expected.ID != tt.expectedRoute {
+				t.Errorf("expected route %s actual index %v scan %v", tt.expectedRoute, actual, expected)
+			}
+		})
+	}
+}
+
+func TestRouteIndex_FindMatchingWithVariables(t *testing.T) {
+	index := gateway.NewRouteIndex(newIndexTestRoutes(t))
+
+	route, variables := index.FindMatchingWithVariables(httptest.NewRequest(http.MethodPut, "/api/users/42", nil))
+
+	if route == nil || route.ID != "user-id" || !reflect.DeepEqual(variables, map[string]string{"id": "42"}) {
+		t.Errorf("expected route user-id with variable id 42 actual %v %v", route, variables)
+	}
+}
+
+func TestRouteIndex_FindMatchingWithVariables_DropsVariablesOfFailedBranches(t *testing.T) {
+	tenantPath, err := predicate.NewPathTemplatePredicate("/{tenant}/**")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tenantHeader, err := predicate.NewHeaderPredicate("X-Tenant", ".*")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	routes := gateway.Routes{
+		{
+			ID: "tenant-or-public",
+			Predicates: gateway.Predicates{
+				predicate.NewOrPredicate(
+					predicate.NewAndPredicate(tenantPath, tenantHeader),
+					predicate.NewPathPredicate("/public/**")),
+			},
+		},
+	}
+	req := httptest.NewRequest(http.MethodGet, "/public/x", nil)
+
+	indexRoute, indexVariables := gateway.NewRouteIndex(routes).FindMatchingWithVariables(req)
+	scanRoute, scanVariables := routes.FindMatchingWithVariables(req)
+
+	if indexRoute == nil || len(indexVariables) != 0 {
+		t.Errorf("expected route without variables from the index actual %v %v", indexRoute, indexVariables)
+	}
+	if scanRoute == nil || len(scanVariables) != 0 {
+		t.Errorf("expected route without variables from the scan actual %v %v", scanRoute, scanVariables)
+	}
+}
+
+func TestRouteIndex_FindMatching_NoMatch(t *testing.T) {
+	index := gateway.NewRouteIndex(gateway.Routes{
+		{ID: "r1", Predicates: gateway.Predicates{predicate.NewPathPredicate("/a/**")}},
+		{ID: "r2", Predicates: gateway.Predicates{predicate.NewMethodPredicate(http.MethodPost), predicate.NewPathPredicate("/b")}},
+	})
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/b", nil),
+		httptest.NewRequest(http.MethodPost, "/b/c", nil),
+		httptest.NewRequest(http.MethodPost, "/", nil),
+	} {
+		if route := index.FindMatching(req); route != nil {
+			t.Errorf("expected no route for %s %s actual %s", req.Method, req.URL.Path, route.ID)
+		}
+	}
+}
+
+func TestRouteIndex_FindMatching_ReturnsACopy(t *testing.T) {
+	routes := gateway.Routes{{ID: "r1", Predicates: gateway.Predicates{predicate.NewPathPredicate("/**")}}}
+	index := gateway.NewRouteIndex(routes)
+
+	index.FindMatching(httptest.NewRequest(http.MethodGet, "/", nil)).ID = "mutated"
+
+	if routes[0].ID != "r1" {
+		t.Errorf("expected route table untouched actual %s", routes[0].ID)
+	}
+}
+
+func TestRouteIndex_FindMatching_SameAsScan(t *testing.T) {
+	segments := []string{"api", "users", "42", "orders", "items", "static", "health", ""}
+	paths := []string{"/"}
+	for _, first := range segments {
+		for _, second := range segments {
+			for _, third := range segments {
+				paths = append(paths, "/"+first, "/"+first+"/"+second, "/"+first+"/"+second+"/"+third)
+			}
+		}
+	}
+	allRoutes := newIndexTestRoutes(t)
+	// without the catch-all routes, many requests match no route
+	for _, routes := range []gateway.Routes{allRoutes, allRoutes[:len(allRoutes)-2]} {
+		index := gateway.NewRouteIndex(routes)
+		for _, host := range []string{"x.org", "admin.example.org", "cdn.example.org"} {
+			for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodPut} {
+				for _, path := range paths {
+					req := httptest.NewRequest(method, "http://"+host+path, nil)
+					actual, expected := index.FindMatching(req), routes.FindMatching(req)
+					if (actual == nil) != (expected == nil) || (actual != nil && actual.ID != expected.ID) {
+						t.Fatalf("%s %s%s: expected route %v actual %v", method, host, path, expected, actual)
+					}
+				}
+			}
+		}
+	}
+}
@@ -44,6 +44,50 @@ func TestRoute_GetDestinationURL(t *testing.T) {
 	}
 }
 
+func TestRoute_HasBackend(t *testing.T) {
+	tests := []struct {
+		uri      string
+		expected bool
+	}{
+		{uri: "https://example.org", expected: true},
+		{uri: "static:", expected: false},
+		{uri: "no://op", expected: false},
+		{uri: "no://other", expected: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			route, _ := gateway.NewRoute("r1", tt.uri, nil, nil, nil, 0, nil, nil)
+
+			if actual := route.HasBackend(); actual != tt.expected {
+				t.Errorf("expected %v actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRoute_MetadataValue(t *testing.T) {
+	route := &gateway.Route{Metadata: map[string]any{"team": "payments", "sla": map[string]any{"tier": 1}}}
+	tests := []struct {
+		expected   any
+		name       string
+		key        string
+		expectedOk bool
+	}{
+		{name: "metadata value should be found", key: "team", expected: "payments", expectedOk: true},
+		{name: "nested metadata value should be found", key: "sla.tier", expected: 1, expectedOk: true},
+		{name: "missing metadata value should not be found", key: "owner", expected: nil, expectedOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, ok := route.MetadataValue(tt.key)
+
+			if actual != tt.expected || ok != tt.expectedOk {
+				t.Errorf("expected %v %v actual %v %v", tt.expected, tt.expectedOk, actual, ok)
+			}
+		})
+	}
+}
+
 func TestRoutes_FindMatching_ReturnsACopy(t *testing.T) {
 	routes := gateway.Routes{
 		{
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -33,7 +35,18 @@ const statusClientClosedRequest = 499
 // 3. gateway.ErrHTTP: the gateway http request to backend failed. It will return 502 Bad Gateway.
 // 4. filter.ErrRateLimitExceeded: the rate limit exceeded. It will return 429 Too Many Requests.
 // 5. gateway.ErrCircuitBreaker: the circuit breaker is open. It will return 503 Service Unavailable.
-// 6. any other error: unexpected error. It will return a 500 Internal Server Error.
+// 6. filter.ErrConcurrencyLimitExceeded: no concurrency slot available. It will return 503 Service Unavailable.
+// 7. filter.ErrLoadShed: the request was shed under load. It will return 503 Service Unavailable
+// with a Retry-After header.
+// 8. filter.ErrIPForbidden: the client address is not allowed. It will return a 403 Forbidden.
+// 9. gateway.ErrRequestTooLarge: the request body is larger than its limit. It will return a 413 Content Too Large.
+// 10. gateway.ErrResponseTooLarge: the response body is larger than its limit. It will return a 502 Bad Gateway.
+// 11. gateway.ErrDecodedLimitExceeded: the decompressed request body is larger than its limits. It will return
+// a 413 Content Too Large.
+// 12. gateway.ErrContentDecoding: the request body cannot be decompressed. It will return a 400 Bad Request.
+// 13. gateway.ErrUnsupportedContentEncoding: the request body encoding is not supported. It will return a 415
+// Unsupported Media Type.
+// 14. any other error: unexpected error. It will return a 500 Internal Server Error.
 // If the error is nil, it will do nothing.
 func BaseErrorHandler() ErrorHandlerFunc {
 	return func(ctx *gateway.Context, err error, writer http.ResponseWriter) {
@@ -56,9 +69,42 @@ func BaseErrorHandler() ErrorHandlerFunc {
 		case errors.Is(err, gateway.ErrCircuitBreaker):
 			ctx.Logger.Error("circuit breaker is open", "error", err)
 			http.Error(writer, "", http.StatusServiceUnavailable)
+		case errors.Is(err, filter.ErrConcurrencyLimitExceeded):
+			ctx.Logger.Error("concurrency limit exceeded", "error", err)
+			http.Error(writer, "", http.StatusServiceUnavailable)
+		case errors.Is(err, filter.ErrLoadShed):
+			ctx.Logger.Warn("request shed under load", "error", err)
+			writer.Header().Set("Retry-After", retryAfterSeconds(ctx))
+			http.Error(writer, "", http.StatusServiceUnavailable)
+		case errors.Is(err, filter.ErrIPForbidden):
+			ctx.Logger.Warn("ip address forbidden", "error", err)
+			http.Error(writer, "", http.StatusForbidden)
+		case errors.Is(err, gateway.ErrRequestTooLarge):
+			ctx.Logger.Warn("request body too large", "error", err)
+			http.Error(writer, "", http.StatusRequestEntityTooLarge)
+		case errors.Is(err, gateway.ErrResponseTooLarge):
+			ctx.Logger.Error("response body too large", "error", err)
+			http.Error(writer, "", http.StatusBadGateway)
+		case errors.Is(err, gateway.ErrDecodedLimitExceeded):
+			ctx.Logger.Warn("decoded request body too large", "error", err)
+			http.Error(writer, "", http.StatusRequestEntityTooLarge)
+		case errors.Is(err, gateway.ErrContentDecoding):
+			ctx.Logger.Warn("request body decoding failed", "error", err)
+			http.Error(writer, "", http.StatusBadRequest)
+		case errors.Is(err, gateway.ErrUnsupportedContentEncoding):
+			ctx.Logger.Warn("unsupported request content encoding", "error", err)
+			http.Error(writer, "", http.StatusUnsupportedMediaType)
 		default:
 			ctx.Logger.Error("unexpected error", "error", err)
 			http.Error(writer, "", http.StatusInternalServerError)
 		}
 	}
 }
+
+// retryAfterSeconds returns the Retry-After header value suggested by the filters, rounded up
+// to whole seconds. It defaults to one second.
+func retryAfterSeconds(ctx *gateway.Context) string {
+	retryAfter, _ := ctx.Attributes[filter.RetryAfterAttr].(time.Duration)
+	seconds := int64((retryAfter + time.Second - 1) / time.Second)
+	return strconv.FormatInt(max(seconds, 1), 10)
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/drathveloper/go-cloud-gateway/pkg/filter"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -77,6 +78,54 @@ func TestBaseErrorHandler(t *testing.T) {
 			err:                gateway.ErrCircuitBreaker,
 			expectedErrMsg:     "level=ERROR msg=\"circuit breaker is open\" error=\"circuit breaker failed",
 		},
+		{
+			name:               "test base error handler should succeed when error is concurrency limit exceeded",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			err:                filter.ErrConcurrencyLimitExceeded,
+			expectedErrMsg:     "level=ERROR msg=\"concurrency limit exceeded\" error=\"concurrency limit exceeded",
+		},
+		{
+			name:               "test base error handler should succeed when error is load shed",
+			expectedStatusCode: http.StatusServiceUnavailable,
+			err:                filter.ErrLoadShed,
+			expectedErrMsg:     "level=WARN msg=\"request shed under load\" error=\"request shed under load",
+		},
+		{
+			name:               "test base error handler should succeed when error is ip forbidden",
+			expectedStatusCode: http.StatusForbidden,
+			err:                filter.ErrIPForbidden,
+			expectedErrMsg:     "level=WARN msg=\"ip address forbidden\" error=\"ip address forbidden",
+		},
+		{
+			name:               "test base error handler should succeed when error is request too large",
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			err:                gateway.ErrRequestTooLarge,
+			expectedErrMsg:     "level=WARN msg=\"request body too large\" error=\"request body too large",
+		},
+		{
+			name:               "test base error handler should succeed when error is response too large",
+			expectedStatusCode: http.StatusBadGateway,
+			err:                gateway.ErrResponseTooLarge,
+			expectedErrMsg:     "level=ERROR msg=\"response body too large\" error=\"response body too large",
+		},
+		{
+			name:               "test base error handler should succeed when error is decoded limit exceeded",
+			expectedStatusCode: http.StatusRequestEntityTooLarge,
+			err:                gateway.ErrDecodedLimitExceeded,
+			expectedErrMsg:     "level=WARN msg=\"decoded request body too large\" error=\"decoded body limit exceeded",
+		},
+		{
+			name:               "test base error handler should succeed when error is content decoding",
+			expectedStatusCode: http.StatusBadRequest,
+			err:                gateway.ErrContentDecoding,
+			expectedErrMsg:     "level=WARN msg=\"request body decoding failed\" error=\"content decoding failed",
+		},
+		{
+			name:               "test base error handler should succeed when error is unsupported content encoding",
+			expectedStatusCode: http.StatusUnsupportedMediaType,
+			err:                gateway.ErrUnsupportedContentEncoding,
+			expectedErrMsg:     "level=WARN msg=\"unsupported request content encoding\" error=\"unsupported content encoding",
+		},
 		{
 			name:               "test base error handler should succeed when error is unhandled error",
 			expectedStatusCode: http.StatusInternalServerError,
@@ -105,3 +154,40 @@ func TestBaseErrorHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestBaseErrorHandler_LoadShedSetsRetryAfter(t *testing.T) {
+	tests := []struct {
+		attributes map[string]any
+		name       string
+		expected   string
+	}{
+		{
+			name:       "retry after should default to one second when not set",
+			attributes: map[string]any{},
+			expected:   "1",
+		},
+		{
+			name:       "retry after should round up to whole seconds",
+			attributes: map[string]any{filter.RetryAfterAttr: 2500 * time.Millisecond},
+			expected:   "3",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &gateway.Context{
+				Logger:     slog.New(slog.DiscardHandler),
+				Attributes: tt.attributes,
+			}
+			writer := &DummyWriter{
+				CurrHeader:         http.Header{},
+				ExpectedStatusCode: http.StatusServiceUnavailable,
+			}
+
+			gatewayhandler.BaseErrorHandler().Handle(ctx, filter.ErrLoadShed, writer)
+
+			if writer.CurrHeader.Get("Retry-After") != tt.expected {
+				t.Errorf("expected retry after %s actual %s", tt.expected, writer.CurrHeader.Get("Retry-After"))
+			}
+		})
+	}
+}
@@ -9,8 +9,10 @@ import (
 	"runtime/debug"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 )
 
@@ -33,7 +35,8 @@ type GatewayHandler struct {
 	gateway    Gateway
 	errHandler ErrorHandler
 	notFound   http.Handler
-	routes     gateway.Routes
+	admission  *concurrency.AdmissionController
+	routes     *gateway.RouteIndex
 }
 
 // Option configures a GatewayHandler.
@@ -52,15 +55,26 @@ func WithNotFoundHandler(handler http.Handler) Option {
 	}
 }
 
-// NewGatewayHandler creates a new gateway handler.
+// WithAdmissionController sets the admission controller every routed request is counted in
+// as in-flight, from the moment its route matches until its response has been written. The
+// requests no route matches are not counted.
+// The priority filters use this count to shed load gateway-wide.
+func WithAdmissionController(controller *concurrency.AdmissionController) Option {
+	return func(h *GatewayHandler) {
+		h.admission = controller
+	}
+}
+
+// NewGatewayHandler creates a new gateway handler. The routes are indexed by host, method and path with a
+// gateway.RouteIndex, so they must not change afterward.
 func NewGatewayHandler(
-	gateway Gateway,
+	gw Gateway,
 	routes gateway.Routes,
 	errHandler ErrorHandler,
 	opts ...Option) *GatewayHandler {
 	handler := &GatewayHandler{
-		gateway:    gateway,
-		routes:     routes,
+		gateway:    gw,
+		routes:     gateway.NewRouteIndex(routes),
 		errHandler: errHandler,
 		notFound: http.HandlerFunc(func(writer http.ResponseWriter, _ *http.Request) {
 			http.Error(writer, ErrRouteNotFound.Error(), http.StatusNotFound)
@@ -74,16 +88,25 @@ func NewGatewayHandler(
 
 // ServeHTTP is the entrypoint for all requests to the gateway.
 func (h *GatewayHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
-	route := h.routes.FindMatching(request)
+	start := time.Now()
+	route, variables := h.routes.FindMatchingWithVariables(request)
 	if route == nil {
 		h.notFound.ServeHTTP(writer, request)
 		return
 	}
+	if h.admission != nil {
+		h.admission.Enter()
+		defer h.admission.Done()
+	}
 	shared.SetXForwardedHeaders(request)
 	gwRequest := gateway.NewGatewayRequest(request)
 	ctx, cancel := gateway.NewGatewayContext(request.Context(), route, gwRequest)
 	defer gateway.ReleaseGatewayContext(ctx)
 	defer cancel()
+	ctx.Attributes[gateway.RequestStartAttr] = start
+	if variables != nil {
+		ctx.Attributes[gateway.PathVariablesAttr] = variables
+	}
 	defer gwRequest.BodyReader.Close() //nolint:errcheck
 	if err := h.doWithRecover(ctx); err != nil {
 		h.errHandler.Handle(ctx, err, writer)
@@ -145,10 +168,11 @@ func (h *GatewayHandler) writeResponse(writer http.ResponseWriter, ctx *gateway.
 }
 
 // isStreamingResponse reports whether the response must reach the client as it is
-// produced: responses of unknown length and server-sent event streams. It mirrors
-// the flush heuristic of the net/http/httputil reverse proxy.
+// produced: streams of unknown length and server-sent event streams. It mirrors the
+// flush heuristic of the net/http/httputil reverse proxy. The bodies a filter rewrote
+// from a body of known length, like a compressed one, are not streams.
 func isStreamingResponse(response *gateway.Response) bool {
-	if response.BodyReader.Len() == -1 {
+	if response.BodyReader.IsStream() {
 		return true
 	}
 	contentType, _, _ := strings.Cut(response.Headers.Get("Content-Type"), ";")
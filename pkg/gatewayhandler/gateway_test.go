@@ -16,6 +16,8 @@ import (
 	"testing/iotest"
 	"time"
 
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/concurrency"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gatewayhandler"
 	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
@@ -592,6 +594,42 @@ func TestGatewayHandler_ServeHTTP_SetsXForwardedHeaders(t *testing.T) {
 	}
 }
 
+func TestGatewayHandler_ServeHTTP_StoresPathVariables(t *testing.T) {
+	var seen map[string]string
+	gw := &mockGateway{
+		doFunc: func(ctx *gateway.Context) error {
+			seen = gateway.PathVariables(ctx)
+			ctx.Response = &gateway.Response{
+				Status:     http.StatusOK,
+				Headers:    http.Header{},
+				BodyReader: gateway.NewReplayableBody(nil, 0),
+			}
+			return nil
+		},
+	}
+	errHandler := &mockErrorHandler{
+		handleFunc: func(_ *gateway.Context, _ error, _ http.ResponseWriter) {},
+	}
+	usersPath, err := predicate.NewPathTemplatePredicate("/users/{id:[0-9]+}")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	routes := gateway.Routes{
+		{
+			ID:         "r1",
+			Timeout:    time.Minute,
+			Predicates: gateway.Predicates{usersPath},
+		},
+	}
+	gwHandler := gatewayhandler.NewGatewayHandler(gw, routes, errHandler)
+
+	gwHandler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, http.MethodGet, "http://localhost:8080/users/42", nil))
+
+	if seen["id"] != "42" {
+		t.Errorf("expected path variable id 42, actual %v", seen)
+	}
+}
+
 func TestGatewayHandler_ServeHTTP_DefaultNotFound(t *testing.T) {
 	gw := &mockGateway{
 		doFunc: func(_ *gateway.Context) error {
@@ -666,6 +704,114 @@ func TestGatewayHandler_ServeHTTP_CustomNotFoundHandler(t *testing.T) {
 	}
 }
 
+func TestGatewayHandler_ServeHTTP_CountsEveryRequestInFlight(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{})
+	var inFlightWhileWriting int
+	gw := &mockGateway{
+		doFunc: func(ctx *gateway.Context) error {
+			body := iotest.ErrReader(io.EOF)
+			ctx.Response = &gateway.Response{
+				Status:  http.StatusOK,
+				Headers: http.Header{},
+				BodyReader: gateway.NewReplayableBody(io.NopCloser(readerFunc(func(p []byte) (int, error) {
+					inFlightWhileWriting = controller.InFlight()
+					return body.Read(p)
+				})), -1),
+			}
+			return nil
+		},
+	}
+	errHandler := &mockErrorHandler{
+		handleFunc: func(_ *gateway.Context, _ error, _ http.ResponseWriter) {},
+	}
+	routes := gateway.Routes{
+		{
+			ID:      "r1",
+			Timeout: time.Minute,
+			Logger:  slog.Default(),
+		},
+	}
+	gwHandler := gatewayhandler.NewGatewayHandler(
+		gw,
+		routes,
+		errHandler,
+		gatewayhandler.WithAdmissionController(controller))
+
+	gwHandler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, http.MethodGet, "http://localhost:8080/", nil))
+
+	if inFlightWhileWriting != 1 {
+		t.Errorf("expected the request counted while its response is written, actual %d in flight",
+			inFlightWhileWriting)
+	}
+	if controller.InFlight() != 0 {
+		t.Errorf("expected the request released once handled, actual %d in flight", controller.InFlight())
+	}
+}
+
+func TestGatewayHandler_ServeHTTP_DoesNotCountUnroutedRequestsInFlight(t *testing.T) {
+	controller := concurrency.NewAdmissionController(&shared.RealTime{}, concurrency.AdmissionSettings{})
+	var inFlightWhileNotFound int
+	notFound := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		inFlightWhileNotFound = controller.InFlight()
+		w.WriteHeader(http.StatusNotFound)
+	})
+	routes := gateway.Routes{
+		{
+			ID: "r1",
+			Predicates: gateway.Predicates{
+				predicate.NewMethodPredicate(http.MethodPost),
+			},
+		},
+	}
+	gwHandler := gatewayhandler.NewGatewayHandler(
+		&mockGateway{doFunc: func(_ *gateway.Context) error { return nil }},
+		routes,
+		&mockErrorHandler{handleFunc: func(_ *gateway.Context, _ error, _ http.ResponseWriter) {}},
+		gatewayhandler.WithNotFoundHandler(notFound),
+		gatewayhandler.WithAdmissionController(controller))
+
+	gwHandler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, http.MethodGet, "http://localhost:8080/missing", nil))
+
+	if inFlightWhileNotFound != 0 {
+		t.Errorf("expected unrouted requests not counted in flight, actual %d", inFlightWhileNotFound)
+	}
+}
+
+func TestGatewayHandler_ServeHTTP_RecordsRequestStart(t *testing.T) {
+	before := time.Now()
+	var start any
+	gw := &mockGateway{
+		doFunc: func(ctx *gateway.Context) error {
+			start = ctx.Attributes[gateway.RequestStartAttr]
+			ctx.Response = gateway.NewStaticGatewayResponse(http.StatusOK, nil, "")
+			return nil
+		},
+	}
+	routes := gateway.Routes{
+		{
+			ID:      "r1",
+			Timeout: time.Minute,
+			Logger:  slog.Default(),
+		},
+	}
+	gwHandler := gatewayhandler.NewGatewayHandler(
+		gw,
+		routes,
+		&mockErrorHandler{handleFunc: func(_ *gateway.Context, _ error, _ http.ResponseWriter) {}})
+
+	gwHandler.ServeHTTP(httptest.NewRecorder(), newTestRequest(t, http.MethodGet, "http://localhost:8080/", nil))
+
+	if startTime, ok := start.(time.Time); !ok || startTime.Before(before) {
+		t.Errorf("expected the request start recorded at gateway entry, actual %v", start)
+	}
+}
+
+type readerFunc func(p []byte) (int, error)
+
+func (f readerFunc) Read(p []byte) (int, error) {
+	return f(p)
+}
+
 func TestGatewayHandler_ServeHTTP_RecoversFilterPanics(t *testing.T) {
 	body := &closeCountingBody{Reader: bytes.NewReader([]byte("partial"))}
 	gw := &mockGateway{
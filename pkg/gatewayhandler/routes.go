@@ -0,0 +1,58 @@
+package gatewayhandler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// RouteInfo describes a route of the gateway, as served by the RoutesHandler.
+type RouteInfo struct {
+	Metadata   map[string]any `json:"metadata,omitempty"`
+	ID         string         `json:"id"`
+	URI        string         `json:"uri"`
+	Predicates []string       `json:"predicates"`
+	Filters    []string       `json:"filters"`
+	Order      int            `json:"order"`
+}
+
+// RoutesHandler is a debug http handler serving the routes of the gateway as JSON, in the order they are
+// matched. Passwords in the route URIs are redacted.
+type RoutesHandler struct {
+	routes []RouteInfo
+}
+
+// NewRoutesHandler creates a new RoutesHandler of the given routes.
+func NewRoutesHandler(routes gateway.Routes) *RoutesHandler {
+	infos := make([]RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		info := RouteInfo{
+			ID:         route.ID,
+			URI:        route.URI.Redacted(),
+			Order:      route.Order,
+			Metadata:   route.Metadata,
+			Predicates: make([]string, 0, len(route.Predicates)),
+			Filters:    make([]string, 0, len(route.Filters)),
+		}
+		for _, predicate := range route.Predicates {
+			info.Predicates = append(info.Predicates, predicate.Name())
+		}
+		for _, filter := range route.Filters {
+			info.Filters = append(info.Filters, filter.Name())
+		}
+		infos = append(infos, info)
+	}
+	return &RoutesHandler{routes: infos}
+}
+
+// Routes returns the routes, in the order they are matched.
+func (h *RoutesHandler) Routes() []RouteInfo {
+	return h.routes
+}
+
+// ServeHTTP writes the routes as JSON.
+func (h *RoutesHandler) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(h.routes)
+}
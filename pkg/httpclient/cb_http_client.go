@@ -5,14 +5,41 @@ import (
 	"fmt"
 	"net/http"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/circuitbreaker"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 )
 
-// ErrInternalServer marks backend 5xx responses as failures for the circuit breaker
-// accounting. It is not returned to callers: the 5xx response itself is.
+// ErrInternalServer marks backend responses classified as failures (5xx by default) for the
+// circuit breaker accounting. It is not returned to callers: the response itself is.
 var ErrInternalServer = errors.New("internal server error")
 
-const internalServerErrorStatusCode = 500
+//nolint:gochecknoglobals
+var defaultFailurePolicy = &FailurePolicy{}
+
+// PolicyCircuitBreaker is a circuit breaker whose calls are classified by a FailurePolicy. The
+// CircuitBreakerHTTPClient classifies the backend responses of the routes using it with the same
+// policy, so the policy of a route is built once.
+type PolicyCircuitBreaker struct {
+	*circuitbreaker.CircuitBreaker[*http.Response]
+
+	policy *FailurePolicy
+}
+
+// NewPolicyCircuitBreaker creates a new circuit breaker with the given settings, whose IsSuccessful
+// and IsIgnored settings are the ones of the policy.
+func NewPolicyCircuitBreaker(settings circuitbreaker.Settings, policy *FailurePolicy) *PolicyCircuitBreaker {
+	settings.IsSuccessful = policy.IsSuccessful
+	settings.IsIgnored = policy.IsIgnored
+	return &PolicyCircuitBreaker{
+		CircuitBreaker: circuitbreaker.NewCircuitBreaker[*http.Response](settings),
+		policy:         policy,
+	}
+}
+
+// FailurePolicy returns the failure policy of the circuit breaker.
+func (b *PolicyCircuitBreaker) FailurePolicy() *FailurePolicy {
+	return b.policy
+}
 
 // CircuitBreakerHTTPClient is a circuit breaker http client.
 type CircuitBreakerHTTPClient struct {
@@ -21,7 +48,8 @@ type CircuitBreakerHTTPClient struct {
 
 // NewCircuitBreakerHTTPClient creates a new circuit breaker http client.
 //
-// The circuit breaker will be applied to the matching route.
+// The circuit breaker will be applied to the matching route. The backend responses are classified
+// with the failure policy of a PolicyCircuitBreaker, and otherwise 5xx responses are failures.
 func NewCircuitBreakerHTTPClient(client gateway.HTTPClient) *CircuitBreakerHTTPClient {
 	return &CircuitBreakerHTTPClient{
 		client: client,
@@ -34,17 +62,23 @@ func (c *CircuitBreakerHTTPClient) Do(req *http.Request) (*http.Response, error)
 	if route == nil || route.CircuitBreaker == nil {
 		return c.client.Do(req) //nolint:wrapcheck
 	}
-	return c.doWithCircuitBreaker(route.CircuitBreaker, req)
+	policy := defaultFailurePolicy
+	if policyBreaker, ok := route.CircuitBreaker.(*PolicyCircuitBreaker); ok {
+		policy = policyBreaker.FailurePolicy()
+	}
+	return c.doWithCircuitBreaker(route.CircuitBreaker, policy, req)
 }
 
 func (c *CircuitBreakerHTTPClient) doWithCircuitBreaker(
-	circuitBreaker gateway.CircuitBreaker[*http.Response], req *http.Request) (*http.Response, error) {
+	circuitBreaker gateway.CircuitBreaker[*http.Response],
+	policy *FailurePolicy,
+	req *http.Request) (*http.Response, error) {
 	result, err := circuitBreaker.Execute(func() (*http.Response, error) {
 		resp, err := c.client.Do(req)
 		if err != nil {
 			return nil, fmt.Errorf("wrapped circuit breaker request failed: %w", err)
 		}
-		if resp.StatusCode >= internalServerErrorStatusCode {
+		if policy.IsFailureResponse(resp) {
 			// The error makes the breaker count a failure, but the response still
 			// travels back so the client receives the real backend status and body
 			// instead of a generic gateway error.
@@ -53,7 +87,7 @@ func (c *CircuitBreakerHTTPClient) doWithCircuitBreaker(
 		return resp, nil
 	})
 	if result != nil && err != nil {
-		// A response alongside an error is the 5xx accounting case: the breaker
+		// A response alongside an error is the failure response case: the breaker
 		// already counted the failure, the caller gets the response.
 		return result, nil //nolint:nilerr // the error only feeds the breaker accounting
 	}
@@ -249,3 +249,61 @@ func TestCircuitBreakerHTTPClient_Do_AppliesThroughWrappedContext(t *testing.T)
 		t.Error("expected the circuit breaker to be applied through the wrapped context")
 	}
 }
+
+func TestCircuitBreakerHTTPClient_Do_UsesRouteFailurePolicy(t *testing.T) {
+	client := &MockHTTPClient{
+		ExpectedResponse: &http.Response{StatusCode: http.StatusServiceUnavailable},
+	}
+	breaker := httpclient.NewPolicyCircuitBreaker(
+		circuitbreaker.Settings{
+			ReadyToTrip: func(counts circuitbreaker.Counts) bool { return counts.ConsecutiveFailures > 0 },
+		},
+		&httpclient.FailurePolicy{FailureStatuses: []httpclient.StatusRange{{From: 429, To: 429}}})
+	ctx := &gateway.Context{
+		Route: &gateway.Route{
+			ID:             "someId",
+			CircuitBreaker: breaker,
+		},
+		Context: t.Context(),
+	}
+	req := (&http.Request{}).WithContext(ctx)
+	cbClient := httpclient.NewCircuitBreakerHTTPClient(client)
+
+	res, err := cbClient.Do(req) //nolint:bodyclose
+
+	if err != nil || res.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the backend response, actual %v err %v", res, err)
+	}
+	if breaker.State() != circuitbreaker.StateClosed {
+		t.Errorf("expected the 503 not to be counted as a failure, actual state %s", breaker.State())
+	}
+}
+
+func TestCircuitBreakerHTTPClient_Do_UsesPolicyCircuitBreakerFailurePolicy(t *testing.T) {
+	client := &MockHTTPClient{
+		ExpectedResponse: &http.Response{StatusCode: http.StatusTooManyRequests},
+	}
+	breaker := httpclient.NewPolicyCircuitBreaker(
+		circuitbreaker.Settings{
+			ReadyToTrip: func(counts circuitbreaker.Counts) bool { return counts.ConsecutiveFailures > 0 },
+		},
+		&httpclient.FailurePolicy{FailureStatuses: []httpclient.StatusRange{{From: 429, To: 429}}})
+	ctx := &gateway.Context{
+		Route: &gateway.Route{
+			ID:             "someId",
+			CircuitBreaker: breaker,
+		},
+		Context: t.Context(),
+	}
+	req := (&http.Request{}).WithContext(ctx)
+	cbClient := httpclient.NewCircuitBreakerHTTPClient(client)
+
+	res, err := cbClient.Do(req) //nolint:bodyclose
+
+	if err != nil || res.StatusCode != http.StatusTooManyRequests {
+		t.Fatalf("expected the backend response, actual %v err %v", res, err)
+	}
+	if breaker.State() != circuitbreaker.StateOpen {
+		t.Errorf("expected the 429 to be counted as a failure, actual state %s", breaker.State())
+	}
+}
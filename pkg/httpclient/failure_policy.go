@@ -0,0 +1,214 @@
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidStatusRange is returned when a failure status range is not valid.
+var ErrInvalidStatusRange = errors.New("invalid status range")
+
+// ErrInvalidErrorKind is returned when an error kind name is not known.
+var ErrInvalidErrorKind = errors.New("invalid error kind")
+
+const (
+	minStatusCode   = 100
+	maxStatusCode   = 599
+	statusClassSize = 100
+)
+
+// ErrorKind is a class of backend request errors a FailurePolicy can record or ignore.
+type ErrorKind string
+
+// These constants are the error kinds:
+//
+// ErrorKindTimeout is a request that timed out, either on the route deadline or on a network timeout.
+// ErrorKindConnectionRefused is a failure dialing the backend, such as a refused connection or an
+// unreachable host.
+// ErrorKindTLS is a failed TLS handshake or certificate verification.
+const (
+	ErrorKindTimeout           ErrorKind = "timeout"
+	ErrorKindConnectionRefused ErrorKind = "connection-refused"
+	ErrorKindTLS               ErrorKind = "tls"
+)
+
+// ParseErrorKind returns the error kind with the given name, case-insensitively.
+func ParseErrorKind(name string) (ErrorKind, error) {
+	switch kind := ErrorKind(strings.ToLower(name)); kind {
+	case ErrorKindTimeout, ErrorKindConnectionRefused, ErrorKindTLS:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrInvalidErrorKind, name)
+	}
+}
+
+// Matches reports whether the error is of this kind.
+func (k ErrorKind) Matches(err error) bool {
+	switch k {
+	case ErrorKindTimeout:
+		var netErr net.Error
+		return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+	case ErrorKindConnectionRefused:
+		var opErr *net.OpError
+		return errors.As(err, &opErr) && opErr.Op == "dial" && !opErr.Timeout()
+	case ErrorKindTLS:
+		return isTLSError(err)
+	default:
+		return false
+	}
+}
+
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verificationErr *tls.CertificateVerificationError
+	var authorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verificationErr) ||
+		errors.As(err, &authorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
+
+// StatusRange is an inclusive range of HTTP status codes.
+type StatusRange struct {
+	From int
+	To   int
+}
+
+// ParseStatusRange parses a status range. The accepted formats are a single status code (503),
+// an inclusive range (500-599) or a status class (5xx).
+func ParseStatusRange(value string) (StatusRange, error) {
+	value = strings.TrimSpace(value)
+	if len(value) == 3 && strings.HasSuffix(strings.ToLower(value), "xx") {
+		class, err := strconv.Atoi(value[:1])
+		if err != nil {
+			return StatusRange{}, fmt.Errorf("%w: %s", ErrInvalidStatusRange, value)
+		}
+		return newStatusRange(value, class*statusClassSize, class*statusClassSize+statusClassSize-1)
+	}
+	from, to, isRange := strings.Cut(value, "-")
+	fromStatus, err := strconv.Atoi(strings.TrimSpace(from))
+	if err != nil {
+		return StatusRange{}, fmt.Errorf("%w: %s", ErrInvalidStatusRange, value)
+	}
+	if !isRange {
+		return newStatusRange(value, fromStatus, fromStatus)
+	}
+	toStatus, err := strconv.Atoi(strings.TrimSpace(to))
+	if err != nil {
+		return StatusRange{}, fmt.Errorf("%w: %s", ErrInvalidStatusRange, value)
+	}
+	return newStatusRange(value, fromStatus, toStatus)
+}
+
+func newStatusRange(value string, from, to int) (StatusRange, error) {
+	if from < minStatusCode || to > maxStatusCode || from > to {
+		return StatusRange{}, fmt.Errorf("%w: %s", ErrInvalidStatusRange, value)
+	}
+	return StatusRange{From: from, To: to}, nil
+}
+
+// Contains reports whether the status code is in the range.
+func (r StatusRange) Contains(status int) bool {
+	return status >= r.From && status <= r.To
+}
+
+// FailurePolicy classifies the backend responses and errors a circuit breaker records as failures.
+//
+// FailureStatuses are the response status codes counted as failures. If empty, 5xx responses are.
+//
+// FailureHeader and FailureHeaderPattern, when set, count as failures the responses with the
+// header matching the pattern, whatever their status. An empty pattern matches any header value.
+//
+// RecordErrors, when not empty, restricts the errors counted as failures to the given kinds.
+// IgnoreErrors are not recorded at all, neither as failures nor as successes. Client cancellations
+// are not recorded either: they say nothing about the backend health.
+type FailurePolicy struct {
+	FailureHeaderPattern *regexp.Regexp
+	FailureHeader        string
+	FailureStatuses      []StatusRange
+	RecordErrors         []ErrorKind
+	IgnoreErrors         []ErrorKind
+}
+
+//nolint:gochecknoglobals
+var defaultFailureStatuses = []StatusRange{{From: 500, To: 599}}
+
+// IsFailureResponse reports whether the backend response is counted as a failure.
+func (p *FailurePolicy) IsFailureResponse(resp *http.Response) bool {
+	statuses := p.FailureStatuses
+	if len(statuses) == 0 {
+		statuses = defaultFailureStatuses
+	}
+	for _, statusRange := range statuses {
+		if statusRange.Contains(resp.StatusCode) {
+			return true
+		}
+	}
+	if p.FailureHeader == "" {
+		return false
+	}
+	values, isPresent := resp.Header[http.CanonicalHeaderKey(p.FailureHeader)]
+	if !isPresent {
+		return false
+	}
+	if p.FailureHeaderPattern == nil {
+		return true
+	}
+	for _, value := range values {
+		if p.FailureHeaderPattern.MatchString(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsIgnored reports whether the error returned through the circuit breaker is not recorded. It is
+// meant to be used as the circuit breaker IsIgnored setting.
+func (p *FailurePolicy) IsIgnored(err error) bool {
+	if err == nil || errors.Is(err, ErrInternalServer) {
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return true
+	}
+	for _, kind := range p.IgnoreErrors {
+		if kind.Matches(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsSuccessful reports whether the error returned through the circuit breaker is counted as
+// a success. It is meant to be used as the circuit breaker IsSuccessful setting, along with
+// IsIgnored, which classifies the errors that are not recorded.
+func (p *FailurePolicy) IsSuccessful(err error) bool {
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, ErrInternalServer) {
+		return false
+	}
+	if len(p.RecordErrors) == 0 {
+		return false
+	}
+	for _, kind := range p.RecordErrors {
+		if kind.Matches(err) {
+			return false
+		}
+	}
+	return true
+}
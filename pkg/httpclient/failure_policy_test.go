@@ -0,0 +1,288 @@
+package httpclient_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/httpclient"
+)
+
+func TestParseStatusRange(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		name        string
+		input       string
+		expected    httpclient.StatusRange
+	}{
+		{
+			name:     "parse should succeed when input is a single status",
+			input:    "503",
+			expected: httpclient.StatusRange{From: 503, To: 503},
+		},
+		{
+			name:     "parse should succeed when input is a range",
+			input:    "500-504",
+			expected: httpclient.StatusRange{From: 500, To: 504},
+		},
+		{
+			name:     "parse should succeed when input is a status class",
+			input:    "4xx",
+			expected: httpclient.StatusRange{From: 400, To: 499},
+		},
+		{
+			name:        "parse should return error when range is reversed",
+			input:       "504-500",
+			expectedErr: errors.New("invalid status range: 504-500"),
+		},
+		{
+			name:        "parse should return error when status is out of bounds",
+			input:       "600",
+			expectedErr: errors.New("invalid status range: 600"),
+		},
+		{
+			name:        "parse should return error when input is not a status",
+			input:       "server-error",
+			expectedErr: errors.New("invalid status range: server-error"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := httpclient.ParseStatusRange(tt.input)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if result != tt.expected {
+				t.Errorf("expected %v actual %v", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseErrorKind(t *testing.T) {
+	kind, err := httpclient.ParseErrorKind("TLS")
+	if err != nil || kind != httpclient.ErrorKindTLS {
+		t.Errorf("expected kind %s actual %s err %v", httpclient.ErrorKindTLS, kind, err)
+	}
+	_, err = httpclient.ParseErrorKind("dns")
+	if !errors.Is(err, httpclient.ErrInvalidErrorKind) {
+		t.Errorf("expected err %v actual %v", httpclient.ErrInvalidErrorKind, err)
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestErrorKind_Matches(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+	dialTimeout := &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}
+	tests := []struct {
+		err      error
+		name     string
+		kind     httpclient.ErrorKind
+		expected bool
+	}{
+		{
+			name:     "timeout should match route deadline",
+			kind:     httpclient.ErrorKindTimeout,
+			err:      fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			expected: true,
+		},
+		{
+			name:     "timeout should match network timeout",
+			kind:     httpclient.ErrorKindTimeout,
+			err:      dialTimeout,
+			expected: true,
+		},
+		{
+			name:     "connection refused should match dial error",
+			kind:     httpclient.ErrorKindConnectionRefused,
+			err:      fmt.Errorf("request failed: %w", refused),
+			expected: true,
+		},
+		{
+			name:     "connection refused should not match dial timeout",
+			kind:     httpclient.ErrorKindConnectionRefused,
+			err:      dialTimeout,
+			expected: false,
+		},
+		{
+			name:     "tls should match certificate errors",
+			kind:     httpclient.ErrorKindTLS,
+			err:      fmt.Errorf("request failed: %w", x509.UnknownAuthorityError{}),
+			expected: true,
+		},
+		{
+			name:     "tls should not match other errors",
+			kind:     httpclient.ErrorKindTLS,
+			err:      refused,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.kind.Matches(tt.err); result != tt.expected {
+				t.Errorf("expected %t actual %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFailurePolicy_IsFailureResponse(t *testing.T) {
+	tests := []struct {
+		policy   *httpclient.FailurePolicy
+		response *http.Response
+		name     string
+		expected bool
+	}{
+		{
+			name:     "default policy should count 5xx as failure",
+			policy:   &httpclient.FailurePolicy{},
+			response: &http.Response{StatusCode: http.StatusBadGateway},
+			expected: true,
+		},
+		{
+			name:     "default policy should not count 4xx as failure",
+			policy:   &httpclient.FailurePolicy{},
+			response: &http.Response{StatusCode: http.StatusTooManyRequests},
+			expected: false,
+		},
+		{
+			name: "configured statuses should replace the default ones",
+			policy: &httpclient.FailurePolicy{
+				FailureStatuses: []httpclient.StatusRange{{From: 429, To: 429}, {From: 500, To: 502}},
+			},
+			response: &http.Response{StatusCode: http.StatusServiceUnavailable},
+			expected: false,
+		},
+		{
+			name: "failure header should count matching responses whatever their status",
+			policy: &httpclient.FailurePolicy{
+				FailureHeader:        "X-Backend-Health",
+				FailureHeaderPattern: regexp.MustCompile("^degraded$"),
+			},
+			response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Backend-Health": {"degraded"}}},
+			expected: true,
+		},
+		{
+			name: "failure header should not count responses not matching the pattern",
+			policy: &httpclient.FailurePolicy{
+				FailureHeader:        "X-Backend-Health",
+				FailureHeaderPattern: regexp.MustCompile("^degraded$"),
+			},
+			response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Backend-Health": {"ok"}}},
+			expected: false,
+		},
+		{
+			name:     "failure header without pattern should count any value",
+			policy:   &httpclient.FailurePolicy{FailureHeader: "X-Maintenance"},
+			response: &http.Response{StatusCode: http.StatusOK, Header: http.Header{"X-Maintenance": {""}}},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.policy.IsFailureResponse(tt.response); result != tt.expected {
+				t.Errorf("expected %t actual %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFailurePolicy_IsSuccessful(t *testing.T) {
+	refused := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connect: connection refused")}
+	tests := []struct {
+		err      error
+		policy   *httpclient.FailurePolicy
+		name     string
+		expected bool
+	}{
+		{
+			name:     "failure responses should always be failures",
+			policy:   &httpclient.FailurePolicy{IgnoreErrors: []httpclient.ErrorKind{httpclient.ErrorKindTimeout}},
+			err:      httpclient.ErrInternalServer,
+			expected: false,
+		},
+		{
+			name:     "recorded errors should be failures",
+			policy:   &httpclient.FailurePolicy{RecordErrors: []httpclient.ErrorKind{httpclient.ErrorKindConnectionRefused}},
+			err:      refused,
+			expected: false,
+		},
+		{
+			name:     "errors not recorded should be successes when record errors are set",
+			policy:   &httpclient.FailurePolicy{RecordErrors: []httpclient.ErrorKind{httpclient.ErrorKindConnectionRefused}},
+			err:      context.DeadlineExceeded,
+			expected: true,
+		},
+		{
+			name:     "errors should be failures when record errors are not set",
+			policy:   &httpclient.FailurePolicy{IgnoreErrors: []httpclient.ErrorKind{httpclient.ErrorKindTLS}},
+			err:      context.DeadlineExceeded,
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.policy.IsSuccessful(tt.err); result != tt.expected {
+				t.Errorf("expected %t actual %t", tt.expected, result)
+			}
+		})
+	}
+}
+
+func TestFailurePolicy_IsIgnored(t *testing.T) {
+	tests := []struct {
+		err      error
+		policy   *httpclient.FailurePolicy
+		name     string
+		expected bool
+	}{
+		{
+			name:     "successes should not be ignored",
+			policy:   &httpclient.FailurePolicy{IgnoreErrors: []httpclient.ErrorKind{httpclient.ErrorKindTimeout}},
+			err:      nil,
+			expected: false,
+		},
+		{
+			name:     "failure responses should not be ignored",
+			policy:   &httpclient.FailurePolicy{IgnoreErrors: []httpclient.ErrorKind{httpclient.ErrorKindTimeout}},
+			err:      httpclient.ErrInternalServer,
+			expected: false,
+		},
+		{
+			name:     "ignored errors should be ignored",
+			policy:   &httpclient.FailurePolicy{IgnoreErrors: []httpclient.ErrorKind{httpclient.ErrorKindTimeout}},
+			err:      context.DeadlineExceeded,
+			expected: true,
+		},
+		{
+			name:     "errors not ignored should not be ignored",
+			policy:   &httpclient.FailurePolicy{IgnoreErrors: []httpclient.ErrorKind{httpclient.ErrorKindTLS}},
+			err:      context.DeadlineExceeded,
+			expected: false,
+		},
+		{
+			name:     "client cancellations should be ignored",
+			policy:   &httpclient.FailurePolicy{RecordErrors: []httpclient.ErrorKind{httpclient.ErrorKindTimeout}},
+			err:      context.Canceled,
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.policy.IsIgnored(tt.err); result != tt.expected {
+				t.Errorf("expected %t actual %t", tt.expected, result)
+			}
+		})
+	}
+}
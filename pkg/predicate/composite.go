@@ -0,0 +1,133 @@
+package predicate
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// These constants are the names of the composite predicates.
+const (
+	AndPredicateName = "And"
+	OrPredicateName  = "Or"
+	NotPredicateName = "Not"
+)
+
+// Composite is a predicate combining nested predicates. And matches when all of them match, Or when
+// any of them matches, and Not when not all of them match, which negates a single nested predicate.
+//
+// The composite predicates are built by the Factory, since their args hold nested predicates: a
+// predicates list where each item has a name and args, like the predicates of a route.
+type Composite struct {
+	name       string
+	predicates gateway.Predicates
+}
+
+// NewAndPredicate creates a new predicate matching when all the given predicates match.
+func NewAndPredicate(predicates ...gateway.Predicate) *Composite {
+	return &Composite{name: AndPredicateName, predicates: predicates}
+}
+
+// NewOrPredicate creates a new predicate matching when any of the given predicates matches.
+func NewOrPredicate(predicates ...gateway.Predicate) *Composite {
+	return &Composite{name: OrPredicateName, predicates: predicates}
+}
+
+// NewNotPredicate creates a new predicate matching when not all the given predicates match.
+func NewNotPredicate(predicates ...gateway.Predicate) *Composite {
+	return &Composite{name: NotPredicateName, predicates: predicates}
+}
+
+func isComposite(name string) bool {
+	return name == AndPredicateName || name == OrPredicateName || name == NotPredicateName
+}
+
+// buildComposite builds the nested predicates of a composite predicate through the factory, so they
+// may be composite predicates themselves.
+func (f *Factory) buildComposite(name string, args map[string]any) (*Composite, error) {
+	items, ok := args["predicates"].([]any)
+	if !ok || len(items) == 0 {
+		return nil, fmt.Errorf("%w: name %s: failed to convert 'predicates' attribute: %w",
+			ErrInvalidPredicate, name, shared.ErrRequiredSliceValue)
+	}
+	predicates := make(gateway.Predicates, 0, len(items))
+	for idx, item := range items {
+		nested, err := f.buildNested(item)
+		if err != nil {
+			return nil, fmt.Errorf("%w: name %s: predicate %d: %w", ErrInvalidPredicate, name, idx, err)
+		}
+		predicates = append(predicates, nested)
+	}
+	return &Composite{name: name, predicates: predicates}, nil
+}
+
+//nolint:ireturn
+func (f *Factory) buildNested(arg any) (gateway.Predicate, error) {
+	item, err := shared.ConvertToMap(arg)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+	name, err := shared.ConvertToString(item["name"])
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert 'name' attribute: %w", err)
+	}
+	var args map[string]any
+	if item["args"] != nil {
+		if args, err = shared.ConvertToMap(item["args"]); err != nil {
+			return nil, fmt.Errorf("failed to convert 'args' attribute of %s: %w", name, err)
+		}
+	}
+	return f.Build(name, args)
+}
+
+// Test checks if the composite predicate matches the given request.
+func (p *Composite) Test(request *http.Request) bool {
+	var state *gateway.MatchState
+	return p.test(request, &state)
+}
+
+// TestWithState checks if the composite predicate matches the given request, sharing the match state
+// with the nested predicates.
+func (p *Composite) TestWithState(request *http.Request, state *gateway.MatchState) bool {
+	return p.test(request, &state)
+}
+
+// test tests the nested predicates. The path variables captured by an Or branch that does not match
+// or by the predicates a Not negates are dropped: they belong to no match.
+func (p *Composite) test(request *http.Request, state **gateway.MatchState) bool {
+	switch p.name {
+	case OrPredicateName:
+		for _, predicate := range p.predicates {
+			snapshot := (*state).SnapshotPathVariables()
+			if gateway.TestWithState(predicate, request, state) {
+				return true
+			}
+			(*state).RestorePathVariables(snapshot)
+		}
+		return false
+	case NotPredicateName:
+		snapshot := (*state).SnapshotPathVariables()
+		matched := p.predicates.TestAllWithState(request, state)
+		(*state).RestorePathVariables(snapshot)
+		return !matched
+	default:
+		return p.predicates.TestAllWithState(request, state)
+	}
+}
+
+// Name returns the name of the predicate.
+func (p *Composite) Name() string {
+	return p.name
+}
+
+// walkPredicates calls fn with every predicate, the ones nested in composite predicates included.
+func walkPredicates(predicates gateway.Predicates, fn func(predicate gateway.Predicate)) {
+	for _, predicate := range predicates {
+		fn(predicate)
+		if composite, ok := predicate.(*Composite); ok {
+			walkPredicates(composite.predicates, fn)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package predicate_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+func TestFactory_BuildComposite(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+		builderName string
+	}{
+		{
+			name:        "build should succeed when nested predicates are valid",
+			builderName: "Or",
+			args: map[string]any{
+				"predicates": []any{
+					map[string]any{"name": "Path", "args": map[string]any{"patterns": []any{"/a/**"}}},
+					map[string]any{"name": "Not", "args": map[string]any{
+						"predicates": []any{
+							map[string]any{"name": "Host", "args": map[string]any{"patterns": []any{"x.org"}}},
+						},
+					}},
+				},
+			},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should return error when predicates argument is not present",
+			builderName: "And",
+			args:        map[string]any{},
+			expectedErr: errors.New("invalid predicate args: name And: failed to convert 'predicates' attribute: value is required to be a valid slice"),
+		},
+		{
+			name:        "build should return error when nested predicate has no name",
+			builderName: "Or",
+			args: map[string]any{
+				"predicates": []any{map[string]any{"args": map[string]any{}}},
+			},
+			expectedErr: errors.New("invalid predicate args: name Or: predicate 0: failed to convert 'name' attribute: value is required"),
+		},
+		{
+			name:        "build should return error when nested predicate args are not valid",
+			builderName: "Or",
+			args: map[string]any{
+				"predicates": []any{map[string]any{"name": "Path", "args": "/a/**"}},
+			},
+			expectedErr: errors.New("invalid predicate args: name Or: predicate 0: failed to convert 'args' attribute of Path: value is required to be a valid map"),
+		},
+		{
+			name:        "build should return error naming the nested predicate that failed",
+			builderName: "Or",
+			args: map[string]any{
+				"predicates": []any{
+					map[string]any{"name": "Method", "args": map[string]any{"methods": []any{"GET"}}},
+					map[string]any{"name": "Not", "args": map[string]any{
+						"predicates": []any{map[string]any{"name": "Unknown"}},
+					}},
+				},
+			},
+			expectedErr: errors.New("invalid predicate args: name Or: predicate 1: invalid predicate args: name Not: predicate 0: invalid predicate args: name: Unknown"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			factory := predicate.NewFactory(predicate.BuilderRegistry)
+
+			actual, err := factory.Build(tt.builderName, tt.args)
+
+			if fmt.Sprintf("%s", tt.expectedErr) != fmt.Sprintf("%s", err) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual.Name() != tt.builderName {
+				t.Errorf("expected name %s actual %s", tt.builderName, actual.Name())
+			}
+		})
+	}
+}
+
+func TestCompositePredicate_Test(t *testing.T) {
+	pathA := predicate.NewPathPredicate("/a")
+	pathB := predicate.NewPathPredicate("/b")
+	hostX, err := predicate.NewHostPredicate("x.org")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tests := []struct {
+		predicate gateway.Predicate
+		name      string
+		target    string
+		expected  bool
+	}{
+		{
+			name:      "or should match when any predicate matches",
+			predicate: predicate.NewOrPredicate(pathA, pathB),
+			target:    "http://y.org/b",
+			expected:  true,
+		},
+		{
+			name:      "or should not match when no predicate matches",
+			predicate: predicate.NewOrPredicate(pathA, pathB),
+			target:    "http://y.org/c",
+			expected:  false,
+		},
+		{
+			name:      "and should match when all predicates match",
+			predicate: predicate.NewAndPredicate(pathA, hostX),
+			target:    "http://x.org/a",
+			expected:  true,
+		},
+		{
+			name:      "and should not match when a predicate does not match",
+			predicate: predicate.NewAndPredicate(pathA, hostX),
+			target:    "http://y.org/a",
+			expected:  false,
+		},
+		{
+			name:      "not should negate the nested predicate",
+			predicate: predicate.NewNotPredicate(hostX),
+			target:    "http://x.org/a",
+			expected:  false,
+		},
+		{
+			name:      "path a or path b but not from host x should match",
+			predicate: predicate.NewAndPredicate(predicate.NewOrPredicate(pathA, pathB), predicate.NewNotPredicate(hostX)),
+			target:    "http://y.org/b",
+			expected:  true,
+		},
+		{
+			name:      "path a or path b but not from host x should not match host x",
+			predicate: predicate.NewAndPredicate(predicate.NewOrPredicate(pathA, pathB), predicate.NewNotPredicate(hostX)),
+			target:    "http://x.org/b",
+			expected:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+
+			if actual := tt.predicate.Test(req); actual != tt.expected {
+				t.Errorf("expected %v actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestCompositePredicate_TestWithState_PathVariables(t *testing.T) {
+	tenantPath, err := predicate.NewPathTemplatePredicate("/{tenant}/**")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	idPath, err := predicate.NewPathTemplatePredicate("/{id}")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tenantHeader, err := predicate.NewHeaderPredicate("X-Tenant", ".*")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	tests := []struct {
+		predicate         gateway.Predicate
+		headers           http.Header
+		expectedVariables map[string]string
+		name              string
+		target            string
+		expected          bool
+	}{
+		{
+			name: "or should drop the variables of a branch that did not match",
+			predicate: predicate.NewOrPredicate(
+				predicate.NewAndPredicate(tenantPath, tenantHeader),
+				predicate.NewPathPredicate("/public/**")),
+			target:            "/public/x",
+			expected:          true,
+			expectedVariables: nil,
+		},
+		{
+			name: "or should keep the variables of the branch that matched",
+			predicate: predicate.NewOrPredicate(
+				predicate.NewAndPredicate(tenantPath, tenantHeader),
+				predicate.NewPathPredicate("/public/**")),
+			headers:           http.Header{"X-Tenant": {"acme"}},
+			target:            "/acme/x",
+			expected:          true,
+			expectedVariables: map[string]string{"tenant": "acme"},
+		},
+		{
+			name:              "not should drop the variables of the negated predicates",
+			predicate:         predicate.NewNotPredicate(idPath, tenantHeader),
+			target:            "/42",
+			expected:          true,
+			expectedVariables: nil,
+		},
+		{
+			name: "not should keep the variables captured before it",
+			predicate: predicate.NewAndPredicate(
+				idPath,
+				predicate.NewNotPredicate(tenantPath, tenantHeader)),
+			target:            "/42",
+			expected:          true,
+			expectedVariables: map[string]string{"id": "42"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			req.Header = tt.headers
+			if req.Header == nil {
+				req.Header = http.Header{}
+			}
+			state := &gateway.MatchState{}
+
+			actual := gateway.TestWithState(tt.predicate, req, &state)
+
+			if actual != tt.expected {
+				t.Errorf("expected %v actual %v", tt.expected, actual)
+			}
+			if !reflect.DeepEqual(tt.expectedVariables, state.PathVariables()) {
+				t.Errorf("expected variables %v actual %v", tt.expectedVariables, state.PathVariables())
+			}
+		})
+	}
+}
+
+func TestCompositePredicate_SharesWeightGroup(t *testing.T) {
+	v1, _ := predicate.NewWeightPredicate("canary", 1, "", "")
+	v2, _ := predicate.NewWeightPredicate("canary", 1, "", "")
+	routes := gateway.Routes{
+		{ID: "v1", Predicates: gateway.Predicates{predicate.NewOrPredicate(predicate.NewPathPredicate("/none"), v1)}},
+		{ID: "v2", Predicates: gateway.Predicates{predicate.NewAndPredicate(v2)}},
+	}
+	if err := predicate.LinkWeightGroups(routes); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for range 100 {
+		if routes.FindMatching(httptest.NewRequest(http.MethodGet, "/", nil)) == nil {
+			t.Fatal("expected every request to match a route of the nested weight group")
+		}
+	}
+}
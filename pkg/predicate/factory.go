@@ -30,8 +30,14 @@ func NewFactory(registry gateway.PredicateBuilderRegistry) *Factory {
 //
 // The args are expected to be a map of strings to any.
 //
+// The composite predicates (And, Or and Not) are built by the factory itself, which builds their nested
+// predicates recursively. Their errors name the path to the nested predicate that failed to build.
+//
 //nolint:ireturn
 func (f *Factory) Build(name string, args map[string]any) (gateway.Predicate, error) {
+	if isComposite(name) {
+		return f.buildComposite(name, args)
+	}
 	if f.registry[name] != nil {
 		fi, err := f.registry[name].Build(args)
 		if err != nil {
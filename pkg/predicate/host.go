@@ -63,7 +63,30 @@ func (p *Host) Test(request *http.Request) bool {
 	return false
 }
 
+// IndexKeys returns the hosts the patterns match, when all of them are literal hosts.
+func (p *Host) IndexKeys() (gateway.IndexKind, []string) {
+	for _, pattern := range p.patterns {
+		if !isLiteralHost(pattern) {
+			return gateway.IndexHost, nil
+		}
+	}
+	return gateway.IndexHost, p.patterns
+}
+
 // Name returns the name of the predicate.
 func (p *Host) Name() string {
 	return HostPredicateName
 }
+
+// isLiteralHost checks if the pattern only matches itself: it has no wildcard, and none of its characters
+// has a meaning in the compiled regexp.
+func isLiteralHost(pattern string) bool {
+	for i := range len(pattern) {
+		c := pattern[i]
+		if c != '.' && c != '-' && c != ':' && c != '_' && (c < 'a' || c > 'z') && (c < 'A' || c > 'Z') &&
+			(c < '0' || c > '9') {
+			return false
+		}
+	}
+	return pattern != ""
+}
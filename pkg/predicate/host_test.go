@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"testing"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
 )
 
@@ -89,3 +91,30 @@ func TestHostPredicate_Name(t *testing.T) {
 		t.Errorf("expected %s actual %s", predicate.HostPredicateName, p.Name())
 	}
 }
+
+func TestHostPredicate_IndexKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{name: "literal hosts should be indexed", patterns: []string{"example.org", "api.example.org:8080"}, expected: []string{"example.org", "api.example.org:8080"}},
+		{name: "wildcard host should not be indexed", patterns: []string{"example.org", "*.example.org"}, expected: nil},
+		{name: "any host should not be indexed", patterns: []string{"**"}, expected: nil},
+		{name: "host with regexp characters should not be indexed", patterns: []string{"[::1]"}, expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := predicate.NewHostPredicate(tt.patterns...)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			kind, keys := p.IndexKeys()
+
+			if kind != gateway.IndexHost || !slices.Equal(keys, tt.expected) {
+				t.Errorf("expected %v actual %v", tt.expected, keys)
+			}
+		})
+	}
+}
@@ -43,6 +43,11 @@ func (p *Method) Test(r *http.Request) bool {
 	return slices.Contains(p.methods, r.Method)
 }
 
+// IndexKeys returns the methods.
+func (p *Method) IndexKeys() (gateway.IndexKind, []string) {
+	return gateway.IndexMethod, p.methods
+}
+
 // Name returns the name of the predicate.
 func (p *Method) Name() string {
 	return MethodPredicateName
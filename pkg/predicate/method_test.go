@@ -4,8 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"testing"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
 )
 
@@ -87,3 +89,13 @@ func TestMethodPredicate_Name(t *testing.T) {
 		t.Errorf("expected %s actual %s", predicate.MethodPredicateName, p.Name())
 	}
 }
+
+func TestMethodPredicate_IndexKeys(t *testing.T) {
+	p := predicate.NewMethodPredicate(http.MethodGet, http.MethodHead)
+
+	kind, keys := p.IndexKeys()
+
+	if kind != gateway.IndexMethod || !slices.Equal(keys, []string{http.MethodGet, http.MethodHead}) {
+		t.Errorf("expected method keys actual %v", keys)
+	}
+}
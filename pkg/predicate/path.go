@@ -3,6 +3,8 @@ package predicate
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 
 	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
 	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
@@ -16,6 +18,17 @@ type Path struct {
 	patterns []string
 }
 
+// PathTemplate is a path predicate whose patterns capture variables, like '/users/{id}' or
+// '/users/{id:[0-9]+}'. See shared.PathTemplate for the syntax.
+//
+// The variables captured by the pattern matching the request are stored in the gateway context
+// attributes under gateway.PathVariablesAttr, so the filters of the route can reference them as
+// '{name}'.
+type PathTemplate struct {
+	patterns  []string
+	templates []*shared.PathTemplate
+}
+
 // NewPathPredicate creates a new path predicate.
 func NewPathPredicate(patterns ...string) *Path {
 	return &Path{
@@ -23,13 +36,34 @@ func NewPathPredicate(patterns ...string) *Path {
 	}
 }
 
+// NewPathTemplatePredicate creates a new path predicate capturing the variables of the patterns.
+func NewPathTemplatePredicate(patterns ...string) (*PathTemplate, error) {
+	templates := make([]*shared.PathTemplate, 0, len(patterns))
+	for _, pattern := range patterns {
+		template, err := shared.CompilePathTemplate(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build path predicate: %w", err)
+		}
+		templates = append(templates, template)
+	}
+	return &PathTemplate{
+		patterns:  patterns,
+		templates: templates,
+	}, nil
+}
+
 // NewPathPredicateBuilder creates a new path predicate builder.
+//
+// When any of the patterns contains variables, the predicate is a PathTemplate.
 func NewPathPredicateBuilder() gateway.PredicateBuilderFunc {
 	return func(args map[string]any) (gateway.Predicate, error) {
 		patterns, err := shared.ConvertToStringSlice(args["patterns"])
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert 'patterns' attribute: %w", err)
 		}
+		if slices.ContainsFunc(patterns, shared.IsPathTemplate) {
+			return NewPathTemplatePredicate(patterns...)
+		}
 		return NewPathPredicate(patterns...), nil
 	}
 }
@@ -47,7 +81,68 @@ func (p *Path) Test(r *http.Request) bool {
 	return false
 }
 
+// IndexKeys returns the literal paths, or path prefixes, the patterns may match.
+func (p *Path) IndexKeys() (gateway.IndexKind, []string) {
+	return gateway.IndexPath, pathIndexKeys(p.patterns)
+}
+
 // Name returns the name of the predicate.
 func (p *Path) Name() string {
 	return PathPredicateName
 }
+
+// Test checks if the request path matches the given patterns.
+//
+// If the request path does not match any pattern, the predicate will return false.
+// If the request path matches at least one pattern, the predicate will return true.
+func (p *PathTemplate) Test(r *http.Request) bool {
+	for _, template := range p.templates {
+		if _, ok := template.Match(r.URL.Path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// TestWithState checks if the request path matches the given patterns, storing the variables captured
+// by the first matching pattern in the match state.
+func (p *PathTemplate) TestWithState(r *http.Request, state *gateway.MatchState) bool {
+	for _, template := range p.templates {
+		if variables, ok := template.Match(r.URL.Path); ok {
+			state.SetPathVariables(variables)
+			return true
+		}
+	}
+	return false
+}
+
+// IndexKeys returns the literal paths, or path prefixes, the patterns may match.
+func (p *PathTemplate) IndexKeys() (gateway.IndexKind, []string) {
+	return gateway.IndexPath, pathIndexKeys(p.patterns)
+}
+
+// Name returns the name of the predicate.
+func (p *PathTemplate) Name() string {
+	return PathPredicateName
+}
+
+// pathIndexKeys returns the index key of each pattern: the pattern itself when it is a literal path, or
+// its literal segments before the first wildcard or variable, followed by '/**'.
+func pathIndexKeys(patterns []string) []string {
+	keys := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		segments := strings.Split(strings.Trim(pattern, "/"), "/")
+		literal := slices.IndexFunc(segments, func(segment string) bool {
+			return strings.ContainsAny(segment, "*?{")
+		})
+		switch {
+		case literal == -1:
+			keys = append(keys, pattern)
+		case literal == 0:
+			keys = append(keys, "/**")
+		default:
+			keys = append(keys, "/"+strings.Join(segments[:literal], "/")+"/**")
+		}
+	}
+	return keys
+}
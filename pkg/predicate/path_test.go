@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"slices"
 	"testing"
 
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
 )
 
@@ -22,11 +25,25 @@ func TestNewPathPredicateBuilder(t *testing.T) {
 			},
 			expectedErr: nil,
 		},
+		{
+			name: "build should succeed when patterns contain variables",
+			args: map[string]any{
+				"patterns": []any{"/users/{id:[0-9]+}", "/**"},
+			},
+			expectedErr: nil,
+		},
 		{
 			name:        "build should fail when patterns argument is not valid",
 			args:        map[string]any{},
 			expectedErr: errors.New("failed to convert 'patterns' attribute: value is required"),
 		},
+		{
+			name: "build should fail when pattern variables are not valid",
+			args: map[string]any{
+				"patterns": []any{"/users/{id"},
+			},
+			expectedErr: errors.New("failed to build path predicate: invalid path template: /users/{id: unbalanced braces"),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -87,3 +104,94 @@ func TestPathPredicate_Name(t *testing.T) {
 		t.Errorf("expected %s actual %s", predicate.PathPredicateName, p.Name())
 	}
 }
+
+func TestPathTemplate_TestWithState(t *testing.T) {
+	tests := []struct {
+		expectedVariables map[string]string
+		name              string
+		reqPath           string
+		patterns          []string
+		expected          bool
+	}{
+		{
+			name:              "path should match and capture variables",
+			reqPath:           "/users/42/orders/7",
+			patterns:          []string{"/users/{id}/orders/{order:[0-9]+}"},
+			expected:          true,
+			expectedVariables: map[string]string{"id": "42", "order": "7"},
+		},
+		{
+			name:              "path should capture variables of the first matching pattern",
+			reqPath:           "/users/42",
+			patterns:          []string{"/accounts/{account}", "/users/{user}", "/**"},
+			expected:          true,
+			expectedVariables: map[string]string{"user": "42"},
+		},
+		{
+			name:              "path should match glob pattern without variables",
+			reqPath:           "/health",
+			patterns:          []string{"/users/{user}", "/health"},
+			expected:          true,
+			expectedVariables: map[string]string{},
+		},
+		{
+			name:              "path should not match",
+			reqPath:           "/users/me",
+			patterns:          []string{"/users/{id:[0-9]+}"},
+			expected:          false,
+			expectedVariables: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred, err := predicate.NewPathTemplatePredicate(tt.patterns...)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			req, _ := http.NewRequestWithContext(t.Context(), http.MethodGet, tt.reqPath, nil)
+			state := &gateway.MatchState{}
+
+			actual := pred.TestWithState(req, state)
+
+			if tt.expected != actual || tt.expected != pred.Test(req) {
+				t.Errorf("expected %t actual %t", tt.expected, actual)
+			}
+			if !reflect.DeepEqual(tt.expectedVariables, state.PathVariables()) {
+				t.Errorf("expected variables %v actual %v", tt.expectedVariables, state.PathVariables())
+			}
+			if pred.Name() != predicate.PathPredicateName {
+				t.Errorf("expected %s actual %s", predicate.PathPredicateName, pred.Name())
+			}
+		})
+	}
+}
+
+func TestPathPredicate_IndexKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		expected []string
+	}{
+		{name: "literal paths should be indexed as they are", patterns: []string{"/users", "/"}, expected: []string{"/users", "/"}},
+		{name: "glob paths should be indexed by literal prefix", patterns: []string{"/api/users/**", "/api/*/items", "/a?c"}, expected: []string{"/api/users/**", "/api/**", "/**"}},
+		{name: "template paths should be indexed by literal prefix", patterns: []string{"/users/{id}/orders", "/{tenant}/users"}, expected: []string{"/users/**", "/**"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pathTemplate, err := predicate.NewPathTemplatePredicate(tt.patterns...)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+
+			kind, keys := predicate.NewPathPredicate(tt.patterns...).IndexKeys()
+			templateKind, templateKeys := pathTemplate.IndexKeys()
+
+			if kind != gateway.IndexPath || !slices.Equal(keys, tt.expected) {
+				t.Errorf("expected %v actual %v", tt.expected, keys)
+			}
+			if templateKind != gateway.IndexPath || !slices.Equal(templateKeys, tt.expected) {
+				t.Errorf("expected template keys %v actual %v", tt.expected, templateKeys)
+			}
+		})
+	}
+}
@@ -6,13 +6,15 @@ import "github.com/drathveloper/go-cloud-gateway/pkg/gateway"
 //
 //nolint:gochecknoglobals
 var BuilderRegistry gateway.PredicateBuilderRegistry = map[string]gateway.PredicateBuilder{
-	MethodPredicateName:  NewMethodPredicateBuilder(),
-	HostPredicateName:    NewHostPredicateBuilder(),
-	PathPredicateName:    NewPathPredicateBuilder(),
-	QueryPredicateName:   NewQueryPredicateBuilder(),
-	HeaderPredicateName:  NewHeaderPredicateBuilder(),
-	CookiePredicateName:  NewCookiePredicateBuilder(),
-	BeforePredicateName:  NewBeforePredicateBuilder(),
-	AfterPredicateName:   NewAfterPredicateBuilder(),
-	BetweenPredicateName: NewBetweenPredicateBuilder(),
+	MethodPredicateName:     NewMethodPredicateBuilder(),
+	HostPredicateName:       NewHostPredicateBuilder(),
+	PathPredicateName:       NewPathPredicateBuilder(),
+	QueryPredicateName:      NewQueryPredicateBuilder(),
+	HeaderPredicateName:     NewHeaderPredicateBuilder(),
+	CookiePredicateName:     NewCookiePredicateBuilder(),
+	BeforePredicateName:     NewBeforePredicateBuilder(),
+	AfterPredicateName:      NewAfterPredicateBuilder(),
+	BetweenPredicateName:    NewBetweenPredicateBuilder(),
+	WeightPredicateName:     NewWeightPredicateBuilder(),
+	RemoteAddrPredicateName: NewRemoteAddrPredicateBuilder(),
 }
@@ -0,0 +1,83 @@
+package predicate
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// RemoteAddrPredicateName is the name of the remote address predicate.
+const RemoteAddrPredicateName = "RemoteAddr"
+
+// RemoteAddr is a predicate that checks if the client address belongs to any of the given
+// networks, IPv4 or IPv6.
+//
+// By default, the client address is resolved by shared.GetRemoteAddr, which trusts the
+// X-Forwarded-For and X-Real-Ip headers. When the gateway is not behind a trusted proxy, the peer
+// of the connection must be used instead, since the client chooses those headers.
+type RemoteAddr struct {
+	sources *shared.CIDRSet
+	usePeer bool
+}
+
+// NewRemoteAddrPredicate creates a new remote address predicate. The sources are CIDRs or single
+// IP addresses.
+func NewRemoteAddrPredicate(sources []string, usePeer bool) (*RemoteAddr, error) {
+	set, err := shared.ParseCIDRSet(sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build remote addr predicate: %w", err)
+	}
+	return &RemoteAddr{
+		sources: set,
+		usePeer: usePeer,
+	}, nil
+}
+
+// NewRemoteAddrPredicateBuilder creates a new remote address predicate builder.
+//
+// The args are expected to contain the following keys:
+// - sources: the list of CIDRs or IP addresses.
+// - use-peer: optional, match the connection peer instead of the resolved client address (default false).
+func NewRemoteAddrPredicateBuilder() gateway.PredicateBuilderFunc {
+	return func(args map[string]any) (gateway.Predicate, error) {
+		sources, err := shared.ConvertToStringSlice(args["sources"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'sources' attribute: %w", err)
+		}
+		usePeer, err := convertOptionalBool(args, "use-peer")
+		if err != nil {
+			return nil, err
+		}
+		return NewRemoteAddrPredicate(sources, usePeer)
+	}
+}
+
+// Test checks if the remote address predicate matches the given request.
+//
+// If the client address cannot be parsed, the predicate will return false.
+func (p *RemoteAddr) Test(request *http.Request) bool {
+	remoteAddr := request.RemoteAddr
+	if !p.usePeer {
+		remoteAddr = shared.GetRemoteAddr(request)
+	}
+	addr, ok := shared.ParseRemoteAddr(remoteAddr)
+	return ok && p.sources.Contains(addr)
+}
+
+// Name returns the name of the predicate.
+func (p *RemoteAddr) Name() string {
+	return RemoteAddrPredicateName
+}
+
+func convertOptionalBool(args map[string]any, name string) (bool, error) {
+	if args[name] == nil {
+		return false, nil
+	}
+	value, err := shared.ConvertToBool(args[name])
+	if err != nil {
+		return false, fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
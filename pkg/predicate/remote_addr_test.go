@@ -0,0 +1,134 @@
+package predicate_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+func TestNewRemoteAddrPredicateBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"sources": []any{"10.0.0.0/8", "2001:db8::/32"}, "use-peer": true},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when use peer argument is not present",
+			args:        map[string]any{"sources": []any{"192.168.0.1"}},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when sources argument is not present",
+			args:        map[string]any{},
+			expectedErr: errors.New("failed to convert 'sources' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when use peer argument is not valid",
+			args:        map[string]any{"sources": []any{"10.0.0.0/8"}, "use-peer": "maybe"},
+			expectedErr: errors.New("failed to convert 'use-peer' attribute: value is required to be a valid bool"),
+		},
+		{
+			name:        "build should fail when a source is not valid",
+			args:        map[string]any{"sources": []any{"10.0.0.0/40"}},
+			expectedErr: errors.New("failed to build remote addr predicate: invalid CIDR: 10.0.0.0/40"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := predicate.NewRemoteAddrPredicateBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func TestRemoteAddrPredicate_Test(t *testing.T) {
+	tests := []struct {
+		header     http.Header
+		name       string
+		remoteAddr string
+		usePeer    bool
+		expected   bool
+	}{
+		{
+			name:       "test should match when peer belongs to a source",
+			header:     http.Header{},
+			remoteAddr: "10.1.2.3:4321",
+			expected:   true,
+		},
+		{
+			name:       "test should match when ipv6 peer belongs to a source",
+			header:     http.Header{},
+			remoteAddr: "[2001:db8::7]:4321",
+			expected:   true,
+		},
+		{
+			name:       "test should not match when peer does not belong to any source",
+			header:     http.Header{},
+			remoteAddr: "11.1.2.3:4321",
+			expected:   false,
+		},
+		{
+			name:       "test should match when forwarded client belongs to a source",
+			header:     http.Header{"X-Forwarded-For": {"10.1.2.3, 172.16.0.1"}},
+			remoteAddr: "172.16.0.1:4321",
+			expected:   true,
+		},
+		{
+			name:       "test should not match when forwarded client belongs to a source but peer is used",
+			header:     http.Header{"X-Forwarded-For": {"10.1.2.3"}},
+			remoteAddr: "172.16.0.1:4321",
+			usePeer:    true,
+			expected:   false,
+		},
+		{
+			name:       "test should match when peer belongs to a source and forwarded client is ignored",
+			header:     http.Header{"X-Forwarded-For": {"172.16.0.1"}},
+			remoteAddr: "10.1.2.3:4321",
+			usePeer:    true,
+			expected:   true,
+		},
+		{
+			name:       "test should not match when remote address cannot be parsed",
+			header:     http.Header{},
+			remoteAddr: "unknown",
+			expected:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := predicate.NewRemoteAddrPredicate([]string{"10.0.0.0/8", "2001:db8::/32"}, tt.usePeer)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			req := &http.Request{Header: tt.header, RemoteAddr: tt.remoteAddr}
+
+			if actual := p.Test(req); actual != tt.expected {
+				t.Errorf("expected %v actual %v", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRemoteAddrPredicate_Name(t *testing.T) {
+	p, err := predicate.NewRemoteAddrPredicate(nil, false)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if name := p.Name(); name != predicate.RemoteAddrPredicateName {
+		t.Errorf("expected name %s actual %s", predicate.RemoteAddrPredicateName, name)
+	}
+}
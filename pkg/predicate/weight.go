@@ -0,0 +1,213 @@
+package predicate
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+
+	"github.com/drathveloper/go-cloud-gateway/internal/pkg/shared"
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+)
+
+// WeightPredicateName is the name of the weight predicate.
+const WeightPredicateName = "Weight"
+
+// ErrInvalidWeightGroup is returned when the weight predicates of a group cannot be linked together.
+var ErrInvalidWeightGroup = errors.New("invalid weight group")
+
+const (
+	fnvOffset64 = 14695981039346656037
+	fnvPrime64  = 1099511628211
+)
+
+// Weight is a predicate that splits the traffic between the routes of a group by weight. A route with
+// weight 95 and another with weight 5 in the same group get 95% and 5% of the requests.
+//
+// The route of the group is chosen once per request, when the first predicate of the group is tested.
+// The choice is random unless the group hashes a header or a cookie: the requests with the same value
+// then always get the same route, as long as the group does not change.
+//
+// The predicates of a group are linked by LinkWeightGroups. Until then, a weight predicate always matches.
+type Weight struct {
+	group      *weightGroup
+	groupName  string
+	hashHeader string
+	hashCookie string
+	weight     int
+}
+
+type weightGroup struct {
+	name       string
+	hashHeader string
+	hashCookie string
+	members    []*Weight
+	total      uint64
+}
+
+// NewWeightPredicate creates a new weight predicate. The hash header and cookie are optional, and at
+// most one of them may be set.
+func NewWeightPredicate(group string, weight int, hashHeader, hashCookie string) (*Weight, error) {
+	if group == "" {
+		return nil, fmt.Errorf("%w: group is required", ErrInvalidWeightGroup)
+	}
+	if weight < 0 {
+		return nil, fmt.Errorf("%w: group %s: negative weight %d", ErrInvalidWeightGroup, group, weight)
+	}
+	if hashHeader != "" && hashCookie != "" {
+		return nil, fmt.Errorf("%w: group %s: hash header and hash cookie are exclusive", ErrInvalidWeightGroup, group)
+	}
+	return &Weight{
+		groupName:  group,
+		weight:     weight,
+		hashHeader: hashHeader,
+		hashCookie: hashCookie,
+	}, nil
+}
+
+// NewWeightPredicateBuilder creates a new weight predicate builder.
+func NewWeightPredicateBuilder() gateway.PredicateBuilderFunc {
+	return func(args map[string]any) (gateway.Predicate, error) {
+		group, err := shared.ConvertToString(args["group"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'group' attribute: %w", err)
+		}
+		weight, err := shared.ConvertToInt(args["weight"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert 'weight' attribute: %w", err)
+		}
+		hashHeader, err := convertOptionalString(args, "hash-header")
+		if err != nil {
+			return nil, err
+		}
+		hashCookie, err := convertOptionalString(args, "hash-cookie")
+		if err != nil {
+			return nil, err
+		}
+		return NewWeightPredicate(group, weight, hashHeader, hashCookie)
+	}
+}
+
+// LinkWeightGroups links the weight predicates of the routes into their groups, in route order, the ones
+// nested in composite predicates included. It must be called once all the routes are built, before they
+// match any request.
+//
+// The predicates of a group must agree on the hash header and cookie, and their total weight must be
+// positive.
+func LinkWeightGroups(routes gateway.Routes) error {
+	groups := make(map[string]*weightGroup)
+	var order []*weightGroup
+	var err error
+	for i := range routes {
+		walkPredicates(routes[i].Predicates, func(predicate gateway.Predicate) {
+			weight, ok := predicate.(*Weight)
+			if !ok || err != nil {
+				return
+			}
+			group, exists := groups[weight.groupName]
+			if !exists {
+				group = &weightGroup{name: weight.groupName, hashHeader: weight.hashHeader, hashCookie: weight.hashCookie}
+				groups[weight.groupName] = group
+				order = append(order, group)
+			}
+			if group.hashHeader != weight.hashHeader || group.hashCookie != weight.hashCookie {
+				err = fmt.Errorf("%w: group %s: route %s hashes a different key", ErrInvalidWeightGroup,
+					group.name, routes[i].ID)
+				return
+			}
+			group.members = append(group.members, weight)
+			group.total += uint64(weight.weight)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, group := range order {
+		if group.total == 0 {
+			return fmt.Errorf("%w: group %s: total weight is 0", ErrInvalidWeightGroup, group.name)
+		}
+		for _, member := range group.members {
+			member.group = group
+		}
+	}
+	return nil
+}
+
+// Test checks if the weight predicate matches the given request.
+//
+// Tested alone, the route of the group is chosen for this call only. Routes.FindMatching shares the
+// choice across the routes of the group.
+func (p *Weight) Test(request *http.Request) bool {
+	return p.TestWithState(request, &gateway.MatchState{})
+}
+
+// TestWithState checks if the route of the group chosen for the request is the one of the predicate.
+func (p *Weight) TestWithState(request *http.Request, state *gateway.MatchState) bool {
+	if p.group == nil {
+		return true
+	}
+	chosen, ok := state.Load(p.group)
+	if !ok {
+		chosen = p.group.choose(request)
+		state.Store(p.group, chosen)
+	}
+	return chosen == p
+}
+
+// Name returns the name of the predicate.
+func (p *Weight) Name() string {
+	return WeightPredicateName
+}
+
+func (g *weightGroup) choose(request *http.Request) *Weight {
+	roll := g.roll(request)
+	var cumulative uint64
+	for _, member := range g.members {
+		cumulative += uint64(member.weight)
+		if roll < cumulative {
+			return member
+		}
+	}
+	return g.members[len(g.members)-1]
+}
+
+func (g *weightGroup) roll(request *http.Request) uint64 {
+	var key string
+	switch {
+	case g.hashHeader != "":
+		key = request.Header.Get(g.hashHeader)
+	case g.hashCookie != "":
+		if cookie, err := request.Cookie(g.hashCookie); err == nil {
+			key = cookie.Value
+		}
+	default:
+	}
+	if key == "" {
+		return rand.Uint64N(g.total) //nolint:gosec // traffic splitting needs no secure randomness
+	}
+	return hashKey(g.name, key) % g.total
+}
+
+// hashKey returns the FNV-1a hash of the group name and the key, so the same key lands on unrelated
+// routes in different groups.
+func hashKey(group, key string) uint64 {
+	hash := uint64(fnvOffset64)
+	for _, part := range []string{group, "\x00", key} {
+		for i := range len(part) {
+			hash ^= uint64(part[i])
+			hash *= fnvPrime64
+		}
+	}
+	return hash
+}
+
+func convertOptionalString(args map[string]any, name string) (string, error) {
+	if args[name] == nil {
+		return "", nil
+	}
+	value, err := shared.ConvertToString(args[name])
+	if err != nil {
+		return "", fmt.Errorf("failed to convert '%s' attribute: %w", name, err)
+	}
+	return value, nil
+}
@@ -0,0 +1,175 @@
+package predicate_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/drathveloper/go-cloud-gateway/pkg/gateway"
+	"github.com/drathveloper/go-cloud-gateway/pkg/predicate"
+)
+
+func TestNewWeightPredicateBuilder(t *testing.T) {
+	tests := []struct {
+		expectedErr error
+		args        map[string]any
+		name        string
+	}{
+		{
+			name:        "build should succeed when args are present and are valid",
+			args:        map[string]any{"group": "canary", "weight": 95},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should succeed when hash header is present",
+			args:        map[string]any{"group": "canary", "weight": "5", "hash-header": "X-User-ID"},
+			expectedErr: nil,
+		},
+		{
+			name:        "build should fail when group argument is not present",
+			args:        map[string]any{"weight": 95},
+			expectedErr: errors.New("failed to convert 'group' attribute: value is required"),
+		},
+		{
+			name:        "build should fail when weight argument is not valid",
+			args:        map[string]any{"group": "canary", "weight": "high"},
+			expectedErr: errors.New("failed to convert 'weight' attribute: value is required to be a valid int"),
+		},
+		{
+			name:        "build should fail when hash cookie argument is not valid",
+			args:        map[string]any{"group": "canary", "weight": 95, "hash-cookie": 1},
+			expectedErr: errors.New("failed to convert 'hash-cookie' attribute: value is required to be a valid string"),
+		},
+		{
+			name:        "build should fail when weight is negative",
+			args:        map[string]any{"group": "canary", "weight": -1},
+			expectedErr: errors.New("invalid weight group: group canary: negative weight -1"),
+		},
+		{
+			name:        "build should fail when both hash header and hash cookie are present",
+			args:        map[string]any{"group": "canary", "weight": 1, "hash-header": "X-User-ID", "hash-cookie": "session"},
+			expectedErr: errors.New("invalid weight group: group canary: hash header and hash cookie are exclusive"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := predicate.NewWeightPredicateBuilder().Build(tt.args)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+			if err == nil && actual == nil {
+				t.Errorf("expected %v to be present", actual)
+			}
+		})
+	}
+}
+
+func newWeightRoutes(t *testing.T, hashHeader string, weights ...int) gateway.Routes {
+	t.Helper()
+	routes := make(gateway.Routes, 0, len(weights))
+	for i, weight := range weights {
+		weightPredicate, err := predicate.NewWeightPredicate("canary", weight, hashHeader, "")
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		routes = append(routes, gateway.Route{ID: "v" + strconv.Itoa(i+1), Predicates: gateway.Predicates{weightPredicate}})
+	}
+	if err := predicate.LinkWeightGroups(routes); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return routes
+}
+
+func TestLinkWeightGroups(t *testing.T) {
+	newRoute := func(id, group string, weight int, hashHeader string) gateway.Route {
+		weightPredicate, _ := predicate.NewWeightPredicate(group, weight, hashHeader, "")
+		return gateway.Route{ID: id, Predicates: gateway.Predicates{weightPredicate}}
+	}
+	tests := []struct {
+		expectedErr error
+		name        string
+		routes      gateway.Routes
+	}{
+		{
+			name:        "link should succeed when groups are valid",
+			routes:      gateway.Routes{newRoute("v1", "a", 95, ""), newRoute("v2", "a", 5, ""), newRoute("v3", "b", 0, ""), newRoute("v4", "b", 1, "")},
+			expectedErr: nil,
+		},
+		{
+			name:        "link should fail when total weight of a group is 0",
+			routes:      gateway.Routes{newRoute("v1", "a", 0, ""), newRoute("v2", "a", 0, "")},
+			expectedErr: errors.New("invalid weight group: group a: total weight is 0"),
+		},
+		{
+			name:        "link should fail when routes of a group hash different keys",
+			routes:      gateway.Routes{newRoute("v1", "a", 1, "X-User-ID"), newRoute("v2", "a", 1, "X-Session-ID")},
+			expectedErr: errors.New("invalid weight group: group a: route v2 hashes a different key"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := predicate.LinkWeightGroups(tt.routes)
+
+			if fmt.Sprintf("%s", err) != fmt.Sprintf("%s", tt.expectedErr) {
+				t.Errorf("expected err %s actual %s", tt.expectedErr, err)
+			}
+		})
+	}
+}
+
+func TestWeightPredicate_FindMatching(t *testing.T) {
+	const requests = 10000
+	routes := newWeightRoutes(t, "", 90, 10, 0)
+	hits := map[string]int{}
+	for range requests {
+		route := routes.FindMatching(&http.Request{Header: http.Header{}})
+		if route == nil {
+			t.Fatal("expected every request to match a route of the group")
+		}
+		hits[route.ID]++
+	}
+	if hits["v3"] != 0 {
+		t.Errorf("expected no request for weight 0 route, actual %d", hits["v3"])
+	}
+	if share := hits["v2"] * 100 / requests; share < 7 || share > 13 {
+		t.Errorf("expected about 10%% of requests for v2, actual %d%%", share)
+	}
+}
+
+func TestWeightPredicate_StickyHashHeader(t *testing.T) {
+	routes := newWeightRoutes(t, "X-User-ID", 50, 50)
+	seen := map[string]bool{}
+	for user := range 50 {
+		req := &http.Request{Header: http.Header{"X-User-Id": {"user-" + strconv.Itoa(user)}}}
+		first := routes.FindMatching(req)
+		for range 10 {
+			if route := routes.FindMatching(req); route.ID != first.ID {
+				t.Fatalf("expected user %d to stay on route %s, actual %s", user, first.ID, route.ID)
+			}
+		}
+		seen[first.ID] = true
+	}
+	if !seen["v1"] || !seen["v2"] {
+		t.Errorf("expected users spread over both routes, actual %v", seen)
+	}
+}
+
+func TestWeightPredicate_Test(t *testing.T) {
+	unlinked, err := predicate.NewWeightPredicate("canary", 0, "", "")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !unlinked.Test(&http.Request{}) {
+		t.Error("expected unlinked weight predicate to match")
+	}
+	routes := newWeightRoutes(t, "", 1, 0)
+	if !routes[0].Predicates[0].Test(&http.Request{}) || routes[1].Predicates[0].Test(&http.Request{}) {
+		t.Error("expected only the weighted route to match")
+	}
+	if name := unlinked.Name(); name != predicate.WeightPredicateName {
+		t.Errorf("expected name %s actual %s", predicate.WeightPredicateName, name)
+	}
+}